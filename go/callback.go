@@ -0,0 +1,110 @@
+package tuish
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// callbackPage is shown in the browser once the checkout redirects back.
+const callbackPage = `<!doctype html>
+<html><head><meta charset="utf-8"><title>Purchase complete</title></head>
+<body><p>Purchase complete. You can close this tab and return to your terminal.</p></body></html>
+`
+
+// WithCallbackTimeout sets how long PurchaseWithCallback listens for the
+// browser to return (default: 5m). Polling carries on after it until the
+// checkout settles or WithTimeout expires.
+func WithCallbackTimeout(timeout time.Duration) CheckoutWaitOption {
+	return func(o *checkoutWaitOptions) {
+		if timeout > 0 {
+			o.callbackTimeout = timeout
+		}
+	}
+}
+
+// PurchaseWithCallback opens a browser checkout that redirects back to a
+// short-lived listener on localhost, so the purchase completes as soon as the
+// browser returns instead of on the next poll. It polls as WaitForCheckout
+// does while it listens, so a callback that never arrives costs nothing, and
+// WithTimeout bounds the whole wait. If no local port can be opened it only
+// polls. The callback only triggers a status check; the license always comes
+// from the API.
+func (s *SDK) PurchaseWithCallback(ctx context.Context, email string, opts ...CheckoutWaitOption) (*LicenseCheckResult, error) {
+	o := newCheckoutWaitOptions(opts)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		s.logf("callback listener unavailable, polling instead: %v", err)
+		session, err := s.PurchaseInBrowser(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		return s.WaitForCheckout(ctx, session.SessionID, opts...)
+	}
+
+	state, err := callbackState()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	callbacks := make(chan struct{}, 1)
+	server := &http.Server{
+		Handler:           callbackHandler(state, callbacks),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(listener)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	returnURL := fmt.Sprintf("http://%s/callback/%s", listener.Addr(), state)
+	session, err := s.client.CreateCheckoutSessionWithReturn(ctx, s.config.ProductID, email, returnURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := openBrowser(session.CheckoutURL); err != nil {
+		s.logf("open browser: %v", err)
+	}
+
+	// Stop listening after the callback timeout; polling carries on
+	stopListening := time.AfterFunc(o.callbackTimeout, func() {
+		s.logf("no checkout callback after %s, polling only", o.callbackTimeout)
+		server.Close()
+	})
+	defer stopListening.Stop()
+
+	return s.waitForCheckout(ctx, session.SessionID, o, callbacks)
+}
+
+// callbackHandler serves the checkout return URL, signalling callbacks when
+// the browser arrives with the expected state.
+func callbackHandler(state string, callbacks chan<- struct{}) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback/"+state, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case callbacks <- struct{}{}:
+		default:
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, callbackPage)
+	})
+	return mux
+}
+
+// callbackState returns a random path segment so other local processes
+// can't guess the callback URL.
+func callbackState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate callback state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,241 @@
+package tuish
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newCallbackTestServer serves checkout init and status, recording the
+// return URL and counting status requests.
+func newCallbackTestServer(t *testing.T, license string) (*httptest.Server, func() (string, int)) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var returnURL string
+	statusCalls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/v1/checkout/init":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			returnURL = body["returnUrl"]
+			json.NewEncoder(w).Encode(map[string]any{
+				"sessionId":   "sess_123",
+				"checkoutUrl": "https://checkout.example.com/sess_123",
+			})
+		case strings.HasPrefix(r.URL.Path, "/v1/checkout/status/"):
+			statusCalls++
+			json.NewEncoder(w).Encode(map[string]any{"status": "complete", "licenseKey": license})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() (string, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return returnURL, statusCalls
+	}
+}
+
+func stubBrowser(t *testing.T, open func(url string) error) {
+	t.Helper()
+	original := openBrowser
+	openBrowser = open
+	t.Cleanup(func() { openBrowser = original })
+}
+
+func TestSDKPurchaseWithCallback(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_callback",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	server, recorded := newCallbackTestServer(t, license)
+
+	// The browser finishes checkout and follows the return URL
+	pages := make(chan string, 1)
+	stubBrowser(t, func(url string) error {
+		returnURL, _ := recorded()
+		go func() {
+			resp, err := http.Get(returnURL + "?session_id=sess_123")
+			if err != nil {
+				pages <- err.Error()
+				return
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			pages <- string(body)
+		}()
+		return nil
+	})
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	// Polling would take an hour, so only the callback can finish in time
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := sdk.PurchaseWithCallback(ctx, "", WithPollInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("PurchaseWithCallback failed: %v", err)
+	}
+	if !result.Valid || result.License.ID != "lic_callback" {
+		t.Fatalf("expected completed license, got %+v", result)
+	}
+
+	returnURL, statusCalls := recorded()
+	if !strings.HasPrefix(returnURL, "http://127.0.0.1:") {
+		t.Errorf("expected a localhost return URL, got %q", returnURL)
+	}
+	if statusCalls != 1 {
+		t.Errorf("expected one status check after the callback, got %d", statusCalls)
+	}
+	if sdk.GetCachedLicenseKey() != license {
+		t.Error("expected license to be stored")
+	}
+	if page := <-pages; !strings.Contains(page, "return to your terminal") {
+		t.Errorf("expected completion page, got %q", page)
+	}
+
+	// The listener is shut down once the purchase resolves
+	if _, err := http.Get(returnURL); err == nil {
+		t.Error("expected callback listener to be closed")
+	}
+}
+
+func TestSDKPurchaseWithCallbackFallsBackToPolling(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_callback",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	server, recorded := newCallbackTestServer(t, license)
+	stubBrowser(t, func(string) error { return nil })
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	result, err := sdk.PurchaseWithCallback(context.Background(), "",
+		WithCallbackTimeout(20*time.Millisecond),
+		WithPollInterval(5*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("PurchaseWithCallback failed: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expected completed license from polling, got %+v", result)
+	}
+	if _, statusCalls := recorded(); statusCalls == 0 {
+		t.Error("expected polling after the callback timeout")
+	}
+}
+
+func TestSDKPurchaseWithCallbackPollsWhileListening(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_callback",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	server, _ := newCallbackTestServer(t, license)
+	stubBrowser(t, func(string) error { return nil })
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	// The browser never returns, but polling doesn't wait for the listener
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := sdk.PurchaseWithCallback(ctx, "",
+		WithCallbackTimeout(time.Hour),
+		WithPollInterval(5*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("PurchaseWithCallback failed: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expected completed license from polling, got %+v", result)
+	}
+}
+
+func TestSDKPurchaseWithCallbackSingleTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/checkout/init" {
+			json.NewEncoder(w).Encode(map[string]any{
+				"sessionId":   "sess_123",
+				"checkoutUrl": "https://checkout.example.com/sess_123",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"status": "pending"})
+	}))
+	defer server.Close()
+	stubBrowser(t, func(string) error { return nil })
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	// WithTimeout bounds the whole call, including time spent listening
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := sdk.PurchaseWithCallback(ctx, "",
+		WithCallbackTimeout(time.Hour),
+		WithPollInterval(5*time.Millisecond),
+		WithTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("PurchaseWithCallback failed: %v", err)
+	}
+	if result.Valid || result.Reason != ReasonNetworkError {
+		t.Errorf("expected a timed out wait, got %+v", result)
+	}
+}
+
+func TestCallbackHandlerRejectsWrongState(t *testing.T) {
+	callbacks := make(chan struct{}, 1)
+	server := httptest.NewServer(callbackHandler("expected", callbacks))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/callback/guessed")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for the wrong state, got %d", resp.StatusCode)
+	}
+	select {
+	case <-callbacks:
+		t.Error("expected no callback for the wrong state")
+	default:
+	}
+}
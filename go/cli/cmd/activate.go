@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var activateCmd = &cobra.Command{
+	Use:   "activate <license-key|->",
+	Short: "Verify and store a license key on this machine (use - to read stdin)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := readLicenseKey(args)
+		if err != nil {
+			return err
+		}
+
+		sdk, err := newLicenseSDK()
+		if err != nil {
+			return err
+		}
+
+		details, err := previewLicense(sdk, key)
+		if err != nil {
+			return err
+		}
+		if err := sdk.StoreLicense(key); err != nil {
+			return fmt.Errorf("store license: %w", err)
+		}
+
+		if outputJSON {
+			return writeJSON(cmd.OutOrStdout(), licenseOutput{Valid: true, License: details})
+		}
+
+		fmt.Println(successStyle.Render("License activated."))
+		printLicenseDetails(details)
+		return nil
+	},
+}
+
+func init() {
+	addLicenseFlags(activateCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Show the license state on this machine, for support requests",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sdk, err := newLicenseSDK()
+		if err != nil {
+			return err
+		}
+
+		d := sdk.Diagnose()
+		if outputJSON {
+			return writeJSON(cmd.OutOrStdout(), d)
+		}
+
+		fmt.Println(titleStyle.Render("Tuish doctor"))
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("SDK version: %s", d.SDKVersion)))
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("Product: %s", d.ProductID)))
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("API URL: %s", d.APIBaseURL)))
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("Fingerprint: %s", d.Fingerprint)))
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("Storage: %s", d.StorageDir)))
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("License in environment: %t", d.EnvLicense)))
+
+		switch {
+		case d.Cache.Error != "":
+			fmt.Println(warnStyle.Render(fmt.Sprintf("Cache unreadable: %s", d.Cache.Error)))
+			return nil
+		case !d.Cache.Exists:
+			fmt.Println(warnStyle.Render("No cached license"))
+		default:
+			fmt.Println(mutedStyle.Render(fmt.Sprintf("License key: %s", d.Cache.LicenseKey)))
+			fmt.Println(mutedStyle.Render(fmt.Sprintf("Cached: %s", time.UnixMilli(d.Cache.CachedAt).Format(time.RFC3339))))
+			fmt.Println(mutedStyle.Render(fmt.Sprintf("Refresh due: %s (needs refresh: %t)",
+				time.UnixMilli(d.Cache.RefreshAt).Format(time.RFC3339), d.Cache.NeedsRefresh)))
+		}
+
+		if d.Check.Valid {
+			fmt.Println(successStyle.Render("License is valid."))
+		} else {
+			fmt.Println(warnStyle.Render(fmt.Sprintf("License is not valid: %s", d.Check.Reason)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	addLicenseFlags(doctorCmd)
+}
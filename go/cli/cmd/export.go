@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	tuish "github.com/tuishdotdev/tuish/go"
+)
+
+type exportOutput struct {
+	LicenseKey string `json:"licenseKey"`
+	NodeLocked bool   `json:"nodeLocked"`
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the license key stored on this machine, for backup or transfer",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sdk, err := newLicenseSDK()
+		if err != nil {
+			return err
+		}
+
+		key, err := sdk.ExportLicense()
+		if errors.Is(err, tuish.ErrNoLicense) {
+			return errors.New("No license stored on this machine")
+		}
+		if err != nil {
+			return err
+		}
+
+		nodeLocked := false
+		if payload, err := tuish.ExtractLicensePayload(key); err == nil {
+			nodeLocked = payload.MachineID != nil
+		}
+
+		if outputJSON {
+			return writeJSON(cmd.OutOrStdout(), exportOutput{LicenseKey: key, NodeLocked: nodeLocked})
+		}
+
+		// Only the key goes to stdout, so it can be redirected to a file
+		fmt.Fprintln(cmd.OutOrStdout(), key)
+		if nodeLocked {
+			fmt.Fprintln(os.Stderr, warnStyle.Render("This license is locked to this machine and won't verify on another one."))
+		}
+		return nil
+	},
+}
+
+func init() {
+	addLicenseFlags(exportCmd)
+}
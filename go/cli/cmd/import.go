@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	tuish "github.com/tuishdotdev/tuish/go"
+)
+
+type importOutput struct {
+	Imported       bool   `json:"imported"`
+	LicenseID      string `json:"licenseId"`
+	MachineMatches bool   `json:"machineMatches"`
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <license-key|->",
+	Short: "Store a license key from tuish export (use - to read stdin)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := readLicenseKey(args)
+		if err != nil {
+			return err
+		}
+
+		sdk, err := newLicenseSDK()
+		if err != nil {
+			return err
+		}
+
+		payload, err := sdk.ImportLicense(key)
+		switch {
+		case errors.Is(err, tuish.ErrInvalidFormat):
+			return errors.New("Invalid license key format")
+		case errors.Is(err, tuish.ErrInvalidSignature):
+			return errors.New("License is not valid: invalid_signature")
+		case errors.Is(err, tuish.ErrRevoked):
+			return errors.New("License is not valid: revoked")
+		case err != nil:
+			return fmt.Errorf("import license: %w", err)
+		}
+
+		machineMatches := payload.MachineID == nil || *payload.MachineID == sdk.GetMachineFingerprint()
+
+		if outputJSON {
+			return writeJSON(cmd.OutOrStdout(), importOutput{
+				Imported:       true,
+				LicenseID:      payload.LicenseID,
+				MachineMatches: machineMatches,
+			})
+		}
+
+		fmt.Println(successStyle.Render("License imported."))
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("License: %s", payload.LicenseID)))
+		if !machineMatches {
+			fmt.Println(warnStyle.Render("This license is locked to another machine and won't verify here; deactivate it there or contact support."))
+		}
+		return nil
+	},
+}
+
+func init() {
+	addLicenseFlags(importCmd)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	tuish "github.com/tuishdotdev/tuish/go"
+)
+
+var (
+	licenseProductID string
+	licensePublicKey string
+)
+
+// addLicenseFlags registers the flags needed to build an SDK for a product.
+func addLicenseFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&licenseProductID, "product-id", "", "Product ID the license belongs to")
+	cmd.Flags().StringVar(&licensePublicKey, "public-key", "", "Product public key (SPKI base64 or hex)")
+}
+
+func newLicenseSDK() (*tuish.SDK, error) {
+	if licenseProductID == "" {
+		return nil, errors.New("--product-id is required")
+	}
+	if licensePublicKey == "" {
+		return nil, errors.New("--public-key is required")
+	}
+	return tuish.New(tuish.Config{
+		ProductID:  licenseProductID,
+		PublicKey:  licensePublicKey,
+		APIBaseURL: apiBaseURL,
+	})
+}
+
+// readLicenseKey resolves the license key argument. "-" reads the key from
+// stdin: piped input is read to EOF straight away, while a terminal is
+// prompted for a single line (or rejected in --json mode, which never prompts).
+func readLicenseKey(args []string) (string, error) {
+	return readLicenseKeyFrom(args, os.Stdin, os.Stdout, stdinIsTerminal())
+}
+
+// readLicenseKeyFrom is readLicenseKey with stdin, the prompt's output and
+// whether stdin is a terminal passed in.
+func readLicenseKeyFrom(args []string, in io.Reader, out io.Writer, terminal bool) (string, error) {
+	if len(args) == 0 {
+		return "", errors.New("License key is required; pass it as an argument or - to read stdin")
+	}
+	if args[0] != "-" {
+		return strings.TrimSpace(args[0]), nil
+	}
+
+	if !terminal {
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return "", fmt.Errorf("read stdin: %w", err)
+		}
+		key := strings.TrimSpace(string(data))
+		if key == "" {
+			return "", errors.New("No license key on stdin")
+		}
+		return key, nil
+	}
+
+	if outputJSON {
+		return "", errors.New("stdin is a terminal; pipe the license key in or pass it as an argument")
+	}
+
+	fmt.Fprint(out, "Paste your license key: ")
+	input, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	key := strings.TrimSpace(input)
+	if key == "" {
+		return "", errors.New("License key is required")
+	}
+	return key, nil
+}
+
+func stdinIsTerminal() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+type licenseOutput struct {
+	Valid   bool                  `json:"valid"`
+	Reason  string                `json:"reason,omitempty"`
+	License *tuish.LicenseDetails `json:"license,omitempty"`
+}
+
+// previewLicense verifies a key for this machine without storing it.
+// Invalid keys are reported as errors so scripts see a non-zero exit.
+func previewLicense(sdk *tuish.SDK, key string) (*tuish.LicenseDetails, error) {
+	details, result, err := sdk.PreviewLicense(key)
+	if err != nil {
+		return nil, errors.New("Invalid license key format")
+	}
+	if !result.Valid {
+		return nil, fmt.Errorf("License is not valid: %s", result.Reason)
+	}
+	return details, nil
+}
+
+func printLicenseDetails(details *tuish.LicenseDetails) {
+	fmt.Println(mutedStyle.Render(fmt.Sprintf("License: %s", details.ID)))
+	fmt.Println(mutedStyle.Render(fmt.Sprintf("Product: %s", details.ProductID)))
+	if len(details.Features) > 0 {
+		fmt.Println(mutedStyle.Render(fmt.Sprintf("Features: %s", strings.Join(details.Features, ", "))))
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadLicenseKeyFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		terminal bool
+		json     bool
+		want     string
+		wantErr  string
+	}{
+		{name: "argument", args: []string{" key.from.arg \n"}, want: "key.from.arg"},
+		{name: "piped", args: []string{"-"}, stdin: "\n  key.from.pipe\n\n", want: "key.from.pipe"},
+		{name: "empty pipe", args: []string{"-"}, wantErr: "No license key on stdin"},
+		{name: "terminal prompt", args: []string{"-"}, stdin: "key.typed\nignored\n", terminal: true, want: "key.typed"},
+		{name: "terminal in json mode", args: []string{"-"}, stdin: "key.typed\n", terminal: true, json: true, wantErr: "stdin is a terminal"},
+		{name: "no argument", wantErr: "License key is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputJSON = tt.json
+			t.Cleanup(func() { outputJSON = false })
+
+			var prompt strings.Builder
+			got, err := readLicenseKeyFrom(tt.args, strings.NewReader(tt.stdin), &prompt, tt.terminal)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readLicenseKeyFrom failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if tt.terminal != strings.Contains(prompt.String(), "Paste your license key") {
+				t.Errorf("expected a prompt only for a terminal, got %q", prompt.String())
+			}
+		})
+	}
+}
+
+// blockingReader fails the test if it is read, standing in for a terminal
+// the user hasn't typed into.
+type blockingReader struct{ t *testing.T }
+
+func (r blockingReader) Read([]byte) (int, error) {
+	r.t.Error("expected stdin not to be read")
+	return 0, io.EOF
+}
+
+func TestReadLicenseKeyFromTerminalJSONDoesNotRead(t *testing.T) {
+	outputJSON = true
+	t.Cleanup(func() { outputJSON = false })
+
+	if _, err := readLicenseKeyFrom([]string{"-"}, blockingReader{t}, io.Discard, true); err == nil {
+		t.Error("expected an error for a terminal in --json mode")
+	}
+}
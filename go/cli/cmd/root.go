@@ -44,5 +44,11 @@ func init() {
 		keysCmd,
 		analyticsCmd,
 		demoCmd,
+		activateCmd,
+		verifyCmd,
+		exportCmd,
+		importCmd,
+		doctorCmd,
+		statusCmd,
 	)
 }
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	tuish "github.com/tuishdotdev/tuish/go"
+)
+
+var statusFormat string
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check the license stored on this machine",
+	Long: `Check the license stored on this machine.
+
+--format selects the output: plain (default), json, or shell, which prints
+VAR=value lines for eval:
+
+  eval "$(tuish status --format=shell --product-id ... --public-key ...)"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := statusFormat
+		if outputJSON {
+			format = "json"
+		}
+		if format != "plain" && format != "json" && format != "shell" {
+			return fmt.Errorf("Unknown format %q; use plain, json or shell", format)
+		}
+
+		sdk, err := newLicenseSDK()
+		if err != nil {
+			return err
+		}
+		result, err := sdk.CheckLicense(context.Background())
+		if err != nil {
+			return fmt.Errorf("check license: %w", err)
+		}
+
+		out := cmd.OutOrStdout()
+		switch format {
+		case "json":
+			return writeJSON(out, licenseOutput{Valid: result.Valid, Reason: string(result.Reason), License: result.License})
+		case "shell":
+			return writeShellStatus(out, result)
+		default:
+			return writePlainStatus(out, result)
+		}
+	},
+}
+
+func init() {
+	addLicenseFlags(statusCmd)
+	statusCmd.Flags().StringVar(&statusFormat, "format", "plain", "Output format: plain, json or shell")
+}
+
+// statusFields returns the status as ordered name/value pairs. Expiry is in
+// Unix milliseconds and empty for perpetual licenses.
+func statusFields(result *tuish.LicenseCheckResult) [][2]string {
+	var id, features, expires string
+	if result.License != nil {
+		id = result.License.ID
+		features = strings.Join(result.License.Features, ",")
+		if result.License.ExpiresAt != nil {
+			expires = strconv.FormatInt(*result.License.ExpiresAt, 10)
+		}
+	}
+	return [][2]string{
+		{"VALID", strconv.FormatBool(result.Valid)},
+		{"REASON", string(result.Reason)},
+		{"LICENSE_ID", id},
+		{"FEATURES", features},
+		{"EXPIRES", expires},
+	}
+}
+
+// writeShellStatus prints the status as NAME=value lines with every value
+// single-quoted, so eval can't run anything hidden in a feature name.
+func writeShellStatus(w io.Writer, result *tuish.LicenseCheckResult) error {
+	for _, field := range statusFields(result) {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", field[0], shellQuote(field[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// writePlainStatus prints the status as unstyled "Name: value" lines.
+func writePlainStatus(w io.Writer, result *tuish.LicenseCheckResult) error {
+	lines := []string{fmt.Sprintf("Valid: %t", result.Valid)}
+	if result.Reason != "" {
+		lines = append(lines, fmt.Sprintf("Reason: %s", result.Reason))
+	}
+	if license := result.License; license != nil {
+		expires := "never"
+		if license.ExpiresAt != nil {
+			expires = time.UnixMilli(*license.ExpiresAt).UTC().Format(time.RFC3339)
+		}
+		lines = append(lines,
+			fmt.Sprintf("License: %s", license.ID),
+			fmt.Sprintf("Features: %s", strings.Join(license.Features, ", ")),
+			fmt.Sprintf("Expires: %s", expires),
+		)
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
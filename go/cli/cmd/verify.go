@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <license-key|->",
+	Short: "Verify a license key offline (use - to read stdin)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := readLicenseKey(args)
+		if err != nil {
+			return err
+		}
+
+		sdk, err := newLicenseSDK()
+		if err != nil {
+			return err
+		}
+
+		details, err := previewLicense(sdk, key)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			return writeJSON(cmd.OutOrStdout(), licenseOutput{Valid: true, License: details})
+		}
+
+		fmt.Println(successStyle.Render("License is valid."))
+		printLicenseDetails(details)
+		return nil
+	},
+}
+
+func init() {
+	addLicenseFlags(verifyCmd)
+}
@@ -65,6 +65,162 @@ func TestCliVectors(t *testing.T) {
 	}
 }
 
+func TestCliDoctorJSON(t *testing.T) {
+	storageDir := t.TempDir()
+	t.Setenv(StorageDirEnv, storageDir)
+	t.Setenv(DefaultLicenseKeyEnv, "")
+
+	license := generateTestLicense(t, LicensePayload{
+		LicenseID: "lic_doctor",
+		ProductID: "prod_test",
+		Features:  []string{"pro"},
+		IssuedAt:  1700000000000,
+	})
+	if err := NewStorage(storageDir, false).Save("prod_test", license, GetMachineFingerprint()); err != nil {
+		t.Fatalf("save license: %v", err)
+	}
+
+	bin := buildCLIBinary(t)
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	stdout, stderr, exitCode := runCLI(t, bin, []string{
+		"--config", configPath, "--json", "--api-url", "http://127.0.0.1:1",
+		"doctor", "--product-id", "prod_test", "--public-key", testPublicKeyHex,
+	})
+	if exitCode != 0 {
+		t.Fatalf("exit code %d: %s", exitCode, stderr)
+	}
+	if strings.Contains(stdout, license) {
+		t.Fatal("doctor printed the full license key")
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("parse output json: %v (%s)", err, stdout)
+	}
+	cache, _ := got["cache"].(map[string]any)
+	check, _ := got["check"].(map[string]any)
+	if cache == nil || check == nil {
+		t.Fatalf("expected cache and check objects, got %s", stdout)
+	}
+
+	// Normalize the fields that vary by machine and time
+	for _, field := range []string{"fingerprint", "storageDir", "sdkVersion"} {
+		if s, _ := got[field].(string); s == "" {
+			t.Errorf("expected %s to be set", field)
+		}
+		got[field] = field
+	}
+	for _, field := range []string{"cachedAt", "refreshAt"} {
+		if n, _ := cache[field].(float64); n <= 0 {
+			t.Errorf("expected cache.%s to be set", field)
+		}
+		cache[field] = field
+	}
+	normalized, _ := json.Marshal(got)
+
+	compareJSON(t, string(normalized), json.RawMessage(`{
+		"sdkVersion": "sdkVersion",
+		"productId": "prod_test",
+		"apiBaseUrl": "http://127.0.0.1:1",
+		"fingerprint": "fingerprint",
+		"envLicense": false,
+		"storageDir": "storageDir",
+		"cache": {
+			"exists": true,
+			"licenseKey": "`+RedactLicenseKey(license)+`",
+			"cachedAt": "cachedAt",
+			"refreshAt": "refreshAt",
+			"needsRefresh": false
+		},
+		"check": {
+			"valid": true,
+			"license": {
+				"id": "lic_doctor",
+				"productId": "prod_test",
+				"features": ["pro"],
+				"status": "active",
+				"issuedAt": 1700000000000,
+				"expiresAt": null
+			},
+			"offlineVerified": true,
+			"source": "offline"
+		}
+	}`))
+}
+
+func TestCliStatusFormats(t *testing.T) {
+	storageDir := t.TempDir()
+	t.Setenv(StorageDirEnv, storageDir)
+
+	expiresAt := int64(4102444800000)
+	license := generateTestLicense(t, LicensePayload{
+		LicenseID: "lic_status",
+		ProductID: "prod_test",
+		Features:  []string{"pro", "it's; rm -rf ~"},
+		IssuedAt:  1700000000000,
+		ExpiresAt: &expiresAt,
+	})
+	if err := NewStorage(storageDir, false).Save("prod_test", license, GetMachineFingerprint()); err != nil {
+		t.Fatalf("save license: %v", err)
+	}
+
+	bin := buildCLIBinary(t)
+	status := func(t *testing.T, format ...string) string {
+		t.Helper()
+		args := []string{"--config", filepath.Join(t.TempDir(), "config.json"), "--api-url", "http://127.0.0.1:1"}
+		args = append(args, format...)
+		args = append(args, "status", "--product-id", "prod_test", "--public-key", testPublicKeyHex)
+		stdout, stderr, exitCode := runCLI(t, bin, args)
+		if exitCode != 0 {
+			t.Fatalf("exit code %d: %s", exitCode, stderr)
+		}
+		return stdout
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		want := "Valid: true\nLicense: lic_status\nFeatures: pro, it's; rm -rf ~\nExpires: 2100-01-01T00:00:00Z\n"
+		if got := status(t, "--format=plain"); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		for _, flag := range []string{"--format=json", "--json"} {
+			compareJSON(t, status(t, flag), json.RawMessage(`{
+				"valid": true,
+				"license": {
+					"id": "lic_status",
+					"productId": "prod_test",
+					"features": ["pro", "it's; rm -rf ~"],
+					"status": "active",
+					"issuedAt": 1700000000000,
+					"expiresAt": 4102444800000
+				}
+			}`))
+		}
+	})
+
+	t.Run("shell", func(t *testing.T) {
+		want := "VALID='true'\nREASON=''\nLICENSE_ID='lic_status'\nFEATURES='pro,it'\\''s; rm -rf ~'\nEXPIRES='4102444800000'\n"
+		got := status(t, "--format=shell")
+		if got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+
+		sh, err := exec.LookPath("sh")
+		if err != nil {
+			t.Skip("no sh to eval the output with")
+		}
+		out, err := exec.Command(sh, "-c", got+`printf '%s|%s' "$VALID" "$FEATURES"`).Output()
+		if err != nil {
+			t.Fatalf("eval: %v", err)
+		}
+		if string(out) != "true|pro,it's; rm -rf ~" {
+			t.Errorf("expected values to survive eval, got %q", out)
+		}
+	})
+}
+
 func buildCLIBinary(t *testing.T) string {
 	t.Helper()
 	tempDir := t.TempDir()
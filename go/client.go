@@ -4,15 +4,33 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
 const (
 	defaultAPIURL     = "https://tuish-api-production.doug-lance.workers.dev"
 	defaultTimeout    = 30 * time.Second
+	defaultAPIVersion = "v1"
+)
+
+// Error codes returned by the tuish API in APIError.Code.
+const (
+	ErrCodeInvalidRequest  = "INVALID_REQUEST"
+	ErrCodeUnauthorized    = "UNAUTHORIZED"
+	ErrCodeForbidden       = "FORBIDDEN"
+	ErrCodeNotFound        = "NOT_FOUND"
+	ErrCodeProductNotFound = "PRODUCT_NOT_FOUND"
+	ErrCodeLicenseNotFound = "LICENSE_NOT_FOUND"
+	ErrCodePaymentRequired = "PAYMENT_REQUIRED"
+	ErrCodeRateLimited     = "RATE_LIMITED"
+	ErrCodeRebindDenied    = "REBIND_NOT_ALLOWED"
+	ErrCodeInternal        = "INTERNAL_ERROR"
 )
 
 // APIError represents an API error response.
@@ -21,6 +39,10 @@ type APIError struct {
 	Code       string
 	Message    string
 	Details    map[string]any
+
+	// RetryAfter is how long the server asked the client to wait before
+	// retrying, from the Retry-After header (0 if absent)
+	RetryAfter time.Duration
 }
 
 func (e *APIError) Error() string {
@@ -30,6 +52,15 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("%s (status %d)", e.Message, e.StatusCode)
 }
 
+// IsCode reports whether err is (or wraps) an *APIError with the given code.
+func IsCode(err error, code string) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Code == code
+}
+
 // apiResponse wraps API responses.
 type apiResponse[T any] struct {
 	Success bool           `json:"success"`
@@ -47,9 +78,11 @@ type apiErrorBody struct {
 type Client struct {
 	baseURL       string
 	apiKey        string
+	apiVersion    string
 	identityToken string
 	httpClient    *http.Client
 	debug         bool
+	logger        func(format string, args ...any)
 }
 
 // NewClient creates a new API client.
@@ -58,14 +91,60 @@ func NewClient(baseURL, apiKey string, debug bool) *Client {
 		baseURL = defaultAPIURL
 	}
 
-	return &Client{
-		baseURL: baseURL,
-		apiKey:  apiKey,
+	c := &Client{
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		apiVersion: defaultAPIVersion,
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
 		debug: debug,
 	}
+
+	if debug {
+		c.logger = defaultLogger
+	}
+
+	return c
+}
+
+// logf writes a debug line if logging is enabled.
+func (c *Client) logf(format string, args ...any) {
+	if c.logger != nil {
+		c.logger(format, args...)
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date. It returns 0 if the header is missing or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// SetAPIVersion sets the API version used in request paths and the
+// X-API-Version header (default: "v1"). An empty version restores the default.
+func (c *Client) SetAPIVersion(version string) {
+	if version == "" {
+		version = defaultAPIVersion
+	}
+	c.apiVersion = version
+}
+
+// endpoint returns the versioned path for an API endpoint. Path parameters
+// must be escaped with url.PathEscape before they are joined into path.
+func (c *Client) endpoint(path string) string {
+	return "/" + c.apiVersion + path
 }
 
 // SetIdentityToken sets the identity token for authenticated requests.
@@ -78,6 +157,11 @@ func (c *Client) ClearIdentityToken() {
 	c.identityToken = ""
 }
 
+// HasIdentityToken returns whether an identity token is set.
+func (c *Client) HasIdentityToken() bool {
+	return c.identityToken != ""
+}
+
 // request makes an HTTP request to the API.
 func (c *Client) request(ctx context.Context, method, path string, body any, useAPIKey, useIdentityToken bool, result any) error {
 	url := c.baseURL + path
@@ -97,6 +181,7 @@ func (c *Client) request(ctx context.Context, method, path string, body any, use
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Version", c.apiVersion)
 
 	if useAPIKey && c.apiKey != "" {
 		req.Header.Set("X-API-Key", c.apiKey)
@@ -108,16 +193,20 @@ func (c *Client) request(ctx context.Context, method, path string, body any, use
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.logf("%s %s failed: %v", method, path, err)
 		return fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	c.logf("%s %s -> %d", method, path, resp.StatusCode)
+
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return fmt.Errorf("read response: %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
 		var apiResp apiResponse[any]
 		if err := json.Unmarshal(respBody, &apiResp); err == nil && apiResp.Error != nil {
 			return &APIError{
@@ -125,11 +214,13 @@ func (c *Client) request(ctx context.Context, method, path string, body any, use
 				Code:       apiResp.Error.Code,
 				Message:    apiResp.Error.Message,
 				Details:    apiResp.Error.Details,
+				RetryAfter: retryAfter,
 			}
 		}
 		return &APIError{
 			StatusCode: resp.StatusCode,
 			Message:    fmt.Sprintf("request failed with status %d", resp.StatusCode),
+			RetryAfter: retryAfter,
 		}
 	}
 
@@ -152,15 +243,24 @@ func (c *Client) request(ctx context.Context, method, path string, body any, use
 
 // CreateCheckoutSession creates a browser checkout session.
 func (c *Client) CreateCheckoutSession(ctx context.Context, productID, email string) (*CheckoutSessionResult, error) {
+	return c.CreateCheckoutSessionWithReturn(ctx, productID, email, "")
+}
+
+// CreateCheckoutSessionWithReturn creates a browser checkout session that
+// redirects to returnURL once the checkout finishes.
+func (c *Client) CreateCheckoutSessionWithReturn(ctx context.Context, productID, email, returnURL string) (*CheckoutSessionResult, error) {
 	body := map[string]string{
 		"productId": productID,
 	}
 	if email != "" {
 		body["email"] = email
 	}
+	if returnURL != "" {
+		body["returnUrl"] = returnURL
+	}
 
 	var result CheckoutSessionResult
-	err := c.request(ctx, "POST", "/v1/checkout/init", body, true, false, &result)
+	err := c.request(ctx, "POST", c.endpoint("/checkout/init"), body, true, false, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +270,7 @@ func (c *Client) CreateCheckoutSession(ctx context.Context, productID, email str
 // GetCheckoutStatus checks checkout session status.
 func (c *Client) GetCheckoutStatus(ctx context.Context, sessionID string) (*CheckoutStatus, error) {
 	var result CheckoutStatus
-	err := c.request(ctx, "GET", "/v1/checkout/status/"+sessionID, nil, false, false, &result)
+	err := c.request(ctx, "GET", c.endpoint("/checkout/status/"+url.PathEscape(sessionID)), nil, false, false, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -184,13 +284,21 @@ func (c *Client) RequestLoginOtp(ctx context.Context, email string) (*OtpRequest
 	}
 
 	var result OtpRequestResult
-	err := c.request(ctx, "POST", "/v1/auth/login/init", body, false, false, &result)
+	err := c.request(ctx, "POST", c.endpoint("/auth/login/init"), body, false, false, &result)
 	if err != nil {
 		return nil, err
 	}
+	result.RequestedAt = nowFunc()
 	return &result, nil
 }
 
+// ResendLoginOtp requests a fresh login OTP for the same email, replacing
+// the previous one. Callers should verify with the returned OtpID. Rapid
+// resends fail with an *APIError carrying ErrCodeRateLimited and RetryAfter.
+func (c *Client) ResendLoginOtp(ctx context.Context, email string) (*OtpRequestResult, error) {
+	return c.RequestLoginOtp(ctx, email)
+}
+
 // VerifyLogin verifies an OTP and logs in.
 func (c *Client) VerifyLogin(ctx context.Context, email, otpID, otp, deviceFingerprint string) (*LoginResult, error) {
 	body := map[string]string{
@@ -201,7 +309,7 @@ func (c *Client) VerifyLogin(ctx context.Context, email, otpID, otp, deviceFinge
 	}
 
 	var result LoginResult
-	err := c.request(ctx, "POST", "/v1/auth/login/verify", body, false, false, &result)
+	err := c.request(ctx, "POST", c.endpoint("/auth/login/verify"), body, false, false, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -218,7 +326,7 @@ func (c *Client) InitPurchase(ctx context.Context, productID string) (*PurchaseI
 	}
 
 	var result PurchaseInitResult
-	err := c.request(ctx, "POST", "/v1/purchase/init", body, false, true, &result)
+	err := c.request(ctx, "POST", c.endpoint("/purchase/init"), body, false, true, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +342,7 @@ func (c *Client) RequestPurchaseOtp(ctx context.Context) (*struct {
 		OtpID     string `json:"otpId"`
 		ExpiresIn int    `json:"expiresIn"`
 	}
-	err := c.request(ctx, "POST", "/v1/purchase/otp", nil, false, true, &result)
+	err := c.request(ctx, "POST", c.endpoint("/purchase/otp"), nil, false, true, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -251,7 +359,7 @@ func (c *Client) ConfirmPurchase(ctx context.Context, productID, cardID, otpID,
 	}
 
 	var result PurchaseConfirmResult
-	err := c.request(ctx, "POST", "/v1/purchase/confirm", body, false, true, &result)
+	err := c.request(ctx, "POST", c.endpoint("/purchase/confirm"), body, false, true, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -266,9 +374,58 @@ func (c *Client) ValidateLicense(ctx context.Context, licenseKey, machineFingerp
 	}
 
 	var result ValidateResponse
-	err := c.request(ctx, "POST", "/v1/licenses/validate", body, true, false, &result)
+	err := c.request(ctx, "POST", c.endpoint("/licenses/validate"), body, true, false, &result)
 	if err != nil {
+		// The server may echo the key back in its error message
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			apiErr.Message = redactKeyIn(apiErr.Message, licenseKey)
+		}
 		return nil, err
 	}
 	return &result, nil
 }
+
+// DeactivateLicense releases a license's binding to a machine so it can be
+// activated on another one.
+func (c *Client) DeactivateLicense(ctx context.Context, licenseKey, machineFingerprint string) error {
+	body := DeactivateRequest{
+		LicenseKey:         licenseKey,
+		MachineFingerprint: machineFingerprint,
+	}
+
+	err := c.request(ctx, "POST", c.endpoint("/licenses/deactivate"), body, true, false, nil)
+	if err != nil {
+		// The server may echo the key back in its error message
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			apiErr.Message = redactKeyIn(apiErr.Message, licenseKey)
+		}
+		return err
+	}
+	return nil
+}
+
+// RebindLicense asks the server to re-issue a node-locked license for
+// machineFingerprint, releasing its binding to the old machine. It returns
+// the new license key.
+func (c *Client) RebindLicense(ctx context.Context, licenseKey, machineFingerprint string) (string, error) {
+	body := RebindRequest{
+		LicenseKey:         licenseKey,
+		MachineFingerprint: machineFingerprint,
+	}
+
+	var result RebindResponse
+	err := c.request(ctx, "POST", c.endpoint("/licenses/rebind"), body, true, false, &result)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			apiErr.Message = redactKeyIn(apiErr.Message, licenseKey)
+		}
+		return "", err
+	}
+	if result.LicenseKey == "" {
+		return "", errors.New("rebind response has no license key")
+	}
+	return result.LicenseKey, nil
+}
@@ -0,0 +1,7 @@
+package tuish
+
+import "time"
+
+// nowFunc is the clock every expiry, refresh, grace period and trial check
+// reads. Tests override it to land on an exact moment instead of sleeping.
+var nowFunc = time.Now
@@ -0,0 +1,45 @@
+package tuish
+
+import (
+	"testing"
+	"time"
+)
+
+// setNow fixes the package clock at now for the rest of the test.
+func setNow(t *testing.T, now time.Time) {
+	t.Helper()
+	original := nowFunc
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = original })
+}
+
+func TestClockJustAfterExpiry(t *testing.T) {
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := expiry.UnixMilli()
+	license := generateTestLicense(t, LicensePayload{
+		LicenseID: "lic_clock",
+		ProductID: "prod_test",
+		IssuedAt:  expiry.Add(-24 * time.Hour).UnixMilli(),
+		ExpiresAt: &expiresAt,
+	})
+	publicKey, err := ParsePublicKey(testPublicKeyHex)
+	if err != nil {
+		t.Fatalf("parse public key: %v", err)
+	}
+
+	setNow(t, expiry.Add(-time.Millisecond))
+	if result := VerifyLicense(license, publicKey, ""); !result.Valid {
+		t.Fatalf("expected valid license just before expiry, got %s", result.Reason)
+	}
+	if IsLicenseExpired(license) {
+		t.Error("expected IsLicenseExpired to be false just before expiry")
+	}
+
+	setNow(t, expiry.Add(time.Millisecond))
+	if result := VerifyLicense(license, publicKey, ""); result.Valid || result.Reason != ReasonExpired {
+		t.Errorf("expected expired just after expiry, got valid=%v reason=%s", result.Valid, result.Reason)
+	}
+	if !IsLicenseExpired(license) {
+		t.Error("expected IsLicenseExpired to be true just after expiry")
+	}
+}
@@ -1,14 +1,17 @@
 package tuish
 
 import (
+	"bytes"
 	"crypto/ed25519"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
-	"time"
 )
 
 var (
@@ -16,8 +19,20 @@ var (
 	ErrInvalidSignature = errors.New("invalid signature")
 	ErrExpired          = errors.New("license expired")
 	ErrMachineMismatch  = errors.New("machine mismatch")
+	ErrProductMismatch  = errors.New("product mismatch")
+	ErrRevoked          = errors.New("license revoked")
 )
 
+// DefaultMaxLicenseLength is the default for MaxLicenseLength. Real
+// licenses are a few hundred bytes.
+const DefaultMaxLicenseLength = 8 << 10
+
+// MaxLicenseLength is the longest license string ParseLicense and
+// VerifyLicense will decode, in bytes. Longer input is rejected as
+// ErrInvalidFormat before any decoding, so a huge paste can't force large
+// allocations. Raise it before use if your licenses carry many features.
+var MaxLicenseLength = DefaultMaxLicenseLength
+
 // VerifyResult contains the result of license verification.
 type VerifyResult struct {
 	Valid   bool
@@ -34,31 +49,26 @@ type ParsedLicense struct {
 	RawPayload string
 }
 
-// base64URLDecode decodes a base64url-encoded string.
+// base64URLDecode decodes an unpadded base64url-encoded string.
 func base64URLDecode(s string) ([]byte, error) {
-	// Add padding if needed
-	switch len(s) % 4 {
-	case 2:
-		s += "=="
-	case 3:
-		s += "="
-	}
-
-	// Convert from base64url to standard base64
-	s = strings.ReplaceAll(s, "-", "+")
-	s = strings.ReplaceAll(s, "_", "/")
-
-	return base64.StdEncoding.DecodeString(s)
+	return base64.RawURLEncoding.DecodeString(s)
 }
 
 // ParseLicense parses a license string into its components.
 func ParseLicense(licenseString string) (*ParsedLicense, error) {
-	parts := strings.Split(licenseString, ".")
-	if len(parts) != 3 {
+	if len(licenseString) > MaxLicenseLength {
+		return nil, fmt.Errorf("%w: longer than %d bytes", ErrInvalidFormat, MaxLicenseLength)
+	}
+
+	headerB64, rest, ok := strings.Cut(licenseString, ".")
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+	payloadB64, signatureB64, ok := strings.Cut(rest, ".")
+	if !ok || strings.Contains(signatureB64, ".") {
 		return nil, ErrInvalidFormat
 	}
 
-	headerB64, payloadB64, signatureB64 := parts[0], parts[1], parts[2]
 	if headerB64 == "" || payloadB64 == "" || signatureB64 == "" {
 		return nil, ErrInvalidFormat
 	}
@@ -84,6 +94,9 @@ func ParseLicense(licenseString string) (*ParsedLicense, error) {
 	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
 		return nil, fmt.Errorf("parse payload: %w", err)
 	}
+	if err := validatePayload(&payload); err != nil {
+		return nil, err
+	}
 
 	// Decode signature
 	signature, err := base64URLDecode(signatureB64)
@@ -100,23 +113,37 @@ func ParseLicense(licenseString string) (*ParsedLicense, error) {
 	}, nil
 }
 
-// ParsePublicKey parses a public key from SPKI base64 or hex format.
+// validatePayload rejects payloads missing a required claim. A signed
+// "{}" would otherwise parse into a license with no ID or product.
+func validatePayload(payload *LicensePayload) error {
+	switch {
+	case payload.LicenseID == "":
+		return fmt.Errorf("%w: payload has no lid", ErrInvalidFormat)
+	case payload.ProductID == "":
+		return fmt.Errorf("%w: payload has no pid", ErrInvalidFormat)
+	case payload.IssuedAt <= 0:
+		return fmt.Errorf("%w: payload has no iat", ErrInvalidFormat)
+	}
+	return nil
+}
+
+// ParsePublicKey parses a public key from PEM, SPKI base64 or hex format.
 // Returns the raw 32-byte key.
 func ParsePublicKey(publicKey string) (ed25519.PublicKey, error) {
+	publicKey = strings.TrimSpace(publicKey)
+
+	// PEM-wrapped SPKI (-----BEGIN PUBLIC KEY-----)
+	if strings.HasPrefix(publicKey, "-----BEGIN") {
+		return parsePEMPublicKey(publicKey)
+	}
+
 	// Check if it's SPKI base64 format (starts with MCow for Ed25519)
 	if strings.HasPrefix(publicKey, "MCow") || strings.HasPrefix(publicKey, "MCoq") {
 		decoded, err := base64.StdEncoding.DecodeString(publicKey)
 		if err != nil {
 			return nil, fmt.Errorf("decode SPKI key: %w", err)
 		}
-
-		// SPKI format: 12 byte header + 32 byte key
-		if len(decoded) != 44 {
-			return nil, fmt.Errorf("invalid SPKI key length: expected 44 bytes, got %d", len(decoded))
-		}
-
-		// Extract the raw key (last 32 bytes)
-		return ed25519.PublicKey(decoded[12:]), nil
+		return parseSPKIPublicKey(decoded)
 	}
 
 	// Check if it's hex format (64 characters = 32 bytes)
@@ -128,7 +155,71 @@ func ParsePublicKey(publicKey string) (ed25519.PublicKey, error) {
 		return ed25519.PublicKey(key), nil
 	}
 
-	return nil, errors.New("invalid public key format: expected SPKI base64 (MCow...) or 64-character hex")
+	return nil, errors.New("invalid public key format: expected PEM, SPKI base64 (MCow...) or 64-character hex")
+}
+
+// ed25519SPKIPrefix is the DER header of an Ed25519 SPKI key
+// (SEQUENCE, AlgorithmIdentifier with OID 1.3.101.112, BIT STRING).
+var ed25519SPKIPrefix = []byte{0x30, 0x2a, 0x30, 0x05, 0x06, 0x03, 0x2b, 0x65, 0x70, 0x03, 0x21, 0x00}
+
+// parsePEMPublicKey parses a "PUBLIC KEY" PEM block holding an Ed25519 SPKI key.
+func parsePEMPublicKey(publicKey string) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKey))
+	if block == nil {
+		return nil, errors.New("decode PEM key: no complete PEM block found")
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("decode PEM key: expected PUBLIC KEY block, got %s", block.Type)
+	}
+
+	key, err := parseSPKIPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(block.Bytes[:len(ed25519SPKIPrefix)], ed25519SPKIPrefix) {
+		return nil, errors.New("decode PEM key: not an Ed25519 public key")
+	}
+	return key, nil
+}
+
+// parseSPKIPublicKey extracts the raw key from DER-encoded SPKI bytes.
+func parseSPKIPublicKey(der []byte) (ed25519.PublicKey, error) {
+	// SPKI format: 12 byte header + 32 byte key
+	if len(der) != 44 {
+		return nil, fmt.Errorf("invalid SPKI key length: expected 44 bytes, got %d", len(der))
+	}
+
+	// Extract the raw key (last 32 bytes)
+	return ed25519.PublicKey(der[12:]), nil
+}
+
+// PublicKeyFromFile reads a public key for Config.PublicKey from a file in
+// any format ParsePublicKey accepts. The key is validated before returning.
+func PublicKeyFromFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read public key: %w", err)
+	}
+
+	key := strings.TrimSpace(string(data))
+	if _, err := ParsePublicKey(key); err != nil {
+		return "", fmt.Errorf("public key in %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// PublicKeyFromEnv reads a public key for Config.PublicKey from the named
+// environment variable. The key is validated before returning.
+func PublicKeyFromEnv(name string) (string, error) {
+	key := strings.TrimSpace(os.Getenv(name))
+	if key == "" {
+		return "", fmt.Errorf("public key: environment variable %s is not set", name)
+	}
+
+	if _, err := ParsePublicKey(key); err != nil {
+		return "", fmt.Errorf("public key in $%s: %w", name, err)
+	}
+	return key, nil
 }
 
 // VerifyLicense verifies a license signature and checks expiration/machine binding.
@@ -138,26 +229,51 @@ func VerifyLicense(licenseString string, publicKey ed25519.PublicKey, machineID
 		return &VerifyResult{Valid: false, Reason: ReasonInvalidFormat}
 	}
 
-	// Verify signature
-	message := []byte(parsed.RawHeader + "." + parsed.RawPayload)
+	// Verify signature over "header.payload", which prefixes the license
+	message := []byte(licenseString[:len(parsed.RawHeader)+1+len(parsed.RawPayload)])
 	if !ed25519.Verify(publicKey, message, parsed.Signature) {
 		return &VerifyResult{Valid: false, Reason: ReasonInvalidSignature}
 	}
 
 	// Check expiration
-	if parsed.Payload.ExpiresAt != nil && *parsed.Payload.ExpiresAt < time.Now().UnixMilli() {
+	if parsed.Payload.ExpiresAt != nil && *parsed.Payload.ExpiresAt < nowFunc().UnixMilli() {
 		return &VerifyResult{Valid: false, Payload: &parsed.Payload, Reason: ReasonExpired}
 	}
 
 	// Check machine ID if license is locked to a specific machine
 	// If license.MachineID is nil, license is valid on any machine
-	if machineID != "" && parsed.Payload.MachineID != nil && *parsed.Payload.MachineID != machineID {
+	if machineID != "" && parsed.Payload.MachineID != nil && !machineIDMatches(*parsed.Payload.MachineID, machineID) {
 		return &VerifyResult{Valid: false, Payload: &parsed.Payload, Reason: ReasonMachineMismatch}
 	}
 
 	return &VerifyResult{Valid: true, Payload: &parsed.Payload}
 }
 
+// VerifyDetached verifies a license delivered as separate files: the header
+// and payload JSON and the raw signature bytes. It rebuilds the compact
+// license by base64url-encoding each part and verifies that exactly as
+// VerifyLicense does, so the result is the same as for the compact form.
+//
+// The signature covers the encoded bytes, not the JSON values, so
+// headerJSON and payloadJSON must be byte-for-byte what the issuer signed.
+// Reformatting, re-indenting or re-marshaling either file, or adding a
+// trailing newline, breaks the signature.
+func VerifyDetached(headerJSON, payloadJSON, signature []byte, publicKey ed25519.PublicKey, machineID string) *VerifyResult {
+	license := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(payloadJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(signature)
+	return VerifyLicense(license, publicKey, machineID)
+}
+
+// machineIDMatches compares machine IDs in constant time so node-locked
+// licenses don't leak how much of the ID matched.
+func machineIDMatches(licensed, current string) bool {
+	if len(licensed) != len(current) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(licensed), []byte(current)) == 1
+}
+
 // ExtractLicensePayload extracts the payload from a license without verification.
 // This is for display purposes only - never trust unverified payloads.
 func ExtractLicensePayload(licenseString string) (*LicensePayload, error) {
@@ -178,5 +294,5 @@ func IsLicenseExpired(licenseString string) bool {
 	if payload.ExpiresAt == nil {
 		return false // Perpetual license
 	}
-	return *payload.ExpiresAt < time.Now().UnixMilli()
+	return *payload.ExpiresAt < nowFunc().UnixMilli()
 }
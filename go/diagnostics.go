@@ -0,0 +1,82 @@
+package tuish
+
+// Diagnostics is a snapshot of the SDK's license state for support requests
+// and bug reports. Its JSON form is stable. It never holds the full license
+// key.
+type Diagnostics struct {
+	SDKVersion string `json:"sdkVersion"`
+	ProductID  string `json:"productId"`
+
+	// APIBaseURL is the URL the client sends requests to, including the
+	// default when Config.APIBaseURL is empty.
+	APIBaseURL  string `json:"apiBaseUrl"`
+	Fingerprint string `json:"fingerprint"`
+
+	// EnvLicense reports whether the Config.LicenseKeyEnv variable
+	// (TUISH_LICENSE_KEY by default) holds a license key.
+	EnvLicense bool `json:"envLicense"`
+
+	// StorageDir is empty when a custom Config.Store is in use.
+	StorageDir string `json:"storageDir"`
+
+	Cache DiagnosticsCache `json:"cache"`
+
+	// Check is the offline verification of the cached license, or nil if
+	// the cache couldn't be read.
+	Check *LicenseCheckResult `json:"check"`
+}
+
+// DiagnosticsCache describes the product's cache entry.
+type DiagnosticsCache struct {
+	Exists bool `json:"exists"`
+
+	// LicenseKey is the cached key, redacted with RedactLicenseKey.
+	LicenseKey   string `json:"licenseKey,omitempty"`
+	CachedAt     int64  `json:"cachedAt,omitempty"`
+	RefreshAt    int64  `json:"refreshAt,omitempty"`
+	NeedsRefresh bool   `json:"needsRefresh"`
+
+	// Error is why the cache couldn't be read, if it couldn't.
+	Error string `json:"error,omitempty"`
+}
+
+// Diagnose reports the SDK's license state. It works offline: the cached
+// license is verified as CheckLicenseWith(SkipOnline()) would, but nothing
+// is sent to the server and the cache is never changed, even if the license
+// is invalid.
+func (s *SDK) Diagnose() *Diagnostics {
+	fingerprint := s.GetMachineFingerprint()
+	_, envKey := s.envLicense()
+	d := &Diagnostics{
+		SDKVersion:  Version,
+		ProductID:   s.config.ProductID,
+		APIBaseURL:  s.client.baseURL,
+		Fingerprint: fingerprint,
+		EnvLicense:  envKey != "",
+		StorageDir:  s.StorageDir(),
+	}
+
+	cached, err := s.storage.Load(s.config.ProductID)
+	switch {
+	case err != nil:
+		d.Cache.Error = err.Error()
+		return d
+	case cached != nil:
+		d.Cache = DiagnosticsCache{
+			Exists:       true,
+			LicenseKey:   RedactLicenseKey(cached.LicenseKey),
+			CachedAt:     cached.CachedAt,
+			RefreshAt:    cached.RefreshAt,
+			NeedsRefresh: cached.NeedsRefresh(),
+		}
+		d.Check = s.verifyOffline(cached.LicenseKey, fingerprint)
+		return d
+	}
+
+	d.Check = &LicenseCheckResult{
+		Valid:  false,
+		Reason: ReasonNotFound,
+		Source: LicenseSourceNotFound,
+	}
+	return d
+}
@@ -0,0 +1,85 @@
+package tuish
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSDKDiagnose(t *testing.T) {
+	sdk := newOfflineSDK(t, Config{})
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_doctor",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	d := sdk.Diagnose()
+	if d.SDKVersion != Version || d.ProductID != "prod_test" || d.Fingerprint != sdk.GetMachineFingerprint() {
+		t.Errorf("unexpected identity fields: %+v", d)
+	}
+	if d.StorageDir != sdk.GetStorage().GetStorageDir() {
+		t.Errorf("expected storage dir %s, got %s", sdk.GetStorage().GetStorageDir(), d.StorageDir)
+	}
+	if !d.Cache.Exists || d.Cache.NeedsRefresh || d.Cache.RefreshAt <= d.Cache.CachedAt {
+		t.Errorf("expected a fresh cache entry, got %+v", d.Cache)
+	}
+	if d.Check == nil || !d.Check.Valid || d.Check.License.ID != "lic_doctor" {
+		t.Errorf("expected a valid offline check, got %+v", d.Check)
+	}
+
+	data, _ := json.Marshal(d)
+	if strings.Contains(string(data), license) {
+		t.Error("expected the full license key to be redacted")
+	}
+}
+
+func TestSDKDiagnoseKeepsInvalidCache(t *testing.T) {
+	sdk := newOfflineSDK(t, Config{})
+	if err := sdk.storage.Save("prod_test", "not-a-license", sdk.GetMachineFingerprint()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	d := sdk.Diagnose()
+	if d.Check.Valid || d.Check.Reason != ReasonInvalidFormat {
+		t.Errorf("expected invalid format, got %+v", d.Check)
+	}
+	if sdk.GetCachedLicenseKey() != "not-a-license" {
+		t.Error("expected Diagnose to leave the cache alone")
+	}
+}
+
+func TestSDKDiagnoseNoLicense(t *testing.T) {
+	d := newOfflineSDK(t, Config{}).Diagnose()
+	if d.Cache.Exists || d.Check.Reason != ReasonNotFound {
+		t.Errorf("expected no cached license, got %+v / %+v", d.Cache, d.Check)
+	}
+}
+
+func TestSDKDiagnoseEffectiveSettings(t *testing.T) {
+	sdk, err := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	d := sdk.Diagnose()
+	if d.APIBaseURL != defaultAPIURL {
+		t.Errorf("expected the default API URL %s, got %q", defaultAPIURL, d.APIBaseURL)
+	}
+	if d.EnvLicense {
+		t.Error("expected no env license")
+	}
+
+	t.Setenv(DefaultLicenseKeyEnv, "tuish_env_key")
+	if !sdk.Diagnose().EnvLicense {
+		t.Errorf("expected %s to be reported", DefaultLicenseKeyEnv)
+	}
+}
@@ -0,0 +1,47 @@
+package tuish
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultLogger writes debug output to stderr.
+func defaultLogger(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "[tuish] "+format+"\n", args...)
+}
+
+// RedactLicenseKey returns a short, non-secret form of a license key that is
+// still recognizable in logs and error messages, e.g. "eyJ...lic_123...abc".
+// Keys that can't be parsed are reduced to their first few characters.
+func RedactLicenseKey(key string) string {
+	parts := strings.Split(key, ".")
+	if len(parts) != 3 {
+		if len(key) <= 6 {
+			return "[redacted]"
+		}
+		return key[:3] + "..."
+	}
+
+	lid := ""
+	if payload, err := ExtractLicensePayload(key); err == nil {
+		lid = payload.LicenseID
+	}
+
+	return truncate(parts[0], 3) + "..." + lid + "..." + truncate(parts[2], 3)
+}
+
+func truncate(s string, n int) string {
+	if len(s) < n {
+		return s
+	}
+	return s[:n]
+}
+
+// redactKeyIn replaces every occurrence of key in s with its redacted form.
+func redactKeyIn(s, key string) string {
+	if key == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, key, RedactLicenseKey(key))
+}
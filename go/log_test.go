@@ -0,0 +1,78 @@
+package tuish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactLicenseKey(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_123",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	parts := strings.Split(license, ".")
+
+	redacted := RedactLicenseKey(license)
+	want := parts[0][:3] + "...lic_123..." + parts[2][:3]
+	if redacted != want {
+		t.Errorf("expected %q, got %q", want, redacted)
+	}
+	if strings.Contains(redacted, parts[1]) || strings.Contains(redacted, parts[2]) {
+		t.Error("expected payload and signature to be redacted")
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", "[redacted]"},
+		{"short", "[redacted]"},
+		{"not-a-license-key", "not..."},
+		{"aaaa.bbbb.cccc", "aaa......ccc"},
+	}
+	for _, tt := range tests {
+		if got := RedactLicenseKey(tt.key); got != tt.want {
+			t.Errorf("RedactLicenseKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestValidateLicenseErrorRedactsKey(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_123",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ValidateRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"success": false,
+			"error": map[string]any{
+				"code":    ErrCodeInvalidRequest,
+				"message": "unknown license " + body.LicenseKey,
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", false)
+	_, err := client.ValidateLicense(context.Background(), license, "machine")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(err.Error(), license) {
+		t.Errorf("expected license key to be redacted, got %q", err.Error())
+	}
+	if !strings.Contains(err.Error(), RedactLicenseKey(license)) {
+		t.Errorf("expected redacted key in error, got %q", err.Error())
+	}
+}
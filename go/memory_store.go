@@ -0,0 +1,57 @@
+package tuish
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// MemoryStore is a LicenseStore that keeps cached licenses in memory.
+// It is safe for concurrent use and is useful for tests and ephemeral runs.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	licenses map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory license store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		licenses: make(map[string][]byte),
+	}
+}
+
+// Save stores a license in memory.
+func (m *MemoryStore) Save(productID, licenseKey, machineFingerprint string) error {
+	data, err := json.Marshal(newCachedLicense(productID, licenseKey, machineFingerprint))
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.licenses[productID] = data
+	return nil
+}
+
+// Load returns the cached license for a product, or nil if there is none.
+func (m *MemoryStore) Load(productID string) (*CachedLicenseData, error) {
+	m.mu.RLock()
+	data, ok := m.licenses[productID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	var cached CachedLicenseData
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+// Remove deletes the cached license for a product.
+func (m *MemoryStore) Remove(productID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.licenses, productID)
+	return nil
+}
@@ -0,0 +1,73 @@
+package tuish
+
+import (
+	"strconv"
+	"strings"
+)
+
+// currencySymbols maps ISO 4217 codes to their display symbols.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "\u20AC", // €
+	"GBP": "\u00A3", // £
+	"JPY": "\u00A5", // ¥
+}
+
+// zeroDecimalCurrencies lists currencies whose amounts have no minor unit.
+var zeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true,
+	"JPY": true, "KMF": true, "KRW": true, "MGA": true,
+	"PYG": true, "RWF": true, "UGX": true, "VND": true,
+	"VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// FormatMoney formats an amount in the currency's smallest unit (e.g. cents)
+// for display, such as "$19.99", "€1,299.00", or "¥1,999".
+// Currencies without a known symbol are rendered as "<code> <amount>".
+func FormatMoney(amountCents int, currency string) string {
+	code := strings.ToUpper(currency)
+
+	sign := ""
+	amount := int64(amountCents)
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	var formatted string
+	if zeroDecimalCurrencies[code] {
+		formatted = groupThousands(amount)
+	} else {
+		minor := strconv.FormatInt(amount%100, 10)
+		if len(minor) < 2 {
+			minor = "0" + minor
+		}
+		formatted = groupThousands(amount/100) + "." + minor
+	}
+
+	if symbol, ok := currencySymbols[code]; ok {
+		return sign + symbol + formatted
+	}
+	return code + " " + sign + formatted
+}
+
+// groupThousands renders n with comma thousands separators.
+func groupThousands(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var sb strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		sb.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(digits[i : i+3])
+	}
+	return sb.String()
+}
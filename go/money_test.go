@@ -0,0 +1,33 @@
+package tuish
+
+import "testing"
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int
+		currency string
+		want     string
+	}{
+		{"usd", 1999, "usd", "$19.99"},
+		{"usd uppercase", 1999, "USD", "$19.99"},
+		{"usd zero", 0, "usd", "$0.00"},
+		{"usd single cent", 5, "usd", "$0.05"},
+		{"usd thousands", 123456789, "usd", "$1,234,567.89"},
+		{"eur thousands", 129900, "eur", "€1,299.00"},
+		{"gbp", 1000, "gbp", "£10.00"},
+		{"jpy no decimals", 500, "jpy", "¥500"},
+		{"jpy thousands", 1999, "jpy", "¥1,999"},
+		{"unknown currency", 2500, "chf", "CHF 25.00"},
+		{"unknown zero-decimal currency", 50000, "krw", "KRW 50,000"},
+		{"negative", -1999, "usd", "-$19.99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatMoney(tt.amount, tt.currency); got != tt.want {
+				t.Errorf("FormatMoney(%d, %q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package tea
 
 import (
 	"context"
+	"time"
 
+	bubbletea "github.com/charmbracelet/bubbletea"
 	tuish "github.com/tuishdotdev/tuish/go"
 )
 
@@ -19,3 +21,38 @@ func CheckLicenseCmd(sdk *tuish.SDK) Cmd {
 		return msg
 	}
 }
+
+// RequestLoginOtpCmd requests a login OTP for email.
+func RequestLoginOtpCmd(sdk *tuish.SDK, email string) Cmd {
+	return func() Msg {
+		if sdk == nil {
+			return LoginOtpRequestedMsg{Err: ErrMissingSDK}
+		}
+		result, err := sdk.RequestLoginOtp(context.Background(), email)
+		return LoginOtpRequestedMsg{Result: result, Err: err}
+	}
+}
+
+// ResendLoginOtpCmd requests a fresh login OTP for email, replacing the
+// previous one.
+func ResendLoginOtpCmd(sdk *tuish.SDK, email string) Cmd {
+	return func() Msg {
+		if sdk == nil {
+			return LoginOtpRequestedMsg{Err: ErrMissingSDK, Resent: true}
+		}
+		result, err := sdk.ResendLoginOtp(context.Background(), email)
+		return LoginOtpRequestedMsg{Result: result, Err: err, Resent: true}
+	}
+}
+
+// OtpExpiryCmd sends an OtpExpiredMsg when the OTP expires. Use
+// result.ExpiresAt to render a countdown in the meantime.
+func OtpExpiryCmd(result *tuish.OtpRequestResult) Cmd {
+	if result == nil {
+		return nil
+	}
+	otpID := result.OtpID
+	return bubbletea.Tick(time.Until(result.ExpiresAt()), func(time.Time) Msg {
+		return OtpExpiredMsg{OtpID: otpID}
+	})
+}
@@ -8,3 +8,18 @@ type LicenseCheckedMsg struct {
 	Err    error
 	Valid  bool
 }
+
+// LoginOtpRequestedMsg reports the outcome of requesting or resending a
+// login OTP. A rate-limited resend fails with a *tuish.APIError whose
+// RetryAfter says how long to wait.
+type LoginOtpRequestedMsg struct {
+	Result *tuish.OtpRequestResult
+	Err    error
+	Resent bool
+}
+
+// OtpExpiredMsg is sent by OtpExpiryCmd once the OTP has expired, at which
+// point the UI can offer to resend it.
+type OtpExpiredMsg struct {
+	OtpID string
+}
@@ -1,6 +1,7 @@
 package tea
 
 import (
+	"encoding/json"
 	"errors"
 
 	bubbletea "github.com/charmbracelet/bubbletea"
@@ -54,3 +55,37 @@ func (m LicenseModel) HasFeature(feature string) bool {
 	}
 	return false
 }
+
+// LicenseSnapshot is a stable, loggable view of a LicenseModel. It never
+// includes the license key.
+type LicenseSnapshot struct {
+	Checking bool                       `json:"checking"`
+	Valid    bool                       `json:"valid"`
+	Reason   tuish.LicenseInvalidReason `json:"reason,omitempty"`
+	Features []string                   `json:"features"`
+	Error    string                     `json:"error,omitempty"`
+}
+
+// Snapshot returns the model's current state as a LicenseSnapshot.
+func (m LicenseModel) Snapshot() LicenseSnapshot {
+	snap := LicenseSnapshot{
+		Checking: m.Checking,
+		Valid:    m.IsValid(),
+		Features: []string{},
+	}
+	if m.Result != nil {
+		snap.Reason = m.Result.Reason
+		if m.Result.License != nil && m.Result.License.Features != nil {
+			snap.Features = m.Result.License.Features
+		}
+	}
+	if m.Err != nil {
+		snap.Error = m.Err.Error()
+	}
+	return snap
+}
+
+// MarshalJSON encodes the model as its Snapshot.
+func (m LicenseModel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Snapshot())
+}
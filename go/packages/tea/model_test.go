@@ -0,0 +1,60 @@
+package tea
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	tuish "github.com/tuishdotdev/tuish/go"
+)
+
+func TestLicenseModelMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		model LicenseModel
+		want  string
+	}{
+		{
+			name:  "checking",
+			model: LicenseModel{Checking: true},
+			want:  `{"checking":true,"valid":false,"features":[]}`,
+		},
+		{
+			name: "valid",
+			model: LicenseModel{Result: &tuish.LicenseCheckResult{
+				Valid: true,
+				License: &tuish.LicenseDetails{
+					ID:       "lic_123",
+					Features: []string{"pro", "export"},
+					Status:   tuish.LicenseStatusActive,
+				},
+			}},
+			want: `{"checking":false,"valid":true,"features":["pro","export"]}`,
+		},
+		{
+			name: "invalid",
+			model: LicenseModel{Result: &tuish.LicenseCheckResult{
+				Valid:  false,
+				Reason: tuish.ReasonExpired,
+			}},
+			want: `{"checking":false,"valid":false,"reason":"expired","features":[]}`,
+		},
+		{
+			name:  "error",
+			model: LicenseModel{Err: errors.New("network down")},
+			want:  `{"checking":false,"valid":false,"features":[],"error":"network down"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.model)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package tuish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// checkProductsWorkers bounds how many products CheckProducts checks at
+// once, so a large suite doesn't open a burst of validation requests.
+const checkProductsWorkers = 4
+
+// CheckProducts checks the cached licenses of several products that share
+// this SDK's storage and public key, such as a suite of CLIs from one
+// vendor. Each product is checked as CheckLicense would check it, with
+// online revalidations run in parallel.
+//
+// The environment license (Config.LicenseKeyEnv) only applies to this SDK's
+// own product. Config.OnCheck is called for each product, one call at a time.
+//
+// The map holds a result for every product that could be checked. Products
+// whose check failed are left out and their errors are joined into the
+// returned error. If ctx is done, products not yet checked are skipped and
+// ctx's error is included.
+func (s *SDK) CheckProducts(ctx context.Context, productIDs []string) (map[string]*LicenseCheckResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var (
+		mu      sync.Mutex
+		results = make(map[string]*LicenseCheckResult, len(productIDs))
+		errs    []error
+	)
+
+	// Products are checked in parallel, but OnCheck needn't be safe for that
+	onCheck := s.config.OnCheck
+	if onCheck != nil {
+		var onCheckMu sync.Mutex
+		onCheck = func(result *LicenseCheckResult, source string) {
+			onCheckMu.Lock()
+			defer onCheckMu.Unlock()
+			s.config.OnCheck(result, source)
+		}
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < min(checkProductsWorkers, len(productIDs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for productID := range jobs {
+				sdk := s.forProduct(productID)
+				sdk.config.OnCheck = onCheck
+				result, err := sdk.CheckLicense(ctx)
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("check %s: %w", productID, err))
+				} else {
+					results[productID] = result
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	cancelled := func() {
+		mu.Lock()
+		errs = append(errs, ctx.Err())
+		mu.Unlock()
+	}
+feed:
+	for _, productID := range productIDs {
+		if ctx.Err() != nil {
+			cancelled()
+			break
+		}
+		select {
+		case <-ctx.Done():
+			cancelled()
+			break feed
+		case jobs <- productID:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
+
+// forProduct returns an SDK that checks productID's license with this SDK's
+// client, storage, key and settings. A license from the environment belongs
+// to this SDK's product, so other products don't use it.
+func (s *SDK) forProduct(productID string) *SDK {
+	config := s.config
+	config.ProductID = productID
+	other := productID != s.config.ProductID
+	if other {
+		config.LicenseKeyEnv = ""
+		config.EnvLicensePrecedence = false
+		config.CacheEnvLicense = false
+	}
+	return &SDK{
+		config:             config,
+		client:             s.client,
+		storage:            s.storage,
+		publicKey:          s.publicKey,
+		machineFingerprint: s.GetMachineFingerprint(),
+		revoked:            s.revoked,
+		verified:           s.verified,
+		logger:             s.logger,
+		noEnvLicense:       other,
+	}
+}
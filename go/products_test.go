@@ -0,0 +1,111 @@
+package tuish
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSDKCheckProducts(t *testing.T) {
+	sdk := newOfflineSDK(t, Config{})
+	fingerprint := sdk.GetMachineFingerprint()
+
+	past := time.Now().Add(-time.Hour).UnixMilli()
+	licenses := map[string]string{
+		"prod_valid": generateTestLicenseForSDK(t, LicensePayload{
+			LicenseID: "lic_valid",
+			ProductID: "prod_valid",
+			IssuedAt:  time.Now().UnixMilli(),
+		}),
+		"prod_expired": generateTestLicenseForSDK(t, LicensePayload{
+			LicenseID: "lic_expired",
+			ProductID: "prod_expired",
+			IssuedAt:  time.Now().Add(-48 * time.Hour).UnixMilli(),
+			ExpiresAt: &past,
+		}),
+	}
+	for productID, license := range licenses {
+		if err := sdk.storage.Save(productID, license, fingerprint); err != nil {
+			t.Fatalf("save %s: %v", productID, err)
+		}
+	}
+
+	results, err := sdk.CheckProducts(context.Background(), []string{"prod_valid", "prod_expired", "prod_missing"})
+	if err != nil {
+		t.Fatalf("CheckProducts failed: %v", err)
+	}
+
+	if r := results["prod_valid"]; r == nil || !r.Valid || r.License.ID != "lic_valid" {
+		t.Errorf("expected prod_valid to be licensed, got %+v", r)
+	}
+	if r := results["prod_expired"]; r == nil || r.Valid || r.Reason != ReasonExpired {
+		t.Errorf("expected prod_expired to be expired, got %+v", r)
+	}
+	if r := results["prod_missing"]; r == nil || r.Valid || r.Reason != ReasonNotFound {
+		t.Errorf("expected prod_missing to have no license, got %+v", r)
+	}
+
+	// The SDK's own product is untouched
+	if sdk.config.ProductID != "prod_test" {
+		t.Errorf("expected SDK product to stay prod_test, got %s", sdk.config.ProductID)
+	}
+}
+
+func TestSDKCheckProductsCancelled(t *testing.T) {
+	sdk := newOfflineSDK(t, Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := sdk.CheckProducts(ctx, []string{"prod_a", "prod_b", "prod_c", "prod_d", "prod_e", "prod_f"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSDKCheckProductsIgnoresEnvLicenseForOtherProducts(t *testing.T) {
+	sdk := newOfflineSDK(t, Config{EnvLicensePrecedence: true, CacheEnvLicense: true})
+	t.Setenv(DefaultLicenseKeyEnv, generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_env",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	}))
+
+	results, err := sdk.CheckProducts(context.Background(), []string{"prod_test", "prod_other"})
+	if err != nil {
+		t.Fatalf("CheckProducts failed: %v", err)
+	}
+	if r := results["prod_test"]; r == nil || !r.Valid || r.License.ID != "lic_env" {
+		t.Errorf("expected the env license for the SDK's own product, got %+v", r)
+	}
+	if r := results["prod_other"]; r == nil || r.Reason != ReasonNotFound {
+		t.Errorf("expected prod_other to have no license, got %+v", r)
+	}
+	if cached, _ := sdk.storage.Load("prod_other"); cached != nil {
+		t.Error("expected the env license not to be cached for prod_other")
+	}
+}
+
+func TestSDKCheckProductsSerializesOnCheck(t *testing.T) {
+	active, overlapped := 0, false
+	sdk := newOfflineSDK(t, Config{
+		OnCheck: func(*LicenseCheckResult, string) {
+			// Unsynchronized on purpose: the race detector flags parallel calls
+			active++
+			if active > 1 {
+				overlapped = true
+			}
+			time.Sleep(time.Millisecond)
+			active--
+		},
+	})
+
+	products := []string{"prod_a", "prod_b", "prod_c", "prod_d", "prod_e", "prod_f"}
+	if _, err := sdk.CheckProducts(context.Background(), products); err != nil {
+		t.Fatalf("CheckProducts failed: %v", err)
+	}
+	if overlapped {
+		t.Error("expected OnCheck calls not to overlap")
+	}
+}
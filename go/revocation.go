@@ -0,0 +1,65 @@
+package tuish
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RevocationList is a signed list of revoked license IDs for checking
+// revocation without network access. It uses the same
+// "header.payload.signature" format and signing key as licenses.
+type RevocationList struct {
+	// LicenseIDs are the revoked license IDs
+	LicenseIDs []string `json:"revoked"`
+
+	// IssuedAt is when the list was issued (Unix timestamp ms)
+	IssuedAt int64 `json:"iat"`
+}
+
+// ParseRevocationList verifies a signed revocation list and returns its
+// contents. It fails with ErrInvalidSignature if the list wasn't signed by
+// publicKey.
+func ParseRevocationList(data string, publicKey ed25519.PublicKey) (*RevocationList, error) {
+	parts := strings.Split(strings.TrimSpace(data), ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, ErrInvalidFormat
+	}
+
+	signature, err := base64URLDecode(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+
+	message := []byte(parts[0] + "." + parts[1])
+	if !ed25519.Verify(publicKey, message, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	payload, err := base64URLDecode(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+
+	var list RevocationList
+	if err := json.Unmarshal(payload, &list); err != nil {
+		return nil, fmt.Errorf("parse payload: %w", err)
+	}
+	return &list, nil
+}
+
+// loadRevocationList reads and verifies a signed revocation list file.
+func loadRevocationList(path string, publicKey ed25519.PublicKey) (*RevocationList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read revocation list: %w", err)
+	}
+
+	list, err := ParseRevocationList(string(data), publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("revocation list %s: %w", path, err)
+	}
+	return list, nil
+}
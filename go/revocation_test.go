@@ -0,0 +1,130 @@
+package tuish
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// signTestRevocationList signs a revocation list with the given private key seed.
+func signTestRevocationList(t *testing.T, privateKeyHex string, ids ...string) string {
+	t.Helper()
+
+	seed, err := hex.DecodeString(privateKeyHex)
+	if err != nil {
+		t.Fatalf("decode private key: %v", err)
+	}
+	privateKey := ed25519.NewKeyFromSeed(seed)
+
+	headerBytes, _ := json.Marshal(LicenseHeader{Algorithm: "ed25519", Version: 1})
+	payloadBytes, _ := json.Marshal(RevocationList{LicenseIDs: ids, IssuedAt: time.Now().UnixMilli()})
+	message := base64URLEncode(headerBytes) + "." + base64URLEncode(payloadBytes)
+	signature := ed25519.Sign(privateKey, []byte(message))
+	return message + "." + base64URLEncode(signature)
+}
+
+func storeRevocationTestLicense(t *testing.T, sdk *SDK, licenseID string) {
+	t.Helper()
+
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: licenseID,
+		ProductID: "prod_test",
+		Features:  []string{"pro"},
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+}
+
+func TestSDKRevokedLicenseIDs(t *testing.T) {
+	sdk, err := New(Config{
+		ProductID:         "prod_test",
+		PublicKey:         testPublicKeyHex,
+		StorageDir:        t.TempDir(),
+		RevokedLicenseIDs: []string{"lic_revoked"},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	storeRevocationTestLicense(t, sdk, "lic_revoked")
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Valid || result.Reason != ReasonRevoked {
+		t.Fatalf("expected revoked result, got valid=%t reason=%s", result.Valid, result.Reason)
+	}
+	if result.License == nil || result.License.Status != LicenseStatusRevoked {
+		t.Error("expected license details with revoked status")
+	}
+
+	storeRevocationTestLicense(t, sdk, "lic_ok")
+	result, err = sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("expected unlisted license to be valid, got %s", result.Reason)
+	}
+}
+
+func TestSDKRevocationListFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "revoked.crl")
+	crl := signTestRevocationList(t, testPrivateKeyHex, "lic_revoked", "lic_other")
+	if err := os.WriteFile(path, []byte(crl), 0644); err != nil {
+		t.Fatalf("write CRL: %v", err)
+	}
+
+	sdk, err := New(Config{
+		ProductID:          "prod_test",
+		PublicKey:          testPublicKeyHex,
+		StorageDir:         t.TempDir(),
+		RevocationListFile: path,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	storeRevocationTestLicense(t, sdk, "lic_revoked")
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Valid || result.Reason != ReasonRevoked {
+		t.Errorf("expected revoked result, got valid=%t reason=%s", result.Valid, result.Reason)
+	}
+}
+
+func TestSDKRevocationListBadSignature(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "revoked.crl")
+	otherKey := "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb"
+	crl := signTestRevocationList(t, otherKey, "lic_revoked")
+	if err := os.WriteFile(path, []byte(crl), 0644); err != nil {
+		t.Fatalf("write CRL: %v", err)
+	}
+
+	_, err := New(Config{
+		ProductID:          "prod_test",
+		PublicKey:          testPublicKeyHex,
+		StorageDir:         t.TempDir(),
+		RevocationListFile: path,
+	})
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestParseRevocationListInvalidFormat(t *testing.T) {
+	publicKey, _ := ParsePublicKey(testPublicKeyHex)
+	if _, err := ParseRevocationList("not-a-crl", publicKey); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got %v", err)
+	}
+}
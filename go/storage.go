@@ -4,8 +4,13 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"time"
 )
 
@@ -14,21 +19,98 @@ const (
 	cacheRefreshHours    = 24
 )
 
+// storageLockFile is the file in the storage directory that Save and Remove
+// lock, so processes sharing the directory don't interleave writes.
+const storageLockFile = ".lock"
+
+// StorageDirEnv is the environment variable that overrides the storage
+// directory, including Config.StorageDir.
+const StorageDirEnv = "TUISH_STORAGE_DIR"
+
+// DefaultLicenseKeyEnv is the environment variable CheckLicense reads a
+// license key from when Config.LicenseKeyEnv is empty.
+const DefaultLicenseKeyEnv = "TUISH_LICENSE_KEY"
+
+// ErrStorageNotWritable is returned when the license cache can't be written
+// because of file permissions or a read-only filesystem. Setting
+// TUISH_STORAGE_DIR to a writable directory works around it.
+var ErrStorageNotWritable = errors.New("storage not writable")
+
+// storageWriteError wraps permission failures in ErrStorageNotWritable.
+func storageWriteError(err error) error {
+	if errors.Is(err, fs.ErrPermission) || isReadOnlyFSError(err) {
+		return fmt.Errorf("%w: %w", ErrStorageNotWritable, err)
+	}
+	return err
+}
+
+// storageWriteAttempts bounds how many times Save tries a write that fails
+// with a transient error, such as EIO from a home directory mounted over
+// NFS or SMB.
+const storageWriteAttempts = 3
+
+// storageWriteBackoff is the delay before the first retry of a failed
+// write. It doubles after each retry.
+var storageWriteBackoff = 50 * time.Millisecond
+
+// writeFile is os.WriteFile. Tests replace it to inject write failures.
+var writeFile = os.WriteFile
+
+// retryWrite runs write, retrying transient failures with backoff up to
+// storageWriteAttempts times. Other errors are returned at once.
+func (s *Storage) retryWrite(write func() error) error {
+	delay := storageWriteBackoff
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if err == nil || attempt == storageWriteAttempts || !isTransientWriteError(err) {
+			return err
+		}
+		s.logf("cache write failed (attempt %d of %d), retrying: %v", attempt, storageWriteAttempts, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// LicenseStore persists cached licenses, keyed by product ID.
+// Load returns nil, nil when nothing is cached for the product.
+type LicenseStore interface {
+	Save(productID, licenseKey, machineFingerprint string) error
+	Load(productID string) (*CachedLicenseData, error)
+	Remove(productID string) error
+}
+
+// newCachedLicense builds the cache entry for a license saved now.
+func newCachedLicense(productID, licenseKey, machineFingerprint string) CachedLicenseData {
+	now := nowFunc().UnixMilli()
+	return CachedLicenseData{
+		LicenseKey:         licenseKey,
+		CachedAt:           now,
+		RefreshAt:          now + cacheRefreshHours*60*60*1000,
+		ProductID:          productID,
+		MachineFingerprint: machineFingerprint,
+	}
+}
+
 // Storage handles file-based license storage.
 type Storage struct {
 	storageDir string
 	debug      bool
 	logger     func(format string, args ...any)
+
+	// legacyFilenames keeps the spec's truncated 16-character filenames so
+	// the cache can be shared with other tuish SDKs.
+	legacyFilenames bool
+
+	// namespace separates caches of the same product, e.g. per tenant.
+	namespace string
 }
 
-// NewStorage creates a new storage instance.
+// NewStorage creates a new storage instance. An empty storageDir uses
+// TUISH_STORAGE_DIR if set, then $XDG_DATA_HOME/tuish/licenses on Linux and
+// other Unix desktops, and ~/.tuish/licenses otherwise.
 func NewStorage(storageDir string, debug bool) *Storage {
 	if storageDir == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			home = "."
-		}
-		storageDir = filepath.Join(home, defaultStorageDir)
+		storageDir = defaultStoragePath(runtime.GOOS)
 	}
 
 	s := &Storage{
@@ -37,26 +119,121 @@ func NewStorage(storageDir string, debug bool) *Storage {
 	}
 
 	if debug {
-		s.logger = func(format string, args ...any) {
-			// Simple debug logging
-		}
+		s.logger = defaultLogger
 	}
 
 	return s
 }
 
+// defaultStoragePath returns the default storage directory on goos.
+func defaultStoragePath(goos string) string {
+	if dir := os.Getenv(StorageDirEnv); dir != "" {
+		return dir
+	}
+	if goos != "darwin" && goos != "windows" {
+		if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+			return filepath.Join(dataHome, "tuish", "licenses")
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, defaultStorageDir)
+}
+
+// logf writes a debug line if logging is enabled.
+func (s *Storage) logf(format string, args ...any) {
+	if s.logger != nil {
+		s.logger(format, args...)
+	}
+}
+
 // ensureDir creates the storage directory if it doesn't exist.
 func (s *Storage) ensureDir() error {
-	return os.MkdirAll(s.storageDir, 0700)
+	return storageWriteError(os.MkdirAll(s.storageDir, 0700))
+}
+
+// lock takes an exclusive advisory lock on the storage directory, blocking
+// until other processes release it, and returns the func that releases it.
+// If the lock can't be taken, e.g. on a filesystem without lock support,
+// it logs why and the caller carries on unlocked.
+func (s *Storage) lock() (unlock func()) {
+	f, err := os.OpenFile(filepath.Join(s.storageDir, storageLockFile), os.O_CREATE|os.O_RDWR, 0600)
+	if err == nil {
+		if err = lockFile(f); err != nil {
+			f.Close()
+		}
+	}
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logf("cache lock unavailable, continuing unlocked: %v", err)
+		}
+		return func() {}
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}
 }
 
 // getLicenseFilePath returns the file path for a product's license cache.
 func (s *Storage) getLicenseFilePath(productID string) string {
-	hash := sha256.Sum256([]byte(productID))
-	filename := hex.EncodeToString(hash[:8]) + ".json"
+	if s.legacyFilenames {
+		return s.getLegacyLicenseFilePath(productID)
+	}
+	hash := sha256.Sum256([]byte(s.cacheKey(productID)))
+	filename := hex.EncodeToString(hash[:]) + cacheFileExt(productID)
 	return filepath.Join(s.storageDir, filename)
 }
 
+// cacheKey returns what a product's cache filename is hashed from. Without
+// a namespace it is the product ID, so existing filenames are unchanged.
+func (s *Storage) cacheKey(productID string) string {
+	if s.namespace == "" {
+		return productID
+	}
+	return productID + "\x00" + s.namespace
+}
+
+// cacheFileExt returns the extension of a cache file. Trial markers get their
+// own so ClearAll, which removes cached licenses, leaves them in place.
+func cacheFileExt(productID string) string {
+	if strings.HasSuffix(productID, trialKeySuffix) {
+		return ".trial"
+	}
+	return ".json"
+}
+
+// getLegacyLicenseFilePath returns the path used by earlier versions, which
+// truncated the hash to 8 bytes.
+func (s *Storage) getLegacyLicenseFilePath(productID string) string {
+	hash := sha256.Sum256([]byte(s.cacheKey(productID)))
+	filename := hex.EncodeToString(hash[:8]) + cacheFileExt(productID)
+	return filepath.Join(s.storageDir, filename)
+}
+
+// migrateLegacyFile copies a cache file written under the legacy name to the
+// current one. The legacy file is left in place for other tuish SDKs sharing
+// the directory. It reports whether a legacy file was found.
+func (s *Storage) migrateLegacyFile(productID string) bool {
+	legacyPath := s.getLegacyLicenseFilePath(productID)
+	filePath := s.getLicenseFilePath(productID)
+	if legacyPath == filePath || s.namespace != "" {
+		return false
+	}
+	data, err := os.ReadFile(legacyPath)
+	if err != nil {
+		return false
+	}
+	if err := writeFile(filePath, data, 0600); err != nil {
+		s.logf("cache migration failed for %s: %v", productID, err)
+		return false
+	}
+	s.logf("cache migrated for %s", productID)
+	return true
+}
+
 // Save saves a license to disk.
 func (s *Storage) Save(productID, licenseKey, machineFingerprint string) error {
 	if err := s.ensureDir(); err != nil {
@@ -64,22 +241,18 @@ func (s *Storage) Save(productID, licenseKey, machineFingerprint string) error {
 	}
 
 	filePath := s.getLicenseFilePath(productID)
-	now := time.Now().UnixMilli()
-
-	data := CachedLicenseData{
-		LicenseKey:         licenseKey,
-		CachedAt:           now,
-		RefreshAt:          now + cacheRefreshHours*60*60*1000,
-		ProductID:          productID,
-		MachineFingerprint: machineFingerprint,
-	}
+	data := newCachedLicense(productID, licenseKey, machineFingerprint)
 
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(filePath, jsonData, 0600)
+	s.logf("cache save for %s: %s", productID, RedactLicenseKey(licenseKey))
+	defer s.lock()()
+	return storageWriteError(s.retryWrite(func() error {
+		return writeFile(filePath, jsonData, 0600)
+	}))
 }
 
 // Load loads a cached license from disk.
@@ -87,24 +260,54 @@ func (s *Storage) Load(productID string) (*CachedLicenseData, error) {
 	filePath := s.getLicenseFilePath(productID)
 
 	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) && s.migrateLegacyFile(productID) {
+		data, err = os.ReadFile(filePath)
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
+			s.logf("cache miss for %s", productID)
 			return nil, nil
 		}
 		return nil, err
 	}
 
 	var cached CachedLicenseData
-	if err := json.Unmarshal(data, &cached); err != nil {
-		return nil, err
+	err = json.Unmarshal(data, &cached)
+	if err != nil {
+		// The file may have been read mid-write by another process. Save
+		// holds the lock while writing, so read again once it's released.
+		unlock := s.lock()
+		data, err = os.ReadFile(filePath)
+		unlock()
+		if os.IsNotExist(err) {
+			s.logf("cache miss for %s", productID)
+			return nil, nil
+		}
+		if err == nil {
+			err = json.Unmarshal(data, &cached)
+		}
+	}
+	if err != nil {
+		s.logf("cache unreadable for %s: %v", productID, err)
+		return nil, fmt.Errorf("parse cached license for %s: %w", productID, err)
 	}
 
+	s.logf("cache hit for %s: %s", productID, RedactLicenseKey(cached.LicenseKey))
 	return &cached, nil
 }
 
 // Remove removes a cached license.
 func (s *Storage) Remove(productID string) error {
 	filePath := s.getLicenseFilePath(productID)
+	s.logf("cache remove for %s", productID)
+	defer s.lock()()
+
+	// Remove the legacy copy too, so neither the next Load nor another SDK
+	// sharing the directory finds the removed license
+	if legacyPath := s.getLegacyLicenseFilePath(productID); legacyPath != filePath {
+		os.Remove(legacyPath)
+	}
+
 	err := os.Remove(filePath)
 	if os.IsNotExist(err) {
 		return nil
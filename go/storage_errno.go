@@ -0,0 +1,25 @@
+//go:build !plan9
+
+package tuish
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isReadOnlyFSError reports whether err is EROFS, from writing to a
+// read-only filesystem.
+func isReadOnlyFSError(err error) bool {
+	return errors.Is(err, syscall.EROFS)
+}
+
+// isTransientWriteError reports whether a failed write may succeed if it is
+// retried. Permission errors and ENOSPC are permanent.
+func isTransientWriteError(err error) bool {
+	for _, errno := range []syscall.Errno{syscall.EIO, syscall.EAGAIN, syscall.EINTR, syscall.EBUSY, syscall.ETIMEDOUT} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+//go:build plan9
+
+package tuish
+
+// isReadOnlyFSError reports false: Plan 9 errors are strings without errno
+// values to match.
+func isReadOnlyFSError(err error) bool {
+	return false
+}
+
+// isTransientWriteError reports false, so failed writes aren't retried.
+func isTransientWriteError(err error) bool {
+	return false
+}
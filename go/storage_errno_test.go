@@ -0,0 +1,61 @@
+//go:build !plan9
+
+package tuish
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+	"testing"
+)
+
+func TestStorageWriteErrorReadOnlyFS(t *testing.T) {
+	readOnly := &fs.PathError{Op: "open", Path: "/ro/cache.json", Err: syscall.EROFS}
+	if err := storageWriteError(readOnly); !errors.Is(err, ErrStorageNotWritable) {
+		t.Errorf("expected EROFS to be ErrStorageNotWritable, got %v", err)
+	}
+}
+
+func TestStorageRetriesTransientWriteErrors(t *testing.T) {
+	calls := failWrites(t, syscall.EIO)
+	storage := NewStorage(t.TempDir(), false)
+
+	if err := storage.Save("prod_test", "license", "fingerprint"); err != nil {
+		t.Fatalf("expected Save to succeed after a retry, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("expected 2 write attempts, got %d", *calls)
+	}
+	if cached, err := storage.Load("prod_test"); err != nil || cached == nil || cached.LicenseKey != "license" {
+		t.Errorf("expected the license to be saved, got %+v, %v", cached, err)
+	}
+}
+
+func TestStorageGivesUpOnRepeatedWriteErrors(t *testing.T) {
+	calls := failWrites(t, syscall.EIO, syscall.EIO, syscall.EIO, syscall.EIO)
+	storage := NewStorage(t.TempDir(), false)
+
+	err := storage.Save("prod_test", "license", "fingerprint")
+	if !errors.Is(err, syscall.EIO) {
+		t.Fatalf("expected EIO after the last attempt, got %v", err)
+	}
+	if *calls != storageWriteAttempts {
+		t.Errorf("expected %d write attempts, got %d", storageWriteAttempts, *calls)
+	}
+}
+
+func TestStorageFailsFastOnPermanentWriteErrors(t *testing.T) {
+	for _, errno := range []syscall.Errno{syscall.EACCES, syscall.ENOSPC} {
+		t.Run(errno.Error(), func(t *testing.T) {
+			calls := failWrites(t, errno)
+			storage := NewStorage(t.TempDir(), false)
+
+			if err := storage.Save("prod_test", "license", "fingerprint"); !errors.Is(err, errno) {
+				t.Fatalf("expected %v, got %v", errno, err)
+			}
+			if *calls != 1 {
+				t.Errorf("expected a single write attempt, got %d", *calls)
+			}
+		})
+	}
+}
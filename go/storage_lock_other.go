@@ -0,0 +1,19 @@
+//go:build !unix && !windows
+
+package tuish
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile reports that file locking isn't supported, so Storage writes
+// unlocked.
+func lockFile(f *os.File) error {
+	return errors.ErrUnsupported
+}
+
+// unlockFile is a no-op.
+func unlockFile(f *os.File) error {
+	return nil
+}
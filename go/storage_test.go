@@ -1,16 +1,43 @@
 package tuish
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
 
+// cacheFiles returns the cache files in dir, leaving out the lock file.
+func cacheFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	return files
+}
+
+// storeBackends returns each LicenseStore implementation under test.
+func storeBackends(t *testing.T) map[string]LicenseStore {
+	return map[string]LicenseStore{
+		"file":   NewStorage(t.TempDir(), false),
+		"memory": NewMemoryStore(),
+	}
+}
+
 func TestStorageSaveLoad(t *testing.T) {
-	tempDir := t.TempDir()
-	storage := NewStorage(tempDir, false)
+	for name, storage := range storeBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			testStoreSaveLoad(t, storage)
+		})
+	}
+}
 
+func testStoreSaveLoad(t *testing.T, storage LicenseStore) {
 	productID := "prod_test123"
 	licenseKey := "header.payload.signature"
 	fingerprint := "abc123def456"
@@ -56,23 +83,29 @@ func TestStorageSaveLoad(t *testing.T) {
 }
 
 func TestStorageLoadNotFound(t *testing.T) {
-	tempDir := t.TempDir()
-	storage := NewStorage(tempDir, false)
-
-	cached, err := storage.Load("nonexistent_product")
-	if err != nil {
-		t.Fatalf("Load failed: %v", err)
-	}
-
-	if cached != nil {
-		t.Errorf("expected nil for nonexistent product, got %+v", cached)
+	for name, storage := range storeBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			cached, err := storage.Load("nonexistent_product")
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+
+			if cached != nil {
+				t.Errorf("expected nil for nonexistent product, got %+v", cached)
+			}
+		})
 	}
 }
 
 func TestStorageRemove(t *testing.T) {
-	tempDir := t.TempDir()
-	storage := NewStorage(tempDir, false)
+	for name, storage := range storeBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			testStoreRemove(t, storage)
+		})
+	}
+}
 
+func testStoreRemove(t *testing.T, storage LicenseStore) {
 	productID := "prod_toremove"
 	err := storage.Save(productID, "license", "fingerprint")
 	if err != nil {
@@ -99,13 +132,88 @@ func TestStorageRemove(t *testing.T) {
 }
 
 func TestStorageRemoveNonexistent(t *testing.T) {
-	tempDir := t.TempDir()
-	storage := NewStorage(tempDir, false)
+	for name, storage := range storeBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			// Should not error when removing nonexistent
+			err := storage.Remove("nonexistent")
+			if err != nil {
+				t.Errorf("Remove nonexistent should not error, got: %v", err)
+			}
+		})
+	}
+}
 
-	// Should not error when removing nonexistent
-	err := storage.Remove("nonexistent")
-	if err != nil {
-		t.Errorf("Remove nonexistent should not error, got: %v", err)
+func TestMemoryStoreConcurrentUse(t *testing.T) {
+	store := NewMemoryStore()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			productID := fmt.Sprintf("prod_%d", i%4)
+			store.Save(productID, "license", "fingerprint")
+			store.Load(productID)
+			if i%3 == 0 {
+				store.Remove(productID)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestStorageConcurrentProcesses(t *testing.T) {
+	// Separate Storage values share nothing in memory, like two processes
+	dir := t.TempDir()
+	const iterations = 200
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		storage := NewStorage(dir, false)
+		for i := 0; i < iterations; i++ {
+			if err := storage.Save("prod_test", "license_a", "fingerprint"); err != nil {
+				t.Errorf("Save failed: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		storage := NewStorage(dir, false)
+		for i := 0; i < iterations; i++ {
+			var err error
+			if i%2 == 0 {
+				err = storage.Save("prod_test", "license_b", "fingerprint")
+			} else {
+				err = storage.Remove("prod_test")
+			}
+			if err != nil {
+				t.Errorf("Save or Remove failed: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		storage := NewStorage(dir, false)
+		for i := 0; i < iterations; i++ {
+			cached, err := storage.Load("prod_test")
+			if err != nil {
+				t.Errorf("Load saw a corrupt cache: %v", err)
+				return
+			}
+			if cached != nil && cached.LicenseKey != "license_a" && cached.LicenseKey != "license_b" {
+				t.Errorf("Load returned unexpected key %q", cached.LicenseKey)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	if _, err := NewStorage(dir, false).Load("prod_test"); err != nil {
+		t.Errorf("expected a readable cache afterwards, got %v", err)
 	}
 }
 
@@ -148,15 +256,53 @@ func TestStorageNeedsRefresh(t *testing.T) {
 	}
 }
 
-func TestStorageDefaultDir(t *testing.T) {
-	storage := NewStorage("", false)
+func TestCachedLicenseNextRefresh(t *testing.T) {
+	cached := &CachedLicenseData{RefreshAt: time.Now().UnixMilli() + 10000}
+	if got, want := cached.NextRefresh(), time.UnixMilli(cached.RefreshAt); !got.Equal(want) {
+		t.Errorf("expected NextRefresh %v, got %v", want, got)
+	}
+}
 
+func TestStorageDefaultDir(t *testing.T) {
+	t.Setenv(StorageDirEnv, "")
+	t.Setenv("XDG_DATA_HOME", "")
 	home, _ := os.UserHomeDir()
-	expected := filepath.Join(home, ".tuish", "licenses")
+	homeDir := filepath.Join(home, ".tuish", "licenses")
 
-	if storage.GetStorageDir() != expected {
-		t.Errorf("expected default dir %s, got %s", expected, storage.GetStorageDir())
+	storage := NewStorage("", false)
+	if storage.GetStorageDir() != homeDir {
+		t.Errorf("expected default dir %s, got %s", homeDir, storage.GetStorageDir())
 	}
+
+	t.Run("XDG_DATA_HOME", func(t *testing.T) {
+		dataHome := t.TempDir()
+		t.Setenv("XDG_DATA_HOME", dataHome)
+
+		xdgDir := filepath.Join(dataHome, "tuish", "licenses")
+		if got := defaultStoragePath("linux"); got != xdgDir {
+			t.Errorf("expected %s on linux, got %s", xdgDir, got)
+		}
+		for _, goos := range []string{"darwin", "windows"} {
+			if got := defaultStoragePath(goos); got != homeDir {
+				t.Errorf("expected %s on %s, got %s", homeDir, goos, got)
+			}
+		}
+	})
+
+	t.Run(StorageDirEnv, func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(StorageDirEnv, dir)
+		t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+		for _, goos := range []string{"linux", "darwin", "windows"} {
+			if got := defaultStoragePath(goos); got != dir {
+				t.Errorf("expected %s on %s, got %s", dir, goos, got)
+			}
+		}
+		if got := NewStorage("", false).GetStorageDir(); got != dir {
+			t.Errorf("expected NewStorage to use %s, got %s", dir, got)
+		}
+	})
 }
 
 func TestStorageFilePermissions(t *testing.T) {
@@ -169,12 +315,12 @@ func TestStorageFilePermissions(t *testing.T) {
 	}
 
 	// Find the created file
-	entries, _ := os.ReadDir(tempDir)
-	if len(entries) != 1 {
-		t.Fatalf("expected 1 file, got %d", len(entries))
+	files := cacheFiles(t, tempDir)
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
 	}
 
-	filePath := filepath.Join(tempDir, entries[0].Name())
+	filePath := files[0]
 	info, err := os.Stat(filePath)
 	if err != nil {
 		t.Fatalf("Stat failed: %v", err)
@@ -186,3 +332,122 @@ func TestStorageFilePermissions(t *testing.T) {
 		t.Errorf("expected permissions 0600, got %o", perm)
 	}
 }
+
+func TestStorageReadOnlyDir(t *testing.T) {
+	parent := t.TempDir()
+	if err := os.Chmod(parent, 0500); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	t.Cleanup(func() { os.Chmod(parent, 0700) })
+
+	storage := NewStorage(filepath.Join(parent, "licenses"), false)
+	err := storage.Save("prod_test", "license", "fingerprint")
+	if err == nil {
+		t.Skip("directory permissions are not enforced for this user")
+	}
+	if !errors.Is(err, ErrStorageNotWritable) {
+		t.Fatalf("expected ErrStorageNotWritable, got %v", err)
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("expected the permission error to stay wrapped, got %v", err)
+	}
+}
+
+func TestStorageWriteError(t *testing.T) {
+	denied := &fs.PathError{Op: "open", Path: "/ro/cache.json", Err: fs.ErrPermission}
+	if err := storageWriteError(denied); !errors.Is(err, ErrStorageNotWritable) {
+		t.Errorf("expected permission error to be ErrStorageNotWritable, got %v", err)
+	}
+
+	other := errors.New("disk full")
+	if err := storageWriteError(other); errors.Is(err, ErrStorageNotWritable) || err != other {
+		t.Errorf("expected other errors unchanged, got %v", err)
+	}
+	if storageWriteError(nil) != nil {
+		t.Error("expected nil to stay nil")
+	}
+}
+
+// failWrites makes the next len(errs) writes fail with errs in turn, then
+// lets writes through. It returns how many writes were attempted.
+func failWrites(t *testing.T, errs ...error) *int {
+	t.Helper()
+	calls := 0
+	origWrite, origBackoff := writeFile, storageWriteBackoff
+	writeFile = func(name string, data []byte, perm os.FileMode) error {
+		calls++
+		if calls <= len(errs) {
+			return &fs.PathError{Op: "write", Path: name, Err: errs[calls-1]}
+		}
+		return os.WriteFile(name, data, perm)
+	}
+	storageWriteBackoff = 0
+	t.Cleanup(func() { writeFile, storageWriteBackoff = origWrite, origBackoff })
+	return &calls
+}
+
+func TestStorageFilenameUsesFullHash(t *testing.T) {
+	tempDir := t.TempDir()
+	storage := NewStorage(tempDir, false)
+
+	if err := storage.Save("prod_test", "license", "fingerprint"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	files := cacheFiles(t, tempDir)
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	// 64 hex characters + ".json"
+	if name := filepath.Base(files[0]); len(name) != 69 {
+		t.Errorf("expected full-hash filename, got %s", name)
+	}
+}
+
+func TestStorageMigratesLegacyFilename(t *testing.T) {
+	tempDir := t.TempDir()
+	productID := "prod_legacy"
+
+	// Write a cache file under the old truncated name
+	legacy := NewStorage(tempDir, false)
+	legacy.legacyFilenames = true
+	if err := legacy.Save(productID, "license", "fingerprint"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	legacyPath := legacy.getLicenseFilePath(productID)
+
+	storage := NewStorage(tempDir, false)
+	cached, err := storage.Load(productID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cached == nil || cached.LicenseKey != "license" {
+		t.Fatalf("expected legacy license to be loaded, got %+v", cached)
+	}
+
+	if _, err := os.Stat(legacyPath); err != nil {
+		t.Errorf("expected legacy file to be kept for other SDKs: %v", err)
+	}
+	if _, err := os.Stat(storage.getLicenseFilePath(productID)); err != nil {
+		t.Errorf("expected cache under the new filename: %v", err)
+	}
+}
+
+func TestStorageRemoveDeletesLegacyFilename(t *testing.T) {
+	tempDir := t.TempDir()
+	productID := "prod_legacy"
+
+	legacy := NewStorage(tempDir, false)
+	legacy.legacyFilenames = true
+	legacy.Save(productID, "license", "fingerprint")
+
+	storage := NewStorage(tempDir, false)
+	if err := storage.Remove(productID); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+
+	cached, _ := storage.Load(productID)
+	if cached != nil {
+		t.Error("expected legacy license to be removed")
+	}
+}
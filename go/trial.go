@@ -0,0 +1,179 @@
+package tuish
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTrialStarted is returned by StartTrial when this machine already has a
+// trial marker for the product, whether or not the trial is still running.
+var ErrTrialStarted = errors.New("trial already started")
+
+// trialLicenseID is the license ID reported for trial results.
+const trialLicenseID = "trial"
+
+// trialMarker is the signed record of a local trial.
+type trialMarker struct {
+	ProductID string `json:"pid"`
+	StartedAt int64  `json:"start"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// trialKeySuffix marks the store key the trial marker is saved under,
+// alongside (not instead of) the product's cached license.
+const trialKeySuffix = ":trial"
+
+// trialStoreKey is the store key of a product's trial marker.
+func trialStoreKey(productID string) string {
+	return productID + trialKeySuffix
+}
+
+// StartTrial starts a local trial of the given length. No license is issued:
+// CheckLicense reports the product as valid with IsTrial set until the trial
+// ends, and as expired afterwards. The marker is bound to this machine's
+// fingerprint, so it can't be copied to another machine, and is signed with
+// the product key and Config.TrialSecret, so it can't be forged from what is
+// stored beside it. Later calls return ErrTrialStarted.
+//
+// Enforcement is best effort: ClearLicense and Storage.ClearAll keep the
+// marker, but deleting it from the storage directory (or a custom Store)
+// allows another trial.
+func (s *SDK) StartTrial(days int) error {
+	if days <= 0 {
+		return fmt.Errorf("trial length must be positive, got %d days", days)
+	}
+
+	existing, err := s.storage.Load(trialStoreKey(s.config.ProductID))
+	if err != nil {
+		return fmt.Errorf("load trial marker: %w", err)
+	}
+	if existing != nil {
+		return ErrTrialStarted
+	}
+
+	now := nowFunc()
+	marker := trialMarker{
+		ProductID: s.config.ProductID,
+		StartedAt: now.UnixMilli(),
+		ExpiresAt: now.AddDate(0, 0, days).UnixMilli(),
+	}
+
+	machineFingerprint := s.GetMachineFingerprint()
+	encoded, err := signTrialMarker(marker, s.trialKey(machineFingerprint))
+	if err != nil {
+		return err
+	}
+
+	s.logf("starting %d day trial for %s", days, s.config.ProductID)
+	return s.storage.Save(trialStoreKey(s.config.ProductID), encoded, machineFingerprint)
+}
+
+// checkTrial returns the trial result for this machine, or nil if no trial
+// has been started.
+func (s *SDK) checkTrial(machineFingerprint string) *LicenseCheckResult {
+	cached, err := s.storage.Load(trialStoreKey(s.config.ProductID))
+	if err != nil || cached == nil {
+		return nil
+	}
+
+	marker, err := verifyTrialMarker(cached.LicenseKey, s.trialKey(machineFingerprint))
+	if err != nil || marker.ProductID != s.config.ProductID {
+		s.logf("trial marker rejected: %v", err)
+		return &LicenseCheckResult{
+			Valid:           false,
+			Reason:          ReasonInvalidSignature,
+			OfflineVerified: true,
+			IsTrial:         true,
+			Source:          LicenseSourceOffline,
+		}
+	}
+
+	expiresAt := marker.ExpiresAt
+	result := &LicenseCheckResult{
+		Valid:           true,
+		OfflineVerified: true,
+		IsTrial:         true,
+		Source:          LicenseSourceOffline,
+		License: &LicenseDetails{
+			ID:        trialLicenseID,
+			ProductID: marker.ProductID,
+			Features:  []string{},
+			Status:    LicenseStatusActive,
+			IssuedAt:  marker.StartedAt,
+			ExpiresAt: &expiresAt,
+		},
+	}
+
+	if nowFunc().UnixMilli() >= expiresAt {
+		result.Valid = false
+		result.Reason = ReasonExpired
+		result.License.Status = LicenseStatusExpired
+	}
+	return result
+}
+
+// signTrialMarker encodes a marker as "trial.<payload>.<mac>", where the MAC
+// is keyed by key.
+func signTrialMarker(marker trialMarker, key []byte) (string, error) {
+	payload, err := json.Marshal(marker)
+	if err != nil {
+		return "", fmt.Errorf("encode trial marker: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	mac := trialMAC(encoded, key)
+	return "trial." + encoded + "." + base64.RawURLEncoding.EncodeToString(mac), nil
+}
+
+// verifyTrialMarker decodes a marker, checking it was signed with key.
+func verifyTrialMarker(value string, key []byte) (*trialMarker, error) {
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 || parts[0] != "trial" {
+		return nil, ErrInvalidFormat
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	if !hmac.Equal(mac, trialMAC(parts[1], key)) {
+		return nil, ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+
+	var marker trialMarker
+	if err := json.Unmarshal(payload, &marker); err != nil {
+		return nil, ErrInvalidFormat
+	}
+	return &marker, nil
+}
+
+// trialKey derives the trial MAC key for this product on the given machine.
+// The product key and TrialSecret come from the application rather than the
+// storage directory, so the marker can't be re-signed from the files alone.
+func (s *SDK) trialKey(machineFingerprint string) []byte {
+	h := sha256.New()
+	h.Write([]byte("tuish-trial:"))
+	h.Write(s.publicKey)
+	h.Write([]byte{0})
+	h.Write([]byte(s.config.TrialSecret))
+	h.Write([]byte{0})
+	h.Write([]byte(machineFingerprint))
+	return h.Sum(nil)
+}
+
+// trialMAC signs a trial payload with key.
+func trialMAC(payload string, key []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
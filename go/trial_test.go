@@ -0,0 +1,191 @@
+package tuish
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTrialSDK(t *testing.T, store LicenseStore, machineFingerprint string) *SDK {
+	t.Helper()
+
+	sdk, err := New(Config{
+		ProductID: "prod_test",
+		PublicKey: testPublicKeyHex,
+		Store:     store,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	sdk.machineFingerprint = machineFingerprint
+	return sdk
+}
+
+func TestSDKTrialActive(t *testing.T) {
+	sdk := newTrialSDK(t, NewMemoryStore(), "machine_a")
+
+	if err := sdk.StartTrial(14); err != nil {
+		t.Fatalf("StartTrial failed: %v", err)
+	}
+
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid || !result.IsTrial {
+		t.Fatalf("expected valid trial, got %+v", result)
+	}
+	if result.License == nil || result.License.ExpiresAt == nil {
+		t.Fatal("expected trial license details with an expiry")
+	}
+	remaining := time.Until(time.UnixMilli(*result.License.ExpiresAt))
+	if remaining < 13*24*time.Hour || remaining > 14*24*time.Hour {
+		t.Errorf("expected ~14 days remaining, got %v", remaining)
+	}
+
+	// Restarting must not extend the trial
+	if err := sdk.StartTrial(30); !errors.Is(err, ErrTrialStarted) {
+		t.Errorf("expected ErrTrialStarted, got %v", err)
+	}
+}
+
+func TestSDKTrialExpired(t *testing.T) {
+	store := NewMemoryStore()
+	sdk := newTrialSDK(t, store, "machine_a")
+
+	start := time.Now().AddDate(0, 0, -15)
+	marker, err := signTrialMarker(trialMarker{
+		ProductID: "prod_test",
+		StartedAt: start.UnixMilli(),
+		ExpiresAt: start.AddDate(0, 0, 14).UnixMilli(),
+	}, sdk.trialKey("machine_a"))
+	if err != nil {
+		t.Fatalf("signTrialMarker failed: %v", err)
+	}
+	store.Save(trialStoreKey("prod_test"), marker, "machine_a")
+
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Valid {
+		t.Fatal("expected expired trial to be invalid")
+	}
+	if !result.IsTrial || result.Reason != ReasonExpired {
+		t.Errorf("expected expired trial, got IsTrial=%t reason=%s", result.IsTrial, result.Reason)
+	}
+
+	if err := sdk.StartTrial(14); !errors.Is(err, ErrTrialStarted) {
+		t.Errorf("expected ErrTrialStarted after expiry, got %v", err)
+	}
+}
+
+func TestSDKTrialCopiedMarker(t *testing.T) {
+	store := NewMemoryStore()
+
+	if err := newTrialSDK(t, store, "machine_a").StartTrial(14); err != nil {
+		t.Fatalf("StartTrial failed: %v", err)
+	}
+
+	// Same marker, different machine
+	other := newTrialSDK(t, store, "machine_b")
+	result, err := other.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Valid {
+		t.Fatal("expected copied trial marker to be rejected")
+	}
+	if result.Reason != ReasonInvalidSignature {
+		t.Errorf("expected reason %s, got %s", ReasonInvalidSignature, result.Reason)
+	}
+
+	if err := other.StartTrial(14); !errors.Is(err, ErrTrialStarted) {
+		t.Errorf("expected copied marker to block a new trial, got %v", err)
+	}
+}
+
+func TestSDKTrialLicenseTakesPrecedence(t *testing.T) {
+	sdk := newTrialSDK(t, NewMemoryStore(), "machine_a")
+
+	if err := sdk.StartTrial(14); err != nil {
+		t.Fatalf("StartTrial failed: %v", err)
+	}
+
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		Features:  []string{"pro"},
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid || result.IsTrial || result.License.ID != "lic_test" {
+		t.Errorf("expected the issued license, got %+v", result)
+	}
+}
+
+func TestSDKTrialForgedMarker(t *testing.T) {
+	store := NewMemoryStore()
+	sdk := newTrialSDK(t, store, "machine_a")
+	sdk.config.TrialSecret = "build-secret"
+
+	// A marker re-signed without the app's secret, e.g. to extend the trial
+	start := time.Now()
+	forged, err := signTrialMarker(trialMarker{
+		ProductID: "prod_test",
+		StartedAt: start.UnixMilli(),
+		ExpiresAt: start.AddDate(1, 0, 0).UnixMilli(),
+	}, newTrialSDK(t, store, "machine_a").trialKey("machine_a"))
+	if err != nil {
+		t.Fatalf("signTrialMarker failed: %v", err)
+	}
+	store.Save(trialStoreKey("prod_test"), forged, "machine_a")
+
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Valid || result.Reason != ReasonInvalidSignature {
+		t.Errorf("expected forged marker to be rejected, got %+v", result)
+	}
+}
+
+func TestSDKTrialSurvivesClear(t *testing.T) {
+	sdk, err := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if err := sdk.StartTrial(14); err != nil {
+		t.Fatalf("StartTrial failed: %v", err)
+	}
+	if err := sdk.ClearLicense(); err != nil {
+		t.Fatalf("ClearLicense failed: %v", err)
+	}
+	if err := sdk.GetStorage().ClearAll(); err != nil {
+		t.Fatalf("ClearAll failed: %v", err)
+	}
+
+	if err := sdk.StartTrial(14); !errors.Is(err, ErrTrialStarted) {
+		t.Errorf("expected clearing licenses to keep the trial marker, got %v", err)
+	}
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid || !result.IsTrial {
+		t.Errorf("expected the trial to still be running, got %+v", result)
+	}
+}
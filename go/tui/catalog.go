@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Catalog translates the fixed strings components show. Keys are listed in
+// EnglishCatalog; args fill the fmt verbs in the translated string.
+type Catalog interface {
+	T(key string, args ...any) string
+}
+
+// MapCatalog is a Catalog of fmt format strings keyed by message key. Keys
+// it doesn't have fall back to EnglishCatalog, so a translation can be
+// partial.
+type MapCatalog map[string]string
+
+// T returns the translation for key, formatted with args.
+func (c MapCatalog) T(key string, args ...any) string {
+	format, ok := c[key]
+	if !ok {
+		format, ok = EnglishCatalog[key]
+	}
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// EnglishCatalog is the default catalog. Copy its keys to write a
+// translation.
+var EnglishCatalog = MapCatalog{
+	// Shared
+	"date.format":      "Jan 2, 2006",
+	"date.time_format": "Jan 2, 2006 15:04",
+	"date.never":       "Never",
+	"checking_license": "Checking license...",
+	"hint.exit":        "Exit",
+	"hint.go_back":     "go back",
+	"hint.cancel":      "cancel",
+	"hint.scroll":      "scroll",
+
+	// LicenseStatus
+	"status.error":               "Error: %v",
+	"status.revoked":             "Revoked",
+	"status.revoked_title":       "License revoked",
+	"status.revoked_body":        "This license has been revoked and can no longer be used.",
+	"status.revoked_help":        "Purchase a new license, or contact support if you think this is a mistake.",
+	"status.no_license":          "No license",
+	"status.licensed":            "Licensed",
+	"status.license":             "License",
+	"status.feature_count_one":   "%d feature",
+	"status.feature_count_other": "%d features",
+	"status.offline":             "(offline)",
+	"status.offline_stale":       "(offline \u2014 server unreachable, using cached license)",
+	"status.status_label":        "Status: ",
+	"status.features_label":      "Features:",
+	"status.expires_label":       "Expires: ",
+	"status.more_features":       "%d more",
+	"status.features_above":      "%d above",
+	"status.renew_today":         "Expires today, renew soon",
+	"status.renew_tomorrow":      "Expires tomorrow, renew soon",
+	"status.renew_days":          "Expires in %d days, renew soon",
+
+	// LicenseGate
+	"gate.feature_required_title": "Feature Required",
+	"gate.feature_required_body":  "The \"%s\" feature requires a valid license.",
+	"gate.feature_required_help":  "Please upgrade to access this feature.",
+	"gate.license_required_title": "License Required",
+	"gate.license_required_body":  "A valid license is required to access this application.",
+	"gate.license_required_help":  "Please purchase a license to continue.",
+	"gate.refresh_hint":           "Refresh your license \u2014 it may now include '%s'.",
+	"gate.refresh":                "refresh license",
+	"gate.expired":                "Your license has expired.",
+	"gate.expired_help":           "Renew it to continue.",
+	"gate.revoked":                "Your license has been revoked.",
+	"gate.revoked_help":           "Contact support if you think this is a mistake.",
+	"gate.machine_mismatch":       "Your license is activated on another machine.",
+	"gate.machine_mismatch_help":  "Move it to this machine from the license manager.",
+	"gate.not_found":              "No license was found.",
+	"gate.not_found_help":         "Purchase a license to continue.",
+	"gate.missing_feature":        "Your plan doesn't include \"%s\".",
+	"gate.missing_feature_help":   "Upgrade your plan to use it.",
+
+	// PurchaseFlow
+	"purchase.initializing":    "Initializing...",
+	"purchase.checkout":        "CHECKOUT",
+	"purchase.setting_up":      "Setting up secure checkout...",
+	"purchase.waiting_title":   "COMPLETE YOUR PURCHASE",
+	"purchase.scan_step":       "Scan the QR code with your phone",
+	"purchase.pay_step":        "Complete payment in your browser",
+	"purchase.return_step":     "Return here - we'll detect it automatically",
+	"purchase.waiting":         "Waiting for payment",
+	"purchase.open_url":        "Open this URL to purchase: %s",
+	"purchase.cancel":          "Cancel",
+	"purchase.success_title":   "PURCHASE SUCCESSFUL!",
+	"purchase.activated":       "License Activated",
+	"purchase.features":        "Features unlocked:",
+	"purchase.valid_until":     "Valid until: ",
+	"purchase.paid":            "Paid: ",
+	"purchase.receipt":         "Receipt: ",
+	"purchase.thank_you":       "Thank you for your purchase!",
+	"purchase.failed_title":    "PURCHASE FAILED",
+	"purchase.unexpected":      "An unexpected error occurred",
+	"purchase.error_details":   "Error Details:",
+	"purchase.retry":           "Retry",
+	"purchase.support":         "Need help? Contact ",
+	"purchase.cancelled_title": "Purchase Cancelled",
+	"purchase.try_again":       "Try Again",
+
+	// LicenseManager
+	"manager.title":              "License Manager",
+	"manager.current":            "Current: ",
+	"manager.press":              "Press ",
+	"manager.to_purchase":        " to purchase, ",
+	"manager.to_exit":            " to exit",
+	"manager.menu_status":        "View License Status",
+	"manager.menu_purchase":      "Purchase License",
+	"manager.menu_enter_key":     "Enter License Key",
+	"manager.menu_clear":         "Clear License",
+	"manager.menu_diagnostics":   "Diagnostics",
+	"manager.menu_exit":          "Exit",
+	"manager.status_title":       "License Status",
+	"manager.purchase_title":     "Purchase License",
+	"manager.enter_key_title":    "Enter License Key",
+	"manager.enter_key_prompt":   "Paste your license key below:",
+	"manager.key_placeholder":    "TUISH-XXXX-XXXX-XXXX...",
+	"manager.key_empty":          "Please enter a license key",
+	"manager.key_invalid_format": "Invalid license key format",
+	"manager.key_expired":        "This license has expired",
+	"manager.key_wrong_machine":  "This license is bound to a different machine",
+	"manager.key_wrong_product":  "This license is for a different product",
+	"manager.key_not_genuine":    "This license key is not genuine",
+	"manager.key_revoked":        "This license has been revoked",
+	"manager.key_activated":      "License activated successfully!",
+	"manager.key_not_writable":   "Can't save license: storage not writable \u2014 set %s.",
+	"manager.submit":             "submit",
+	"manager.store":              "store",
+	"manager.preview_grants":     "This license grants:",
+	"manager.preview_product":    "  Product: ",
+	"manager.preview_features":   "  Features: ",
+	"manager.preview_expires":    "  Expires: ",
+	"manager.preview_none":       "none",
+	"manager.preview_confirm":    "Store it?",
+	"manager.clear_title":        "Clear License?",
+	"manager.clear_body":         "This will remove your license from this device.",
+	"manager.clear_help":         "You can re-enter it later if needed.",
+	"manager.clear_keep":         "No, keep license",
+	"manager.clear_confirm":      "Yes, clear license",
+	"manager.rebind_title":       "License Bound Elsewhere",
+	"manager.rebind_prompt":      "This license is bound to another machine \u2014 rebind to this one? [y/n]",
+	"manager.rebinding":          "Rebinding license...",
+	"manager.rebind_not_allowed": "This license can't be moved to this machine. Deactivate it on the other machine or contact support.",
+	"manager.rebind_failed":      "Rebind failed: %v",
+	"manager.diagnostics_title":  "Diagnostics",
+	"manager.diag_fingerprint":   "Machine fingerprint",
+	"manager.diag_storage":       "Storage directory",
+	"manager.diag_product":       "Cached product",
+	"manager.diag_refresh":       "Next refresh",
+	"manager.diag_version":       "SDK version",
+	"manager.diag_none":          "none",
+	"manager.diag_na":            "n/a",
+	"manager.diag_custom_store":  "custom store",
+	"manager.diag_unreadable":    "unreadable (%v)",
+	"manager.diag_copied":        "Fingerprint copied to clipboard",
+	"manager.diag_copy":          "copy fingerprint",
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   Catalog = EnglishCatalog
+)
+
+// SetCatalog sets the catalog components translate their text with. Set it
+// before creating components; nil restores EnglishCatalog.
+func SetCatalog(c Catalog) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if c == nil {
+		c = EnglishCatalog
+	}
+	catalog = c
+}
+
+// tr translates key with the current catalog.
+func tr(key string, args ...any) string {
+	catalogMu.RLock()
+	c := catalog
+	catalogMu.RUnlock()
+	return c.T(key, args...)
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tuish "github.com/tuishdotdev/tuish/go"
+	"github.com/tuishdotdev/tuish/go/tui/tuishtest"
+)
+
+// upperCatalog translates every message to a marked, upper-cased copy of
+// the English text.
+type upperCatalog struct{}
+
+func (upperCatalog) T(key string, args ...any) string {
+	if key == "date.format" || key == "date.time_format" {
+		return EnglishCatalog.T(key)
+	}
+	return "XX " + strings.ToUpper(EnglishCatalog.T(key, args...))
+}
+
+func useCatalog(t *testing.T, c Catalog) {
+	t.Helper()
+	SetCatalog(c)
+	t.Cleanup(func() { SetCatalog(nil) })
+}
+
+func TestCatalogTranslatesComponents(t *testing.T) {
+	useCatalog(t, upperCatalog{})
+
+	status := NewLicenseStatus(nil)
+	status.Update(LicenseCheckedMsg{Result: tuishtest.ValidResult("pro")})
+	if view := status.View(); !strings.Contains(view, "XX STATUS:") || !strings.Contains(view, "XX FEATURES:") {
+		t.Errorf("expected translated status labels, got:\n%s", view)
+	}
+
+	gate := NewLicenseGate(tuishtest.NewFakeSDK(), appModel("app"), LicenseGateConfig{Feature: "pro"})
+	gate.Update(LicenseCheckedMsg{Result: tuishtest.InvalidResult(tuish.ReasonNotFound)})
+	if view := gate.View(); !strings.Contains(view, `XX THE "PRO" FEATURE REQUIRES A VALID LICENSE.`) {
+		t.Errorf("expected translated access denied text, got:\n%s", view)
+	}
+
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{Terminal: TerminalInteractive})
+	flow.setStep(PurchaseStepCreating)
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_123",
+		CheckoutURL: "https://checkout.example.com/sess_123",
+	}})
+	if view := flow.View(); !strings.Contains(view, "XX COMPLETE YOUR PURCHASE") || strings.Contains(view, "Waiting for payment") {
+		t.Errorf("expected translated waiting screen, got:\n%s", view)
+	}
+
+	manager := NewLicenseManager(tuishtest.NewFakeSDK())
+	manager.Update(LicenseCheckedMsg{Result: tuishtest.InvalidResult(tuish.ReasonNotFound)})
+	if view := manager.View(); !strings.Contains(view, "XX LICENSE MANAGER") || !strings.Contains(view, "XX PURCHASE LICENSE") {
+		t.Errorf("expected translated menu, got:\n%s", view)
+	}
+}
+
+func TestMapCatalogFallsBackToEnglish(t *testing.T) {
+	german := MapCatalog{"status.no_license": "Keine Lizenz"}
+	useCatalog(t, german)
+
+	status := NewLicenseStatus(nil)
+	status.Update(LicenseCheckedMsg{Result: tuishtest.InvalidResult(tuish.ReasonNotFound)})
+	if view := status.View(); !strings.Contains(view, "Keine Lizenz") {
+		t.Errorf("expected translated text, got:\n%s", view)
+	}
+
+	if got := german.T("status.feature_count_other", 3); got != "3 features" {
+		t.Errorf("expected English fallback, got %q", got)
+	}
+}
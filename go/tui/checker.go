@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"context"
+
+	tuish "github.com/tuishdotdev/tuish/go"
+)
+
+// LicenseChecker is the part of *tuish.SDK the components use. Tests can
+// pass a tuishtest.FakeSDK instead of an SDK backed by a server and storage.
+type LicenseChecker interface {
+	CheckLicense(ctx context.Context) (*tuish.LicenseCheckResult, error)
+	StoreLicense(licenseKey string) error
+	ClearLicense() error
+	ExtractLicenseInfo(licenseKey string) (*tuish.LicenseDetails, error)
+	PreviewLicense(licenseKey string) (*tuish.LicenseDetails, *tuish.VerifyResult, error)
+	GetCachedLicense() (*tuish.CachedLicenseData, error)
+	PurchaseInBrowser(ctx context.Context, email string) (*tuish.CheckoutSessionResult, error)
+	GetCheckoutStatus(ctx context.Context, sessionID string) (*tuish.CheckoutStatus, error)
+	InitTerminalPurchase(ctx context.Context) (*tuish.PurchaseInitResult, error)
+	GetMachineFingerprint() string
+	StorageDir() string
+	HasIdentityToken() bool
+}
+
+var _ LicenseChecker = (*tuish.SDK)(nil)
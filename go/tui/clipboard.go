@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"io"
+	"os"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardOutput is where OSC 52 clipboard sequences are written.
+var clipboardOutput io.Writer = os.Stdout
+
+// clipboardCopiedMsg is sent after text has been written to the clipboard.
+type clipboardCopiedMsg struct {
+	Error error
+}
+
+// copyToClipboard copies text to the system clipboard using the OSC 52
+// escape sequence, which works over SSH in most modern terminals.
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := osc52.New(text).WriteTo(clipboardOutput)
+		return clipboardCopiedMsg{Error: err}
+	}
+}
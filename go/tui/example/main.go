@@ -42,7 +42,7 @@ type DemoModel struct {
 
 func NewDemoModel() *DemoModel {
 	return &DemoModel{
-		styles:       tui.DefaultStyles(),
+		styles:       tui.DetectStyles(),
 		currentView:  "menu",
 		menuIndex:    0,
 		menuItems:    []string{"license status", "purchase flow", "license gate", "qr code", "exit"},
@@ -166,7 +166,7 @@ func (m *DemoModel) renderLicenseStatus() string {
 	b.WriteString(m.styles.Title.Render("license status") + "\n\n")
 
 	if m.licenseValid {
-		b.WriteString(m.styles.StatusValid.Render(tui.CheckMark+" valid") + "\n\n")
+		b.WriteString(m.styles.StatusValid.Render(m.styles.Glyphs.Check+" valid") + "\n\n")
 
 		// Info table style
 		b.WriteString(m.styles.Muted.Render("product   ") + m.styles.Body.Render(m.productName) + "\n")
@@ -175,10 +175,10 @@ func (m *DemoModel) renderLicenseStatus() string {
 
 		b.WriteString(m.styles.Muted.Render("features") + "\n")
 		for _, f := range m.features {
-			b.WriteString("  " + m.styles.StatusValid.Render(tui.CheckMark) + " " + m.styles.Body.Render(f) + "\n")
+			b.WriteString("  " + m.styles.StatusValid.Render(m.styles.Glyphs.Check) + " " + m.styles.Body.Render(f) + "\n")
 		}
 	} else {
-		b.WriteString(m.styles.StatusInvalid.Render(tui.CrossMark+" no license") + "\n\n")
+		b.WriteString(m.styles.StatusInvalid.Render(m.styles.Glyphs.Cross+" no license") + "\n\n")
 		b.WriteString(m.styles.Muted.Render("run ") + m.styles.Highlight.Render("tuish purchase") + m.styles.Muted.Render(" to activate"))
 	}
 
@@ -231,9 +231,9 @@ func (m *DemoModel) renderLicenseGate() string {
 			Render(
 				m.styles.Success.Render("unlocked") + "\n\n" +
 					m.styles.Body.Render("premium features:") + "\n" +
-					m.styles.Muted.Render("  "+tui.CheckMark+" advanced analytics") + "\n" +
-					m.styles.Muted.Render("  "+tui.CheckMark+" export all formats") + "\n" +
-					m.styles.Muted.Render("  "+tui.CheckMark+" priority support"),
+					m.styles.Muted.Render("  "+m.styles.Glyphs.Check+" advanced analytics") + "\n" +
+					m.styles.Muted.Render("  "+m.styles.Glyphs.Check+" export all formats") + "\n" +
+					m.styles.Muted.Render("  "+m.styles.Glyphs.Check+" priority support"),
 			)
 		b.WriteString(content)
 	} else {
@@ -245,9 +245,9 @@ func (m *DemoModel) renderLicenseGate() string {
 			Render(
 				m.styles.Muted.Render("locked") + "\n\n" +
 					m.styles.Muted.Render("premium features:") + "\n" +
-					m.styles.Muted.Render("  "+tui.CrossMark+" advanced analytics") + "\n" +
-					m.styles.Muted.Render("  "+tui.CrossMark+" export all formats") + "\n" +
-					m.styles.Muted.Render("  "+tui.CrossMark+" priority support"),
+					m.styles.Muted.Render("  "+m.styles.Glyphs.Cross+" advanced analytics") + "\n" +
+					m.styles.Muted.Render("  "+m.styles.Glyphs.Cross+" export all formats") + "\n" +
+					m.styles.Muted.Render("  "+m.styles.Glyphs.Cross+" priority support"),
 			)
 		b.WriteString(content)
 	}
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"context"
+
 	tea "github.com/charmbracelet/bubbletea"
 	tuish "github.com/tuishdotdev/tuish/go"
 )
@@ -21,12 +23,14 @@ type LicenseGateConfig struct {
 // LicenseGate conditionally renders content based on license status.
 // It wraps a child model and only allows access when licensing requirements are met.
 type LicenseGate struct {
-	sdk      *tuish.SDK
+	sdk      LicenseChecker
 	config   LicenseGateConfig
 	styles   Styles
 	child    tea.Model
 	fallback tea.Model
 	loading  tea.Model
+	spinner  *Spinner
+	spinning bool
 
 	result     *tuish.LicenseCheckResult
 	isLoading  bool
@@ -46,7 +50,7 @@ type LicenseGate struct {
 //		Feature: "pro",
 //	})
 //	gate.SetFallback(purchaseFlow)
-func NewLicenseGate(sdk *tuish.SDK, child tea.Model, config ...LicenseGateConfig) *LicenseGate {
+func NewLicenseGate(sdk LicenseChecker, child tea.Model, config ...LicenseGateConfig) *LicenseGate {
 	cfg := LicenseGateConfig{}
 	if len(config) > 0 {
 		cfg = config[0]
@@ -62,6 +66,7 @@ func NewLicenseGate(sdk *tuish.SDK, child tea.Model, config ...LicenseGateConfig
 		config:    cfg,
 		styles:    styles,
 		child:     child,
+		spinner:   NewSpinner(SpinnerConfig{Styles: &styles}),
 		isLoading: true,
 	}
 }
@@ -80,12 +85,35 @@ func (m *LicenseGate) SetLoading(loading tea.Model) *LicenseGate {
 
 // Init initializes the LicenseGate by checking the license.
 func (m *LicenseGate) Init() tea.Cmd {
-	return m.checkLicense
+	return m.startCheck(m.checkLicense)
+}
+
+// startCheck runs check, animating the spinner until the result arrives
+// unless a custom loading model is set.
+func (m *LicenseGate) startCheck(check tea.Cmd) tea.Cmd {
+	m.isLoading = true
+	if m.loading != nil || m.spinning {
+		return check
+	}
+	m.spinning = true
+	m.spinner.Reset()
+	return tea.Batch(check, m.spinner.Tick())
 }
 
 // Update handles messages for the LicenseGate.
 func (m *LicenseGate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case SpinnerTickMsg:
+		// The spinner stops on the first tick after loading ends
+		if msg.ID == m.spinner.id {
+			if !m.isLoading || m.loading != nil {
+				m.spinning = false
+				return m, nil
+			}
+			_, cmd := m.spinner.Update(msg)
+			return m, cmd
+		}
+
 	case LicenseCheckedMsg:
 		m.isLoading = false
 		if msg.Error != nil {
@@ -108,8 +136,7 @@ func (m *LicenseGate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case LicenseStoredMsg:
 		// Re-check license after storing
 		if msg.Error == nil {
-			m.isLoading = true
-			return m, m.checkLicense
+			return m, m.startCheck(m.checkLicense)
 		}
 
 	case LicenseRefreshedMsg:
@@ -117,6 +144,13 @@ func (m *LicenseGate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.result = msg.Result
 			m.hasAccess = m.checkAccess(msg.Result)
 		}
+
+	case tea.KeyMsg:
+		// The default access denied screen offers to refresh a license
+		// that may have been upgraded to include the feature
+		if msg.String() == KeyR && !m.isLoading && !m.hasAccess && m.fallback == nil && m.missingFeature() != "" {
+			return m, m.startCheck(m.refreshLicense)
+		}
 	}
 
 	// Pass messages to the active child model
@@ -150,7 +184,7 @@ func (m *LicenseGate) View() string {
 		if m.loading != nil {
 			return m.loading.View()
 		}
-		return m.styles.Muted.Render("Checking license...")
+		return m.styles.Muted.Render(m.spinner.View() + " " + tr("checking_license"))
 	}
 
 	if m.hasAccess {
@@ -165,25 +199,69 @@ func (m *LicenseGate) View() string {
 }
 
 func (m *LicenseGate) renderAccessDenied() string {
+	title := tr("gate.license_required_title")
+	body := tr("gate.license_required_body")
+	help := m.styles.Muted.Render(tr("gate.license_required_help"))
 	if m.config.Feature != "" {
-		return m.styles.BoxWarning.Render(
-			m.styles.Warning.Render(Lock+" Feature Required") + "\n\n" +
-				m.styles.Body.Render("The \""+m.config.Feature+"\" feature requires a valid license.") + "\n" +
-				m.styles.Muted.Render("Please upgrade to access this feature."),
-		)
+		title = tr("gate.feature_required_title")
+		body = tr("gate.feature_required_body", m.config.Feature)
+		help = m.styles.Muted.Render(tr("gate.feature_required_help"))
+	}
+
+	if reason, guidance := m.deniedReason(); reason != "" {
+		help = m.styles.Body.Render(reason) + "\n" + m.styles.Muted.Render(guidance)
+	}
+	if missing := m.missingFeature(); missing != "" {
+		help += "\n\n" + m.styles.Highlight.Render(tr("gate.refresh_hint", missing)) + "\n\n" +
+			RenderKeyHints([][2]string{{"r", tr("gate.refresh")}}, m.styles)
 	}
 
 	return m.styles.BoxWarning.Render(
-		m.styles.Warning.Render(Lock+" License Required") + "\n\n" +
-			m.styles.Body.Render("A valid license is required to access this application.") + "\n" +
-			m.styles.Muted.Render("Please purchase a license to continue."),
+		m.styles.Warning.Render(Lock+" "+title) + "\n\n" +
+			m.styles.Body.Render(body) + "\n" +
+			help,
 	)
 }
 
+// deniedReason explains why access was denied and what the user can do
+// about it. Both are empty when the result gives no specific reason.
+func (m *LicenseGate) deniedReason() (reason, guidance string) {
+	if missing := m.missingFeature(); missing != "" {
+		return tr("gate.missing_feature", missing), tr("gate.missing_feature_help")
+	}
+	if m.result == nil {
+		return "", ""
+	}
+	switch m.result.Reason {
+	case tuish.ReasonExpired:
+		return tr("gate.expired"), tr("gate.expired_help")
+	case tuish.ReasonRevoked:
+		return tr("gate.revoked"), tr("gate.revoked_help")
+	case tuish.ReasonMachineMismatch:
+		return tr("gate.machine_mismatch"), tr("gate.machine_mismatch_help")
+	case tuish.ReasonNotFound:
+		return tr("gate.not_found"), tr("gate.not_found_help")
+	}
+	return "", ""
+}
+
+// missingFeature returns the required feature when the license is valid but
+// doesn't grant it, which a refresh may fix if the plan was upgraded.
+func (m *LicenseGate) missingFeature() string {
+	if m.config.Feature == "" || m.result == nil || !m.result.Valid || m.result.License == nil {
+		return ""
+	}
+	missing, _ := tuish.DiffFeatures(m.result.License.Features, []string{m.config.Feature})
+	if len(missing) == 0 {
+		return ""
+	}
+	return missing[0]
+}
+
 func (m *LicenseGate) checkAccess(result *tuish.LicenseCheckResult) bool {
 	if m.config.Feature != "" {
 		// Feature-based gating
-		return m.hasFeature(result, m.config.Feature)
+		return grantsFeature(result, m.config.Feature)
 	}
 
 	if m.config.RequireLicense {
@@ -195,22 +273,25 @@ func (m *LicenseGate) checkAccess(result *tuish.LicenseCheckResult) bool {
 	return true
 }
 
-func (m *LicenseGate) hasFeature(result *tuish.LicenseCheckResult, feature string) bool {
-	if result == nil || result.License == nil {
-		return false
-	}
+func (m *LicenseGate) checkLicense() tea.Msg {
+	result, err := m.sdk.CheckLicense(context.Background())
+	return LicenseCheckedMsg{Result: result, Error: err}
+}
 
-	for _, f := range result.License.Features {
-		if f == feature {
-			return true
-		}
-	}
-	return false
+// forceChecker is implemented by checkers that can revalidate online on
+// demand, such as *tuish.SDK.
+type forceChecker interface {
+	CheckLicenseWith(ctx context.Context, opts ...tuish.CheckOption) (*tuish.LicenseCheckResult, error)
 }
 
-func (m *LicenseGate) checkLicense() tea.Msg {
-	result, err := m.sdk.CheckLicense(nil)
-	return LicenseCheckedMsg{Result: result, Error: err}
+// refreshLicense rechecks the license, revalidating it online if the
+// checker supports it so an upgraded plan is picked up straight away.
+func (m *LicenseGate) refreshLicense() tea.Msg {
+	if checker, ok := m.sdk.(forceChecker); ok {
+		result, err := checker.CheckLicenseWith(context.Background(), tuish.ForceOnline())
+		return LicenseCheckedMsg{Result: result, Error: err}
+	}
+	return m.checkLicense()
 }
 
 // HasAccess returns whether access is currently granted.
@@ -235,19 +316,18 @@ func (m *LicenseGate) Child() tea.Model {
 
 // Refresh triggers a license refresh.
 func (m *LicenseGate) Refresh() tea.Cmd {
-	m.isLoading = true
-	return m.checkLicense
+	return m.startCheck(m.checkLicense)
 }
 
 // SimpleLicenseGate provides a simpler interface for gating without a full Bubble Tea model.
 // It checks the license synchronously and returns access status.
 type SimpleLicenseGate struct {
-	sdk     *tuish.SDK
+	sdk     LicenseChecker
 	feature string
 }
 
 // NewSimpleLicenseGate creates a simple license gate for synchronous checks.
-func NewSimpleLicenseGate(sdk *tuish.SDK, feature ...string) *SimpleLicenseGate {
+func NewSimpleLicenseGate(sdk LicenseChecker, feature ...string) *SimpleLicenseGate {
 	f := ""
 	if len(feature) > 0 {
 		f = feature[0]
@@ -255,37 +335,68 @@ func NewSimpleLicenseGate(sdk *tuish.SDK, feature ...string) *SimpleLicenseGate
 	return &SimpleLicenseGate{sdk: sdk, feature: f}
 }
 
-// Check performs a synchronous license check and returns access status.
+// GateDecision explains the outcome of a SimpleLicenseGate check.
+type GateDecision struct {
+	// Allowed is true when the license is valid and has every required feature.
+	Allowed bool
+
+	// MissingFeatures lists required features the license doesn't grant.
+	MissingFeatures []string
+
+	// Reason is why the license is invalid, empty if it is valid.
+	Reason tuish.LicenseInvalidReason
+
+	// Result is the underlying license check result.
+	Result *tuish.LicenseCheckResult
+}
+
+// Check performs a synchronous license check and returns access status,
+// with the same rule as LicenseGate and HasFeature.
 func (g *SimpleLicenseGate) Check() (hasAccess bool, result *tuish.LicenseCheckResult, err error) {
-	result, err = g.sdk.CheckLicense(nil)
+	decision, err := g.CheckDetailed()
 	if err != nil {
 		return false, nil, err
 	}
+	return decision.Allowed, decision.Result, nil
+}
+
+// CheckDetailed performs a synchronous license check and reports why access
+// was denied, including which required features are missing.
+func (g *SimpleLicenseGate) CheckDetailed() (*GateDecision, error) {
+	result, err := g.sdk.CheckLicense(context.Background())
+	if err != nil {
+		return nil, err
+	}
 
+	decision := &GateDecision{
+		Reason: result.Reason,
+		Result: result,
+	}
+
+	if g.feature != "" && !licenseHasFeature(result.License, g.feature) {
+		decision.MissingFeatures = []string{g.feature}
+	}
+
+	decision.Allowed = result.Valid
 	if g.feature != "" {
-		// Check for specific feature
-		if result.License != nil {
-			for _, f := range result.License.Features {
-				if f == g.feature {
-					return true, result, nil
-				}
-			}
-		}
-		return false, result, nil
+		decision.Allowed = grantsFeature(result, g.feature)
 	}
+	return decision, nil
+}
 
-	// Just check for valid license
-	return result.Valid, result, nil
+// grantsFeature reports whether result is a valid license granting feature.
+// It is the rule every feature gate shares: an expired, revoked or
+// mismatched license still carries its features, but grants none of them.
+func grantsFeature(result *tuish.LicenseCheckResult, feature string) bool {
+	return result != nil && result.Valid && licenseHasFeature(result.License, feature)
 }
 
-// HasFeature checks if the current license has a specific feature.
-func HasFeature(sdk *tuish.SDK, feature string) bool {
-	result, err := sdk.CheckLicense(nil)
-	if err != nil || result == nil || result.License == nil {
+// licenseHasFeature reports whether license lists feature.
+func licenseHasFeature(license *tuish.LicenseDetails, feature string) bool {
+	if license == nil {
 		return false
 	}
-
-	for _, f := range result.License.Features {
+	for _, f := range license.Features {
 		if f == feature {
 			return true
 		}
@@ -293,9 +404,18 @@ func HasFeature(sdk *tuish.SDK, feature string) bool {
 	return false
 }
 
+// HasFeature checks if the current license has a specific feature.
+func HasFeature(sdk LicenseChecker, feature string) bool {
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		return false
+	}
+	return grantsFeature(result, feature)
+}
+
 // IsLicensed checks if the current license is valid.
-func IsLicensed(sdk *tuish.SDK) bool {
-	result, err := sdk.CheckLicense(nil)
+func IsLicensed(sdk LicenseChecker) bool {
+	result, err := sdk.CheckLicense(context.Background())
 	if err != nil {
 		return false
 	}
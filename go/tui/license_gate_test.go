@@ -0,0 +1,344 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	tuish "github.com/tuishdotdev/tuish/go"
+	"github.com/tuishdotdev/tuish/go/tui/tuishtest"
+)
+
+var _ LicenseChecker = (*tuishtest.FakeSDK)(nil)
+
+// appModel is a child model that renders a fixed string.
+type appModel string
+
+func (m appModel) Init() tea.Cmd                       { return nil }
+func (m appModel) Update(tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
+func (m appModel) View() string                        { return string(m) }
+
+// runCheck delivers the result of the gate's pending license check,
+// picking it out of a batch that also starts the spinner.
+func runCheck(t *testing.T, gate *LicenseGate, cmd tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a license check command")
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		msgs := make(chan tea.Msg, len(batch))
+		for _, c := range batch {
+			go func(c tea.Cmd) { msgs <- c() }(c)
+		}
+		for msg = range msgs {
+			if _, ok := msg.(LicenseCheckedMsg); ok {
+				break
+			}
+		}
+	}
+	gate.Update(msg)
+}
+
+func TestLicenseGateWithFakeSDK(t *testing.T) {
+	fake := tuishtest.NewFakeSDK(tuishtest.InvalidResult(tuish.ReasonNotFound))
+	gate := NewLicenseGate(fake, appModel("pro app"), LicenseGateConfig{Feature: "pro"})
+
+	runCheck(t, gate, gate.Init())
+	if gate.HasAccess() || !strings.Contains(gate.View(), "Feature Required") {
+		t.Fatalf("expected access denied without a license, got:\n%s", gate.View())
+	}
+
+	// The user buys a license
+	fake.SetResult(tuishtest.ValidResult("pro"))
+	runCheck(t, gate, gate.Refresh())
+	if !gate.HasAccess() || gate.View() != "pro app" {
+		t.Fatalf("expected access after purchase, got:\n%s", gate.View())
+	}
+
+	// The license is later revoked
+	fake.SetResult(tuishtest.InvalidResult(tuish.ReasonRevoked))
+	runCheck(t, gate, gate.Refresh())
+	if gate.HasAccess() {
+		t.Error("expected access to be revoked")
+	}
+	if fake.Checks() != 3 {
+		t.Errorf("expected 3 license checks, got %d", fake.Checks())
+	}
+}
+
+func TestLicenseGateWithFakeSDKStoredLicense(t *testing.T) {
+	fake := tuishtest.NewFakeSDK()
+	fake.AddLicense("TUISH-KEY", &tuish.LicenseDetails{
+		ID:        "lic_key",
+		ProductID: "prod_fake",
+		Features:  []string{"pro"},
+		Status:    tuish.LicenseStatusActive,
+	})
+	gate := NewLicenseGate(fake, appModel("pro app"), LicenseGateConfig{Feature: "pro"})
+
+	runCheck(t, gate, gate.Init())
+	if gate.HasAccess() {
+		t.Fatal("expected access denied without a license")
+	}
+
+	// Storing a key re-checks the license
+	_, cmd := gate.Update(LicenseStoredMsg{Error: fake.StoreLicense("TUISH-KEY")})
+	runCheck(t, gate, cmd)
+	if !gate.HasAccess() {
+		t.Errorf("expected access after storing a key, got:\n%s", gate.View())
+	}
+}
+
+func TestLicenseGateWithFakeSDKCheckError(t *testing.T) {
+	fake := tuishtest.NewFakeSDK()
+	fake.SetError(errors.New("disk unavailable"))
+	gate := NewLicenseGate(fake, appModel("app"), LicenseGateConfig{RequireLicense: true})
+
+	runCheck(t, gate, gate.Init())
+	if gate.HasAccess() {
+		t.Error("expected access denied when the check fails")
+	}
+}
+
+func TestLicenseGateSpinnerWhileLoading(t *testing.T) {
+	gate := NewLicenseGate(tuishtest.NewFakeSDK(tuishtest.ValidResult()), appModel("app"))
+	if gate.Init() == nil {
+		t.Fatal("expected Init to start the check and the spinner")
+	}
+
+	before := gate.View()
+	_, cmd := gate.Update(SpinnerTickMsg{ID: gate.spinner.id})
+	if cmd == nil {
+		t.Error("expected the spinner to schedule its next frame while loading")
+	}
+	if after := gate.View(); after == before || !strings.Contains(after, "Checking license...") {
+		t.Errorf("expected the tick to advance the spinner, got %q then %q", before, after)
+	}
+
+	// Once the check finishes, the next tick stops the animation
+	gate.Update(LicenseCheckedMsg{Result: tuishtest.ValidResult()})
+	if _, cmd := gate.Update(SpinnerTickMsg{ID: gate.spinner.id}); cmd != nil {
+		t.Error("expected the spinner to stop after loading")
+	}
+	if gate.View() != "app" {
+		t.Errorf("expected the child after loading, got %q", gate.View())
+	}
+}
+
+func TestLicenseGateCustomLoadingSkipsSpinner(t *testing.T) {
+	gate := NewLicenseGate(tuishtest.NewFakeSDK(), appModel("app"))
+	gate.SetLoading(appModel("custom loading"))
+
+	if msg := gate.Init()(); msg == nil {
+		t.Fatal("expected Init to check the license")
+	} else if _, ok := msg.(LicenseCheckedMsg); !ok {
+		t.Errorf("expected only a license check with a custom loading model, got %T", msg)
+	}
+	if gate.View() != "custom loading" {
+		t.Errorf("expected the custom loading model, got %q", gate.View())
+	}
+}
+
+func TestLicenseGateSuggestsRefreshForMissingFeature(t *testing.T) {
+	fake := tuishtest.NewFakeSDK(tuishtest.ValidResult("basic"))
+	gate := NewLicenseGate(fake, appModel("pro app"), LicenseGateConfig{Feature: "pro"})
+
+	runCheck(t, gate, gate.Init())
+	if view := gate.View(); !strings.Contains(view, "it may now include 'pro'") {
+		t.Fatalf("expected a refresh suggestion for a licensed user, got:\n%s", view)
+	}
+
+	// The plan was upgraded; refreshing picks it up
+	fake.SetResult(tuishtest.ValidResult("basic", "pro"))
+	_, cmd := gate.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	runCheck(t, gate, cmd)
+	if !gate.HasAccess() {
+		t.Errorf("expected access after refreshing, got:\n%s", gate.View())
+	}
+}
+
+func TestLicenseGateNoRefreshWithoutLicense(t *testing.T) {
+	fake := tuishtest.NewFakeSDK(tuishtest.InvalidResult(tuish.ReasonNotFound))
+	gate := NewLicenseGate(fake, appModel("pro app"), LicenseGateConfig{Feature: "pro"})
+
+	runCheck(t, gate, gate.Init())
+	if view := gate.View(); strings.Contains(view, "Refresh your license") {
+		t.Errorf("expected no refresh suggestion without a license, got:\n%s", view)
+	}
+	if _, cmd := gate.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")}); cmd != nil {
+		t.Error("expected r to do nothing without a license")
+	}
+}
+
+func TestLicenseGateExplainsDenial(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *tuish.LicenseCheckResult
+		reason   string
+		guidance string
+	}{
+		{"expired", tuishtest.InvalidResult(tuish.ReasonExpired), "has expired", "Renew"},
+		{"revoked", tuishtest.InvalidResult(tuish.ReasonRevoked), "has been revoked", "Contact support"},
+		{"machine mismatch", tuishtest.InvalidResult(tuish.ReasonMachineMismatch), "on another machine", "Move it to this machine"},
+		{"not found", tuishtest.InvalidResult(tuish.ReasonNotFound), "No license was found", "Purchase a license"},
+		{"missing feature", tuishtest.ValidResult("basic"), "doesn't include \"pro\"", "Upgrade your plan"},
+		// The SDK keeps the license, and its features, on these results
+		{"expired with feature", withLicense(tuishtest.InvalidResult(tuish.ReasonExpired), "pro"), "has expired", "Renew"},
+		{"revoked with feature", withLicense(tuishtest.InvalidResult(tuish.ReasonRevoked), "pro"), "has been revoked", "Contact support"},
+		{"machine mismatch with feature", withLicense(tuishtest.InvalidResult(tuish.ReasonMachineMismatch), "pro"), "on another machine", "Move it to this machine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := NewLicenseGate(tuishtest.NewFakeSDK(tt.result), appModel("pro app"), LicenseGateConfig{Feature: "pro"})
+			runCheck(t, gate, gate.Init())
+
+			if gate.HasAccess() {
+				t.Error("expected access to be denied")
+			}
+			view := gate.View()
+			if !strings.Contains(view, tt.reason) || !strings.Contains(view, tt.guidance) {
+				t.Errorf("expected %q and %q, got:\n%s", tt.reason, tt.guidance, view)
+			}
+		})
+	}
+}
+
+func TestHasFeatureRequiresValidLicense(t *testing.T) {
+	expired := withLicense(tuishtest.InvalidResult(tuish.ReasonExpired), "pro")
+	if HasFeature(tuishtest.NewFakeSDK(expired), "pro") {
+		t.Error("expected an expired license not to grant its features")
+	}
+	if !HasFeature(tuishtest.NewFakeSDK(tuishtest.ValidResult("pro")), "pro") {
+		t.Error("expected a valid license to grant its features")
+	}
+}
+
+func TestFeatureGatesAgree(t *testing.T) {
+	results := map[string]*tuish.LicenseCheckResult{
+		"valid with feature":    tuishtest.ValidResult("pro"),
+		"valid without feature": tuishtest.ValidResult("basic"),
+		"expired with feature":  withLicense(tuishtest.InvalidResult(tuish.ReasonExpired), "pro"),
+		"no license":            tuishtest.InvalidResult(tuish.ReasonNotFound),
+	}
+	for name, result := range results {
+		t.Run(name, func(t *testing.T) {
+			fake := tuishtest.NewFakeSDK(result)
+
+			gate := NewLicenseGate(fake, appModel("pro app"), LicenseGateConfig{Feature: "pro"})
+			runCheck(t, gate, gate.Init())
+			simple, _, err := NewSimpleLicenseGate(fake, "pro").Check()
+			if err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+			helper := HasFeature(fake, "pro")
+
+			if gate.HasAccess() != simple || simple != helper {
+				t.Errorf("gates disagree: LicenseGate=%v SimpleLicenseGate=%v HasFeature=%v", gate.HasAccess(), simple, helper)
+			}
+		})
+	}
+}
+
+// withLicense attaches a license granting features to result, as the SDK
+// does for an expired, revoked or mismatched license.
+func withLicense(result *tuish.LicenseCheckResult, features ...string) *tuish.LicenseCheckResult {
+	result.License = tuishtest.ValidResult(features...).License
+	return result
+}
+
+func TestLicenseGateGenericDenialWithoutReason(t *testing.T) {
+	fake := tuishtest.NewFakeSDK(tuishtest.InvalidResult(tuish.ReasonInvalidSignature))
+	gate := NewLicenseGate(fake, appModel("app"), LicenseGateConfig{RequireLicense: true})
+
+	runCheck(t, gate, gate.Init())
+	if view := gate.View(); !strings.Contains(view, "Please purchase a license to continue.") {
+		t.Errorf("expected the generic help, got:\n%s", view)
+	}
+}
+
+func TestSimpleLicenseGateCheckDetailedMissingFeature(t *testing.T) {
+	sdk, sign := newSigningSDK(t)
+	license := sign(tuish.LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		Features:  []string{"pro", "analytics"},
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("store license: %v", err)
+	}
+
+	decision, err := NewSimpleLicenseGate(sdk, "export").CheckDetailed()
+	if err != nil {
+		t.Fatalf("CheckDetailed: %v", err)
+	}
+	if decision.Allowed {
+		t.Error("expected access to be denied")
+	}
+	if len(decision.MissingFeatures) != 1 || decision.MissingFeatures[0] != "export" {
+		t.Errorf("MissingFeatures = %v, want [export]", decision.MissingFeatures)
+	}
+	if decision.Reason != "" {
+		t.Errorf("Reason = %q, want empty for a valid license", decision.Reason)
+	}
+	if decision.Result == nil || decision.Result.License == nil {
+		t.Fatal("expected the license check result")
+	}
+	if got := decision.Result.License.Features; len(got) != 2 {
+		t.Errorf("license features = %v", got)
+	}
+
+	allowed, _, err := NewSimpleLicenseGate(sdk, "pro").Check()
+	if err != nil || !allowed {
+		t.Errorf("Check(pro) = %v, %v; want allowed", allowed, err)
+	}
+}
+
+func TestSimpleLicenseGateCheckDetailedInvalidLicense(t *testing.T) {
+	sdk, sign := newSigningSDK(t)
+	otherMachine := "other_machine"
+	license := sign(tuish.LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		Features:  []string{"export"},
+		IssuedAt:  time.Now().UnixMilli(),
+		MachineID: &otherMachine,
+	})
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("store license: %v", err)
+	}
+
+	decision, err := NewSimpleLicenseGate(sdk, "export").CheckDetailed()
+	if err != nil {
+		t.Fatalf("CheckDetailed: %v", err)
+	}
+	if decision.Allowed {
+		t.Error("expected invalid license to be denied even with the feature")
+	}
+	if decision.Reason != tuish.ReasonMachineMismatch {
+		t.Errorf("Reason = %q, want %q", decision.Reason, tuish.ReasonMachineMismatch)
+	}
+
+	allowed, _, err := NewSimpleLicenseGate(sdk, "export").Check()
+	if err != nil || allowed {
+		t.Errorf("Check = %v, %v; want denied", allowed, err)
+	}
+}
+
+func TestSimpleLicenseGateCheckDetailedNoLicense(t *testing.T) {
+	sdk, _ := newSigningSDK(t)
+
+	decision, err := NewSimpleLicenseGate(sdk).CheckDetailed()
+	if err != nil {
+		t.Fatalf("CheckDetailed: %v", err)
+	}
+	if decision.Allowed || decision.Reason != tuish.ReasonNotFound {
+		t.Errorf("decision = %+v, want denied with not_found", decision)
+	}
+	if len(decision.MissingFeatures) != 0 {
+		t.Errorf("MissingFeatures = %v, want none without a required feature", decision.MissingFeatures)
+	}
+}
@@ -1,8 +1,13 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -18,8 +23,18 @@ const (
 	ScreenPurchase
 	ScreenEnterKey
 	ScreenConfirmClear
+	ScreenDiagnostics
+
+	// ScreenConfirmRebind offers to rebind a license that is bound to
+	// another machine. It is shown automatically when a check reports a
+	// machine mismatch and the checker supports rebinding.
+	ScreenConfirmRebind
 )
 
+// statusScreenChrome is the number of rows the status screen uses around
+// the LicenseStatus view (title, spacing, and key hints).
+const statusScreenChrome = 4
+
 // LicenseManagerConfig contains configuration for the LicenseManager component.
 type LicenseManagerConfig struct {
 	// AllowManualEntry enables manual license key entry (default: true).
@@ -28,6 +43,24 @@ type LicenseManagerConfig struct {
 	// Email is pre-filled for purchase flow.
 	Email string
 
+	// InitialScreen is the screen shown when the manager starts (default:
+	// ScreenMenu). ScreenPurchase starts a checkout right away, and
+	// ScreenDiagnostics loads the diagnostics straight away.
+	InitialScreen ManagerScreen
+
+	// ShowDiagnostics adds a Diagnostics menu item showing the machine
+	// fingerprint, storage location, and cache state, for support requests.
+	ShowDiagnostics bool
+
+	// DisableVimKeys turns off the j/k/h/l navigation aliases, for apps
+	// that bind those keys themselves.
+	DisableVimKeys bool
+
+	// FullWidthHighlight renders the selected menu row as a ListItemActive
+	// bar on the theme's Primary color, across the full width of the menu,
+	// instead of only coloring its label.
+	FullWidthHighlight bool
+
 	// OnExit is called when user exits the manager.
 	OnExit func()
 
@@ -51,7 +84,7 @@ type MenuItem struct {
 
 // LicenseManager provides a complete self-service license management UI.
 type LicenseManager struct {
-	sdk    *tuish.SDK
+	sdk    LicenseChecker
 	config LicenseManagerConfig
 	styles Styles
 
@@ -63,13 +96,19 @@ type LicenseManager struct {
 	manualKeyInput  string
 	manualKeyError  string
 	manualKeySuccess bool
+	pendingKey      string
+	pendingLicense  *tuish.LicenseDetails
 	confirmSelected int // 0 = No, 1 = Yes
+	diagnostics     *diagnosticsInfo
+	rebindOffered   bool
+	rebinding       bool
+	rebindError     string
 
 	result *tuish.LicenseCheckResult
 }
 
 // NewLicenseManager creates a new LicenseManager component.
-func NewLicenseManager(sdk *tuish.SDK, config ...LicenseManagerConfig) *LicenseManager {
+func NewLicenseManager(sdk LicenseChecker, config ...LicenseManagerConfig) *LicenseManager {
 	cfg := DefaultLicenseManagerConfig()
 	if len(config) > 0 {
 		cfg = config[0]
@@ -87,14 +126,28 @@ func NewLicenseManager(sdk *tuish.SDK, config ...LicenseManagerConfig) *LicenseM
 		screen: ScreenMenu,
 	}
 
-	m.licenseStatus = NewLicenseStatus(sdk, LicenseStatusConfig{Styles: &styles})
+	statusConfig := DefaultLicenseStatusConfig()
+	statusConfig.Styles = &styles
+	statusConfig.DisableVimKeys = cfg.DisableVimKeys
+	m.licenseStatus = NewLicenseStatus(sdk, statusConfig)
 
 	return m
 }
 
 // Init initializes the LicenseManager.
 func (m *LicenseManager) Init() tea.Cmd {
-	return m.checkLicense
+	switch m.config.InitialScreen {
+	case ScreenPurchase:
+		_, cmd := m.startPurchase()
+		return tea.Batch(m.checkLicense, cmd)
+	case ScreenDiagnostics:
+		m.screen = ScreenDiagnostics
+		m.diagnostics = m.loadDiagnostics()
+		return m.checkLicense
+	default:
+		m.screen = m.config.InitialScreen
+		return m.checkLicense
+	}
 }
 
 // Update handles messages for the LicenseManager.
@@ -103,10 +156,68 @@ func (m *LicenseManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case LicenseCheckedMsg:
 		m.result = msg.Result
 		m.buildMenuItems()
+		m.licenseStatus.Update(msg)
+		m.offerRebind()
+		return m, nil
+
+	case LicenseReboundMsg:
+		m.rebinding = false
+		var denied *tuish.RebindNotAllowedError
+		switch {
+		case errors.As(msg.Error, &denied):
+			m.rebindError = tr("manager.rebind_not_allowed")
+		case msg.Error != nil:
+			m.rebindError = tr("manager.rebind_failed", msg.Error)
+		default:
+			m.screen = ScreenMenu
+			return m, m.checkLicense
+		}
+		return m, nil
+
+	case LicenseRefreshedMsg:
+		if msg.Error != nil || msg.Result == nil {
+			return m, nil
+		}
+		wasValid := m.result != nil && m.result.Valid
+		m.result = msg.Result
+		m.buildMenuItems()
+		m.licenseStatus.Update(msg)
+
+		if wasValid && isRevoked(msg.Result) {
+			result := msg.Result
+			return m, func() tea.Msg {
+				return LicenseRevokedMsg{Result: result}
+			}
+		}
+		return m, nil
+
+	case LicenseRevokedMsg:
+		m.result = msg.Result
+		m.buildMenuItems()
+		m.licenseStatus.Update(msg)
+
+		// Point the user straight at the purchase option
+		for i, item := range m.menuItems {
+			if item.Value == "purchase" {
+				m.selectedIndex = i
+				break
+			}
+		}
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		// Reserve rows for the status screen's title and key hints
+		m.licenseStatus.SetHeight(msg.Height - statusScreenChrome)
+		if m.purchaseFlow != nil {
+			_, cmd := m.purchaseFlow.Update(msg)
+			return m, cmd
+		}
 		return m, nil
 
 	case LicenseStoredMsg:
-		if msg.Error != nil {
+		if errors.Is(msg.Error, tuish.ErrStorageNotWritable) {
+			m.manualKeyError = tr("manager.key_not_writable", tuish.StorageDirEnv)
+		} else if msg.Error != nil {
 			m.manualKeyError = msg.Error.Error()
 		} else {
 			m.manualKeySuccess = true
@@ -117,6 +228,12 @@ func (m *LicenseManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			})
 		}
 
+	case clipboardCopiedMsg:
+		if m.diagnostics != nil {
+			m.diagnostics.copied = msg.Error == nil
+		}
+		return m, nil
+
 	case LicenseClearedMsg:
 		m.screen = ScreenMenu
 		return m, m.checkLicense
@@ -164,25 +281,41 @@ func (m *LicenseManager) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch m.screen {
 	case ScreenMenu:
-		return m.handleMenuKeyPress(key)
+		return m.handleMenuKeyPress(navigationKey(key, m.config.DisableVimKeys))
 
 	case ScreenStatus:
 		if key == KeyEscape || key == KeyQ {
 			m.screen = ScreenMenu
+			return m, nil
 		}
+		_, cmd := m.licenseStatus.Update(msg)
+		return m, cmd
 
 	case ScreenPurchase:
 		// Handled by PurchaseFlow
 		if key == KeyEscape {
 			m.screen = ScreenMenu
-			m.purchaseFlow = nil
+			m.closePurchase()
 		}
 
 	case ScreenEnterKey:
 		return m.handleEnterKeyKeyPress(msg)
 
 	case ScreenConfirmClear:
-		return m.handleConfirmClearKeyPress(key)
+		return m.handleConfirmClearKeyPress(navigationKey(key, m.config.DisableVimKeys))
+
+	case ScreenDiagnostics:
+		switch key {
+		case KeyEscape, KeyQ:
+			m.screen = ScreenMenu
+		case KeyC:
+			if m.diagnostics != nil {
+				return m, copyToClipboard(m.diagnostics.fingerprint)
+			}
+		}
+
+	case ScreenConfirmRebind:
+		return m.handleConfirmRebindKeyPress(key)
 	}
 
 	return m, nil
@@ -203,6 +336,11 @@ func (m *LicenseManager) handleMenuKeyPress(key string) (tea.Model, tea.Cmd) {
 	case KeyEnter:
 		return m.selectMenuItem()
 
+	case KeyP:
+		if m.canPurchase() {
+			return m.startPurchase()
+		}
+
 	case KeyQ:
 		if m.config.OnExit != nil {
 			m.config.OnExit()
@@ -216,6 +354,16 @@ func (m *LicenseManager) handleMenuKeyPress(key string) (tea.Model, tea.Cmd) {
 func (m *LicenseManager) handleEnterKeyKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 
+	if m.pendingKey != "" {
+		switch key {
+		case KeyY, KeyEnter:
+			return m.confirmManualKey()
+		case KeyN, KeyEscape:
+			m.clearPendingKey()
+		}
+		return m, nil
+	}
+
 	switch key {
 	case KeyEscape:
 		m.screen = ScreenMenu
@@ -232,18 +380,36 @@ func (m *LicenseManager) handleEnterKeyKeyPress(msg tea.KeyMsg) (tea.Model, tea.
 		}
 
 	default:
-		// Append printable characters
-		if len(msg.String()) == 1 {
-			m.manualKeyInput += msg.String()
+		// Typed characters and bracketed pastes both arrive as runes
+		if msg.Type == tea.KeyRunes {
+			m.appendManualKey(msg.Runes)
 		}
 	}
 
 	return m, nil
 }
 
+// appendManualKey adds typed or pasted runes to the key input, dropping
+// whitespace and control characters (a key pasted from an email may be
+// wrapped across lines) and stopping at the longest key the SDK accepts.
+func (m *LicenseManager) appendManualKey(runes []rune) {
+	var sb strings.Builder
+	sb.WriteString(m.manualKeyInput)
+	for _, r := range runes {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			continue
+		}
+		if sb.Len()+utf8.RuneLen(r) > tuish.MaxLicenseLength {
+			break
+		}
+		sb.WriteRune(r)
+	}
+	m.manualKeyInput = sb.String()
+}
+
 func (m *LicenseManager) handleConfirmClearKeyPress(key string) (tea.Model, tea.Cmd) {
 	switch key {
-	case KeyUp, KeyDown:
+	case KeyUp, KeyDown, KeyLeft, KeyRight:
 		m.confirmSelected = 1 - m.confirmSelected
 
 	case KeyEnter:
@@ -268,6 +434,26 @@ func (m *LicenseManager) handleConfirmClearKeyPress(key string) (tea.Model, tea.
 	return m, nil
 }
 
+func (m *LicenseManager) handleConfirmRebindKeyPress(key string) (tea.Model, tea.Cmd) {
+	if m.rebinding {
+		return m, nil
+	}
+
+	switch key {
+	case KeyY:
+		if m.rebindError != "" {
+			return m, nil
+		}
+		m.rebinding = true
+		return m, m.rebindMachine
+
+	case KeyN, KeyEscape, KeyQ:
+		m.screen = ScreenMenu
+	}
+
+	return m, nil
+}
+
 func (m *LicenseManager) selectMenuItem() (tea.Model, tea.Cmd) {
 	if m.selectedIndex >= len(m.menuItems) {
 		return m, nil
@@ -280,22 +466,23 @@ func (m *LicenseManager) selectMenuItem() (tea.Model, tea.Cmd) {
 		m.screen = ScreenStatus
 
 	case "purchase":
-		m.screen = ScreenPurchase
-		m.purchaseFlow = NewPurchaseFlow(m.sdk, PurchaseFlowConfig{
-			Email: m.config.Email,
-		})
-		return m, m.purchaseFlow.Init()
+		return m.startPurchase()
 
 	case "enter-key":
 		m.screen = ScreenEnterKey
 		m.manualKeyInput = ""
 		m.manualKeyError = ""
 		m.manualKeySuccess = false
+		m.clearPendingKey()
 
 	case "clear":
 		m.screen = ScreenConfirmClear
 		m.confirmSelected = 0
 
+	case "diagnostics":
+		m.screen = ScreenDiagnostics
+		m.diagnostics = m.loadDiagnostics()
+
 	case "exit":
 		if m.config.OnExit != nil {
 			m.config.OnExit()
@@ -306,32 +493,91 @@ func (m *LicenseManager) selectMenuItem() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// startPurchase switches to the purchase screen with a fresh PurchaseFlow.
+func (m *LicenseManager) startPurchase() (tea.Model, tea.Cmd) {
+	m.closePurchase()
+	m.screen = ScreenPurchase
+	m.purchaseFlow = NewPurchaseFlow(m.sdk, PurchaseFlowConfig{
+		Email: m.config.Email,
+	})
+	return m, m.purchaseFlow.Init()
+}
+
+// closePurchase cancels and discards the purchase flow, if any, so its
+// requests and polling stop with it.
+func (m *LicenseManager) closePurchase() {
+	if m.purchaseFlow != nil {
+		m.purchaseFlow.Cancel()
+		m.purchaseFlow = nil
+	}
+}
+
+// canPurchase reports whether purchasing is offered, which is only the case
+// when there is no valid license.
+func (m *LicenseManager) canPurchase() bool {
+	return m.result == nil || !m.result.Valid
+}
+
 func (m *LicenseManager) submitManualKey() (tea.Model, tea.Cmd) {
 	m.manualKeyError = ""
 	m.manualKeySuccess = false
 
 	key := strings.TrimSpace(m.manualKeyInput)
 	if key == "" {
-		m.manualKeyError = "Please enter a license key"
+		m.manualKeyError = tr("manager.key_empty")
 		return m, nil
 	}
 
-	// Validate the key format
-	info, err := m.sdk.ExtractLicenseInfo(key)
+	// Verify the key before asking the user to confirm it
+	info, result, err := m.sdk.PreviewLicense(key)
 	if err != nil {
-		m.manualKeyError = "Invalid license key format"
+		m.manualKeyError = tr("manager.key_invalid_format")
+		return m, nil
+	}
+	if !result.Valid {
+		m.manualKeyError = previewErrorMessage(result.Reason)
 		return m, nil
 	}
 
-	_ = info // We don't need the info, just validating format
+	m.pendingKey = key
+	m.pendingLicense = info
+	return m, nil
+}
+
+// confirmManualKey stores the previewed key.
+func (m *LicenseManager) confirmManualKey() (tea.Model, tea.Cmd) {
+	key := m.pendingKey
+	m.clearPendingKey()
 
-	// Store the key
 	return m, func() tea.Msg {
 		err := m.sdk.StoreLicense(key)
 		return LicenseStoredMsg{Error: err}
 	}
 }
 
+func (m *LicenseManager) clearPendingKey() {
+	m.pendingKey = ""
+	m.pendingLicense = nil
+}
+
+// previewErrorMessage describes why a pasted key can't be stored.
+func previewErrorMessage(reason tuish.LicenseInvalidReason) string {
+	switch reason {
+	case tuish.ReasonExpired:
+		return tr("manager.key_expired")
+	case tuish.ReasonMachineMismatch:
+		return tr("manager.key_wrong_machine")
+	case tuish.ReasonProductMismatch:
+		return tr("manager.key_wrong_product")
+	case tuish.ReasonInvalidSignature:
+		return tr("manager.key_not_genuine")
+	case tuish.ReasonRevoked:
+		return tr("manager.key_revoked")
+	default:
+		return tr("manager.key_invalid_format")
+	}
+}
+
 // View renders the LicenseManager.
 func (m *LicenseManager) View() string {
 	switch m.screen {
@@ -345,6 +591,10 @@ func (m *LicenseManager) View() string {
 		return m.renderEnterKey()
 	case ScreenConfirmClear:
 		return m.renderConfirmClear()
+	case ScreenDiagnostics:
+		return m.renderDiagnostics()
+	case ScreenConfirmRebind:
+		return m.renderConfirmRebind()
 	default:
 		return ""
 	}
@@ -354,52 +604,96 @@ func (m *LicenseManager) renderMenu() string {
 	var sb strings.Builder
 
 	// Title
-	sb.WriteString(m.styles.Bold.Render("License Manager"))
+	sb.WriteString(m.styles.Bold.Render(tr("manager.title")))
 	sb.WriteString("\n")
 
 	// Current license status (compact)
-	if m.result != nil && m.result.License != nil {
+	if m.result != nil && (m.result.License != nil || isRevoked(m.result)) {
 		status := RenderLicenseStatus(m.result, LicenseStatusConfig{Compact: true})
-		sb.WriteString(m.styles.Muted.Render("Current: "))
+		sb.WriteString(m.styles.Muted.Render(tr("manager.current")))
 		sb.WriteString(status)
 		sb.WriteString("\n")
 	}
 	sb.WriteString("\n")
 
 	// Menu items
+	if m.config.FullWidthHighlight {
+		sb.WriteString(m.renderMenuItemsFullWidth())
+	} else {
+		for i, item := range m.menuItems {
+			cursor := "  "
+			style := m.styles.Body
+			if i == m.selectedIndex {
+				cursor = m.styles.glyphs().Arrow + " "
+				style = m.styles.Highlight
+			}
+
+			sb.WriteString(cursor)
+			sb.WriteString(item.Icon + " ")
+			sb.WriteString(style.Render(item.Label))
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n")
+
+	// Controls
+	if m.canPurchase() {
+		sb.WriteString(m.styles.Muted.Render(tr("manager.press")))
+		sb.WriteString(m.styles.KeyLabel.Render("p"))
+		sb.WriteString(m.styles.Muted.Render(tr("manager.to_purchase")))
+		sb.WriteString(m.styles.KeyLabel.Render("q"))
+		sb.WriteString(m.styles.Muted.Render(tr("manager.to_exit")))
+	} else {
+		sb.WriteString(m.styles.Muted.Render(tr("manager.press")))
+		sb.WriteString(m.styles.KeyLabel.Render("q"))
+		sb.WriteString(m.styles.Muted.Render(tr("manager.to_exit")))
+	}
+
+	return sb.String()
+}
+
+// renderMenuItemsFullWidth renders the menu with the selected row
+// highlighted across the width of the widest row.
+func (m *LicenseManager) renderMenuItemsFullWidth() string {
+	rows := make([]string, len(m.menuItems))
+	width := 0
 	for i, item := range m.menuItems {
 		cursor := "  "
-		style := m.styles.Body
 		if i == m.selectedIndex {
-			cursor = ArrowRight + " "
-			style = m.styles.Highlight
+			cursor = m.styles.glyphs().Arrow + " "
 		}
+		rows[i] = cursor + item.Icon + " " + item.Label
+		width = max(width, lipgloss.Width(rows[i]))
+	}
 
-		sb.WriteString(cursor)
-		sb.WriteString(item.Icon + " ")
-		sb.WriteString(style.Render(item.Label))
+	var sb strings.Builder
+	for i, row := range rows {
+		style := m.styles.ListItem
+		if i == m.selectedIndex {
+			style = m.styles.ListItemActive.
+				Foreground(m.styles.Theme.Inverted).
+				Background(m.styles.Theme.Primary)
+		}
+		sb.WriteString(style.Width(width + style.GetHorizontalFrameSize()).Render(row))
 		sb.WriteString("\n")
 	}
-	sb.WriteString("\n")
-
-	// Controls
-	sb.WriteString(m.styles.Muted.Render("Press "))
-	sb.WriteString(m.styles.KeyLabel.Render("q"))
-	sb.WriteString(m.styles.Muted.Render(" to exit"))
-
 	return sb.String()
 }
 
 func (m *LicenseManager) renderStatus() string {
 	var sb strings.Builder
 
-	sb.WriteString(m.styles.Bold.Render("License Status"))
+	sb.WriteString(m.styles.Bold.Render(tr("manager.status_title")))
 	sb.WriteString("\n\n")
 
 	sb.WriteString(m.licenseStatus.View())
 	sb.WriteString("\n\n")
 
-	sb.WriteString(RenderKeyHint("Esc", "go back", m.styles))
+	hints := [][2]string{{"Esc", tr("hint.go_back")}}
+	if m.licenseStatus.CanScroll() {
+		hints = append(hints, [2]string{m.styles.glyphs().ArrowUp + "/" + m.styles.glyphs().ArrowDown, tr("hint.scroll")})
+	}
+	sb.WriteString(RenderKeyHints(hints, m.styles))
 
 	return sb.String()
 }
@@ -407,7 +701,7 @@ func (m *LicenseManager) renderStatus() string {
 func (m *LicenseManager) renderPurchase() string {
 	var sb strings.Builder
 
-	sb.WriteString(m.styles.Bold.Render("Purchase License"))
+	sb.WriteString(m.styles.Bold.Render(tr("manager.purchase_title")))
 	sb.WriteString("\n\n")
 
 	if m.purchaseFlow != nil {
@@ -420,21 +714,25 @@ func (m *LicenseManager) renderPurchase() string {
 func (m *LicenseManager) renderEnterKey() string {
 	var sb strings.Builder
 
-	sb.WriteString(m.styles.Bold.Render("Enter License Key"))
+	sb.WriteString(m.styles.Bold.Render(tr("manager.enter_key_title")))
 	sb.WriteString("\n")
-	sb.WriteString(m.styles.Muted.Render("Paste your license key below:"))
+	sb.WriteString(m.styles.Muted.Render(tr("manager.enter_key_prompt")))
 	sb.WriteString("\n\n")
 
 	// Input field
+	border := lipgloss.RoundedBorder()
+	if m.styles.ASCII {
+		border = asciiBorder
+	}
 	inputStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(border).
 		BorderForeground(m.styles.Theme.BorderFocus).
 		Padding(0, 1).
 		Width(50)
 
 	displayKey := m.manualKeyInput
 	if displayKey == "" {
-		displayKey = m.styles.Muted.Render("TUISH-XXXX-XXXX-XXXX...")
+		displayKey = m.styles.Muted.Render(tr("manager.key_placeholder"))
 	}
 	sb.WriteString(inputStyle.Render(displayKey))
 	sb.WriteString("\n\n")
@@ -447,37 +745,77 @@ func (m *LicenseManager) renderEnterKey() string {
 
 	// Success message
 	if m.manualKeySuccess {
-		sb.WriteString(m.styles.CheckMark.Render("") + m.styles.Success.Render("License activated successfully!"))
+		sb.WriteString(m.styles.CheckMark.Render("") + m.styles.Success.Render(tr("manager.key_activated")))
+		sb.WriteString("\n\n")
+	}
+
+	// Confirmation of the previewed license
+	if m.pendingLicense != nil {
+		sb.WriteString(m.renderPendingLicense())
 		sb.WriteString("\n\n")
+		sb.WriteString(RenderKeyHints([][2]string{{"y", tr("manager.store")}, {"n", tr("hint.cancel")}}, m.styles))
+		return sb.String()
 	}
 
 	// Controls
 	hints := [][2]string{
-		{"Enter", "submit"},
-		{"Esc", "cancel"},
+		{"Enter", tr("manager.submit")},
+		{"Esc", tr("hint.cancel")},
 	}
 	sb.WriteString(RenderKeyHints(hints, m.styles))
 
 	return sb.String()
 }
 
+// renderPendingLicense summarizes the license awaiting confirmation.
+func (m *LicenseManager) renderPendingLicense() string {
+	var sb strings.Builder
+	license := m.pendingLicense
+
+	sb.WriteString(m.styles.Body.Render(tr("manager.preview_grants")))
+	sb.WriteString("\n")
+	sb.WriteString(m.styles.Muted.Render(tr("manager.preview_product")))
+	sb.WriteString(m.styles.Body.Render(license.ProductID))
+	sb.WriteString("\n")
+
+	features := tr("manager.preview_none")
+	if len(license.Features) > 0 {
+		features = strings.Join(license.Features, ", ")
+	}
+	sb.WriteString(m.styles.Muted.Render(tr("manager.preview_features")))
+	sb.WriteString(m.styles.Body.Render(features))
+	sb.WriteString("\n")
+
+	expires := tr("date.never")
+	if license.ExpiresAt != nil {
+		expires = time.UnixMilli(*license.ExpiresAt).Format(tr("date.format"))
+	}
+	sb.WriteString(m.styles.Muted.Render(tr("manager.preview_expires")))
+	sb.WriteString(m.styles.Body.Render(expires))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(m.styles.Bold.Render(tr("manager.preview_confirm")))
+
+	return sb.String()
+}
+
 func (m *LicenseManager) renderConfirmClear() string {
 	var sb strings.Builder
 
-	sb.WriteString(m.styles.Warning.Render("Clear License?"))
+	sb.WriteString(m.styles.Warning.Render(tr("manager.clear_title")))
 	sb.WriteString("\n\n")
-	sb.WriteString(m.styles.Body.Render("This will remove your license from this device."))
+	sb.WriteString(m.styles.Body.Render(tr("manager.clear_body")))
 	sb.WriteString("\n")
-	sb.WriteString(m.styles.Muted.Render("You can re-enter it later if needed."))
+	sb.WriteString(m.styles.Muted.Render(tr("manager.clear_help")))
 	sb.WriteString("\n\n")
 
 	// Options
-	options := []string{"No, keep license", "Yes, clear license"}
+	options := []string{tr("manager.clear_keep"), tr("manager.clear_confirm")}
 	for i, opt := range options {
 		cursor := "  "
 		style := m.styles.Body
 		if i == m.confirmSelected {
-			cursor = ArrowRight + " "
+			cursor = m.styles.glyphs().Arrow + " "
 			style = m.styles.Highlight
 		}
 		sb.WriteString(cursor)
@@ -488,14 +826,106 @@ func (m *LicenseManager) renderConfirmClear() string {
 	return sb.String()
 }
 
+func (m *LicenseManager) renderConfirmRebind() string {
+	var sb strings.Builder
+
+	sb.WriteString(m.styles.Warning.Render(tr("manager.rebind_title")))
+	sb.WriteString("\n\n")
+
+	switch {
+	case m.rebinding:
+		sb.WriteString(m.styles.Muted.Render(tr("manager.rebinding")))
+	case m.rebindError != "":
+		sb.WriteString(m.styles.CrossMark.Render("") + m.styles.Error.Render(m.rebindError))
+		sb.WriteString("\n\n")
+		sb.WriteString(RenderKeyHints([][2]string{{"Esc", tr("hint.go_back")}}, m.styles))
+	default:
+		sb.WriteString(m.styles.Body.Render(tr("manager.rebind_prompt")))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// diagnosticsInfo is the snapshot shown on the diagnostics screen.
+type diagnosticsInfo struct {
+	fingerprint string
+	storageDir  string
+	cached      *tuish.CachedLicenseData
+	cacheErr    error
+	copied      bool
+}
+
+// loadDiagnostics reads the SDK state shown on the diagnostics screen.
+func (m *LicenseManager) loadDiagnostics() *diagnosticsInfo {
+	info := &diagnosticsInfo{
+		fingerprint: m.sdk.GetMachineFingerprint(),
+		storageDir:  tr("manager.diag_custom_store"),
+	}
+	if dir := m.sdk.StorageDir(); dir != "" {
+		info.storageDir = dir
+	}
+	info.cached, info.cacheErr = m.sdk.GetCachedLicense()
+	return info
+}
+
+func (m *LicenseManager) renderDiagnostics() string {
+	var sb strings.Builder
+	info := m.diagnostics
+
+	sb.WriteString(m.styles.Bold.Render(tr("manager.diagnostics_title")))
+	sb.WriteString("\n\n")
+	if info == nil {
+		sb.WriteString(RenderKeyHints([][2]string{{"Esc", tr("hint.go_back")}}, m.styles))
+		return sb.String()
+	}
+
+	product := tr("manager.diag_none")
+	refresh := tr("manager.diag_na")
+	switch {
+	case info.cacheErr != nil:
+		product = tr("manager.diag_unreadable", info.cacheErr)
+	case info.cached != nil:
+		product = info.cached.ProductID
+		refresh = time.UnixMilli(info.cached.RefreshAt).Format(tr("date.time_format"))
+	}
+
+	rows := [][2]string{
+		{tr("manager.diag_fingerprint"), info.fingerprint},
+		{tr("manager.diag_storage"), info.storageDir},
+		{tr("manager.diag_product"), product},
+		{tr("manager.diag_refresh"), refresh},
+		{tr("manager.diag_version"), tuish.Version},
+	}
+	for _, row := range rows {
+		sb.WriteString(m.styles.Muted.Render(fmt.Sprintf("%-21s", row[0])))
+		sb.WriteString(m.styles.Body.Render(row[1]))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	if info.copied {
+		sb.WriteString(m.styles.CheckMark.Render("") + m.styles.Success.Render(tr("manager.diag_copied")))
+		sb.WriteString("\n\n")
+	}
+
+	hints := [][2]string{
+		{"c", tr("manager.diag_copy")},
+		{"Esc", tr("hint.go_back")},
+	}
+	sb.WriteString(RenderKeyHints(hints, m.styles))
+
+	return sb.String()
+}
+
 func (m *LicenseManager) buildMenuItems() {
 	m.menuItems = []MenuItem{
-		{Label: "View License Status", Value: "status", Icon: Clipboard},
+		{Label: tr("manager.menu_status"), Value: "status", Icon: Clipboard},
 	}
 
-	if m.result == nil || !m.result.Valid {
+	if m.canPurchase() {
 		m.menuItems = append(m.menuItems, MenuItem{
-			Label: "Purchase License",
+			Label: tr("manager.menu_purchase"),
 			Value: "purchase",
 			Icon:  ShoppingCart,
 		})
@@ -503,7 +933,7 @@ func (m *LicenseManager) buildMenuItems() {
 
 	if m.config.AllowManualEntry {
 		m.menuItems = append(m.menuItems, MenuItem{
-			Label: "Enter License Key",
+			Label: tr("manager.menu_enter_key"),
 			Value: "enter-key",
 			Icon:  Key,
 		})
@@ -511,14 +941,22 @@ func (m *LicenseManager) buildMenuItems() {
 
 	if m.result != nil && m.result.License != nil {
 		m.menuItems = append(m.menuItems, MenuItem{
-			Label: "Clear License",
+			Label: tr("manager.menu_clear"),
 			Value: "clear",
 			Icon:  Trash,
 		})
 	}
 
+	if m.config.ShowDiagnostics {
+		m.menuItems = append(m.menuItems, MenuItem{
+			Label: tr("manager.menu_diagnostics"),
+			Value: "diagnostics",
+			Icon:  InfoSign,
+		})
+	}
+
 	m.menuItems = append(m.menuItems, MenuItem{
-		Label: "Exit",
+		Label: tr("manager.menu_exit"),
 		Value: "exit",
 		Icon:  Wave,
 	})
@@ -529,11 +967,47 @@ func (m *LicenseManager) buildMenuItems() {
 	}
 }
 
+// rebinder is implemented by checkers that can move a node-locked license
+// to this machine, such as *tuish.SDK.
+type rebinder interface {
+	RebindMachine(ctx context.Context) error
+}
+
+// offerRebind switches to the rebind prompt the first time a check from the
+// menu reports a license bound to another machine.
+func (m *LicenseManager) offerRebind() {
+	if m.rebindOffered || m.screen != ScreenMenu {
+		return
+	}
+	if m.result == nil || m.result.Reason != tuish.ReasonMachineMismatch {
+		return
+	}
+	if _, ok := m.sdk.(rebinder); !ok {
+		return
+	}
+	m.rebindOffered = true
+	m.rebindError = ""
+	m.screen = ScreenConfirmRebind
+}
+
+func (m *LicenseManager) rebindMachine() tea.Msg {
+	return LicenseReboundMsg{Error: m.sdk.(rebinder).RebindMachine(context.Background())}
+}
+
 func (m *LicenseManager) checkLicense() tea.Msg {
-	result, err := m.sdk.CheckLicense(nil)
+	result, err := m.sdk.CheckLicense(context.Background())
 	return LicenseCheckedMsg{Result: result, Error: err}
 }
 
+// Refresh re-checks the license in the background. If the license turns out
+// to have been revoked, a LicenseRevokedMsg follows.
+func (m *LicenseManager) Refresh() tea.Cmd {
+	return func() tea.Msg {
+		result, err := m.sdk.CheckLicense(context.Background())
+		return LicenseRefreshedMsg{Result: result, Error: err}
+	}
+}
+
 // Screen returns the current screen.
 func (m *LicenseManager) Screen() ManagerScreen {
 	return m.screen
@@ -0,0 +1,663 @@
+package tui
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	tuish "github.com/tuishdotdev/tuish/go"
+	"github.com/tuishdotdev/tuish/go/tui/tuishtest"
+)
+
+func TestLicenseManagerStatusScreenBoundedByHeight(t *testing.T) {
+	manager := NewLicenseManager(nil)
+	manager.Update(LicenseCheckedMsg{Result: manyFeaturesResult(50)})
+	manager.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	manager.Update(tea.KeyMsg{Type: tea.KeyEnter}) // View License Status
+	if manager.Screen() != ScreenStatus {
+		t.Fatalf("expected status screen, got %d", manager.Screen())
+	}
+
+	view := manager.View()
+	if h := lipgloss.Height(view); h > 24 {
+		t.Fatalf("expected view height <= 24, got %d", h)
+	}
+	if !strings.Contains(view, "feature-00") {
+		t.Error("expected features on the status screen")
+	}
+	if !strings.Contains(view, "scroll") {
+		t.Error("expected scroll hint on the status screen")
+	}
+}
+
+// staleStore is a LicenseStore whose license is always due for a refresh.
+type staleStore struct{ licenseKey string }
+
+func (s *staleStore) Save(productID, licenseKey, machineFingerprint string) error {
+	s.licenseKey = licenseKey
+	return nil
+}
+
+func (s *staleStore) Load(productID string) (*tuish.CachedLicenseData, error) {
+	if s.licenseKey == "" {
+		return nil, nil
+	}
+	return &tuish.CachedLicenseData{LicenseKey: s.licenseKey, ProductID: productID}, nil
+}
+
+func (s *staleStore) Remove(productID string) error {
+	s.licenseKey = ""
+	return nil
+}
+
+func TestLicenseManagerRefreshDetectsServerRevocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"valid": false, "reason": "revoked"})
+	}))
+	defer server.Close()
+
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	headerBytes, _ := json.Marshal(tuish.LicenseHeader{Algorithm: "ed25519", Version: 1})
+	payloadBytes, _ := json.Marshal(tuish.LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", IssuedAt: time.Now().UnixMilli()})
+	message := base64.RawURLEncoding.EncodeToString(headerBytes) + "." + base64.RawURLEncoding.EncodeToString(payloadBytes)
+	license := message + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(privateKey, []byte(message)))
+
+	sdk, err := tuish.New(tuish.Config{
+		ProductID:  "prod_test",
+		PublicKey:  hex.EncodeToString(publicKey),
+		APIBaseURL: server.URL,
+		Store:      &staleStore{licenseKey: license},
+	})
+	if err != nil {
+		t.Fatalf("create SDK: %v", err)
+	}
+
+	manager := NewLicenseManager(sdk)
+	manager.Update(LicenseCheckedMsg{Result: manyFeaturesResult(1)})
+
+	_, cmd := manager.Update(manager.Refresh()())
+	if cmd == nil {
+		t.Fatal("expected the server's revocation to be detected")
+	}
+	if msg, ok := cmd().(LicenseRevokedMsg); !ok {
+		t.Fatalf("expected LicenseRevokedMsg, got %T", msg)
+	}
+}
+
+func TestLicenseManagerOffersPurchaseAfterRevocation(t *testing.T) {
+	manager := NewLicenseManager(nil)
+	manager.Update(LicenseCheckedMsg{Result: manyFeaturesResult(1)})
+
+	for _, item := range manager.menuItems {
+		if item.Value == "purchase" {
+			t.Fatal("expected no purchase item for a valid license")
+		}
+	}
+
+	_, cmd := manager.Update(LicenseRefreshedMsg{Result: revokedResult()})
+	if cmd == nil {
+		t.Fatal("expected a revocation command")
+	}
+	msg, ok := cmd().(LicenseRevokedMsg)
+	if !ok {
+		t.Fatalf("expected LicenseRevokedMsg, got %T", msg)
+	}
+	manager.Update(msg)
+
+	selected := manager.menuItems[manager.selectedIndex]
+	if selected.Value != "purchase" {
+		t.Errorf("expected purchase to be selected after revocation, got %q", selected.Value)
+	}
+	if !strings.Contains(manager.View(), "Revoked") {
+		t.Error("expected menu to show the revoked status")
+	}
+}
+
+func TestLicenseManagerQuickPurchaseKey(t *testing.T) {
+	manager := NewLicenseManager(nil)
+	manager.Update(LicenseCheckedMsg{Result: &tuish.LicenseCheckResult{Valid: false, Reason: tuish.ReasonNotFound}})
+
+	if !strings.Contains(manager.View(), "to purchase") {
+		t.Error("expected purchase hint in menu footer")
+	}
+
+	_, cmd := manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if manager.screen != ScreenPurchase {
+		t.Fatalf("expected purchase screen, got %v", manager.screen)
+	}
+	if manager.purchaseFlow == nil {
+		t.Fatal("expected purchase flow to be constructed")
+	}
+	if cmd == nil {
+		t.Error("expected purchase flow init command")
+	}
+}
+
+func TestLicenseManagerQuickPurchaseKeyIgnoredWhenLicensed(t *testing.T) {
+	manager := NewLicenseManager(nil)
+	manager.Update(LicenseCheckedMsg{Result: manyFeaturesResult(1)})
+
+	if strings.Contains(manager.View(), "to purchase") {
+		t.Error("expected no purchase hint for a valid license")
+	}
+
+	manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if manager.screen != ScreenMenu {
+		t.Errorf("expected to stay on menu, got %v", manager.screen)
+	}
+}
+
+// newSigningSDK returns an SDK backed by a temp dir and a function that signs
+// licenses it will accept.
+func newSigningSDK(t *testing.T) (*tuish.SDK, func(tuish.LicensePayload) string) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	sdk, err := tuish.New(tuish.Config{
+		ProductID:  "prod_test",
+		PublicKey:  hex.EncodeToString(publicKey),
+		StorageDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("create SDK: %v", err)
+	}
+
+	sign := func(payload tuish.LicensePayload) string {
+		headerBytes, _ := json.Marshal(tuish.LicenseHeader{Algorithm: "ed25519", Version: 1})
+		payloadBytes, _ := json.Marshal(payload)
+		message := base64.RawURLEncoding.EncodeToString(headerBytes) + "." +
+			base64.RawURLEncoding.EncodeToString(payloadBytes)
+		signature := ed25519.Sign(privateKey, []byte(message))
+		return message + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+
+	return sdk, sign
+}
+
+func typeKey(manager *LicenseManager, key string) {
+	manager.manualKeyInput = key
+	manager.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestLicenseManagerConfirmsManualKey(t *testing.T) {
+	sdk, sign := newSigningSDK(t)
+	future := time.Now().Add(24 * time.Hour).UnixMilli()
+	license := sign(tuish.LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		Features:  []string{"pro", "export"},
+		IssuedAt:  time.Now().UnixMilli(),
+		ExpiresAt: &future,
+	})
+
+	manager := NewLicenseManager(sdk)
+	manager.screen = ScreenEnterKey
+	typeKey(manager, license)
+
+	view := manager.View()
+	if !strings.Contains(view, "This license grants") || !strings.Contains(view, "pro, export") {
+		t.Fatalf("expected license preview, got:\n%s", view)
+	}
+	if sdk.GetCachedLicenseKey() != "" {
+		t.Fatal("expected nothing stored before confirmation")
+	}
+
+	_, cmd := manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Fatal("expected store command after confirmation")
+	}
+	if msg, ok := cmd().(LicenseStoredMsg); !ok || msg.Error != nil {
+		t.Fatalf("expected successful LicenseStoredMsg, got %#v", msg)
+	}
+	if sdk.GetCachedLicenseKey() != license {
+		t.Error("expected license to be stored after confirmation")
+	}
+}
+
+func TestLicenseManagerStorageNotWritable(t *testing.T) {
+	manager := NewLicenseManager(tuishtest.NewFakeSDK())
+	manager.screen = ScreenEnterKey
+
+	err := fmt.Errorf("%w: mkdir /ro: permission denied", tuish.ErrStorageNotWritable)
+	manager.Update(LicenseStoredMsg{Error: err})
+
+	view := manager.View()
+	if !strings.Contains(view, "Can't save license: storage not writable") || !strings.Contains(view, "TUISH_STORAGE_DIR") {
+		t.Errorf("expected storage hint, got:\n%s", view)
+	}
+}
+
+func TestLicenseManagerDeclinesManualKey(t *testing.T) {
+	sdk, sign := newSigningSDK(t)
+	license := sign(tuish.LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", IssuedAt: time.Now().UnixMilli()})
+
+	manager := NewLicenseManager(sdk)
+	manager.screen = ScreenEnterKey
+	typeKey(manager, license)
+
+	_, cmd := manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if cmd != nil {
+		t.Error("expected no command when declining")
+	}
+	if strings.Contains(manager.View(), "This license grants") {
+		t.Error("expected preview to be dismissed")
+	}
+	if manager.screen != ScreenEnterKey {
+		t.Errorf("expected to stay on enter-key screen, got %v", manager.screen)
+	}
+}
+
+func TestLicenseManagerPastesManualKey(t *testing.T) {
+	sdk, sign := newSigningSDK(t)
+	license := sign(tuish.LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", IssuedAt: time.Now().UnixMilli()})
+
+	manager := NewLicenseManager(sdk)
+	manager.screen = ScreenEnterKey
+
+	// A bracketed paste of a key wrapped across two lines
+	half := len(license) / 2
+	paste := license[:half] + "\n" + license[half:] + "\n"
+	manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(paste), Paste: true})
+
+	if manager.manualKeyInput != license {
+		t.Fatalf("expected the pasted key to be captured, got %q", manager.manualKeyInput)
+	}
+	manager.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if manager.pendingKey != license {
+		t.Errorf("expected the pasted key to be previewed, got error %q", manager.manualKeyError)
+	}
+}
+
+func TestLicenseManagerCapsManualKeyLength(t *testing.T) {
+	manager := NewLicenseManager(tuishtest.NewFakeSDK())
+	manager.screen = ScreenEnterKey
+	manager.manualKeyInput = strings.Repeat("a", tuish.MaxLicenseLength)
+
+	manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if got := len(manager.manualKeyInput); got != tuish.MaxLicenseLength {
+		t.Errorf("expected input capped at %d bytes, got %d", tuish.MaxLicenseLength, got)
+	}
+}
+
+func TestLicenseManagerRejectsExpiredManualKey(t *testing.T) {
+	sdk, sign := newSigningSDK(t)
+	past := time.Now().Add(-time.Hour).UnixMilli()
+	license := sign(tuish.LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", IssuedAt: past, ExpiresAt: &past})
+
+	manager := NewLicenseManager(sdk)
+	manager.screen = ScreenEnterKey
+	typeKey(manager, license)
+
+	if manager.pendingKey != "" {
+		t.Error("expected expired key not to be offered for storage")
+	}
+	if !strings.Contains(manager.View(), "expired") {
+		t.Error("expected expiry error message")
+	}
+}
+
+func TestPreviewErrorMessage(t *testing.T) {
+	tests := []struct {
+		reason tuish.LicenseInvalidReason
+		want   string
+	}{
+		{tuish.ReasonExpired, "expired"},
+		{tuish.ReasonMachineMismatch, "different machine"},
+		{tuish.ReasonProductMismatch, "different product"},
+		{tuish.ReasonInvalidSignature, "not genuine"},
+		{tuish.ReasonRevoked, "revoked"},
+		{tuish.ReasonInvalidFormat, "Invalid license key format"},
+	}
+	for _, tt := range tests {
+		if got := previewErrorMessage(tt.reason); !strings.Contains(got, tt.want) {
+			t.Errorf("previewErrorMessage(%s) = %q, want it to mention %q", tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestLicenseManagerInitialScreenPurchase(t *testing.T) {
+	manager := NewLicenseManager(nil, LicenseManagerConfig{InitialScreen: ScreenPurchase})
+
+	cmd := manager.Init()
+	if cmd == nil {
+		t.Fatal("expected Init to return commands")
+	}
+	if manager.Screen() != ScreenPurchase {
+		t.Fatalf("expected purchase screen, got %d", manager.Screen())
+	}
+	if manager.purchaseFlow == nil {
+		t.Fatal("expected purchase flow to be started")
+	}
+	if manager.purchaseFlow.Step() != PurchaseStepCreating {
+		t.Errorf("expected checkout to be creating, got %d", manager.purchaseFlow.Step())
+	}
+}
+
+func TestLicenseManagerCancelsPurchaseOnExit(t *testing.T) {
+	manager := NewLicenseManager(tuishtest.NewFakeSDK())
+	manager.startPurchase()
+	flow := manager.purchaseFlow
+
+	manager.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_1",
+		CheckoutURL: "https://example.com/checkout",
+	}})
+	seq := flow.pollSeq
+
+	manager.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if manager.Screen() != ScreenMenu || manager.purchaseFlow != nil {
+		t.Fatalf("expected the purchase flow to be torn down")
+	}
+	if flow.ctx.Err() == nil {
+		t.Error("expected the purchase context to be cancelled")
+	}
+	if _, cmd := flow.Update(CheckoutStatusMsg{Status: "pending", poll: seq}); cmd != nil {
+		t.Error("expected no poll to be scheduled after cancel")
+	}
+	if msg := flow.doPoll(flow.ctx, "sess_1", seq); msg != nil {
+		t.Errorf("expected an in-flight poll to stop, got %#v", msg)
+	}
+}
+
+func TestLicenseManagerInitialScreenStatus(t *testing.T) {
+	manager := NewLicenseManager(nil, LicenseManagerConfig{InitialScreen: ScreenStatus})
+
+	if manager.Init() == nil {
+		t.Fatal("expected Init to check the license")
+	}
+	if manager.Screen() != ScreenStatus {
+		t.Fatalf("expected status screen, got %d", manager.Screen())
+	}
+
+	manager.Update(LicenseCheckedMsg{Result: goldenStatusResult()})
+	if !strings.Contains(manager.View(), "Golden App") {
+		t.Errorf("expected checked license on status screen, got:\n%s", manager.View())
+	}
+}
+
+func TestLicenseManagerInitialScreenDiagnostics(t *testing.T) {
+	sdk := tuishtest.NewFakeSDK()
+	manager := NewLicenseManager(sdk, LicenseManagerConfig{InitialScreen: ScreenDiagnostics, ShowDiagnostics: true})
+
+	if manager.Init() == nil {
+		t.Fatal("expected Init to check the license")
+	}
+	if manager.Screen() != ScreenDiagnostics {
+		t.Fatalf("expected diagnostics screen, got %d", manager.Screen())
+	}
+	if !strings.Contains(manager.View(), sdk.GetMachineFingerprint()) {
+		t.Errorf("expected the fingerprint on the diagnostics screen, got:\n%s", manager.View())
+	}
+	if _, cmd := manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")}); cmd == nil {
+		t.Error("expected c to copy the fingerprint")
+	}
+
+	// A manager switched to the screen without loading must not panic
+	manager.diagnostics = nil
+	manager.View()
+	manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+}
+
+func TestLicenseManagerDiagnostics(t *testing.T) {
+	sdk, sign := newSigningSDK(t)
+	if err := sdk.StoreLicense(sign(tuish.LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	manager := NewLicenseManager(sdk, LicenseManagerConfig{ShowDiagnostics: true})
+	manager.Update(LicenseCheckedMsg{Result: &tuish.LicenseCheckResult{Valid: false}})
+
+	found := false
+	for i, item := range manager.menuItems {
+		if item.Value == "diagnostics" {
+			manager.selectedIndex = i
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected a Diagnostics menu item")
+	}
+	manager.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if manager.Screen() != ScreenDiagnostics {
+		t.Fatalf("expected diagnostics screen, got %d", manager.Screen())
+	}
+
+	view := manager.View()
+	for _, want := range []string{
+		sdk.GetMachineFingerprint(),
+		sdk.GetStorage().GetStorageDir(),
+		"prod_test",
+		tuish.Version,
+		"copy fingerprint",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected diagnostics to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	var out strings.Builder
+	clipboardOutput = &out
+	defer func() { clipboardOutput = os.Stdout }()
+
+	_, cmd := manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if cmd == nil {
+		t.Fatal("expected copy command")
+	}
+	manager.Update(cmd())
+	if !strings.Contains(out.String(), base64.StdEncoding.EncodeToString([]byte(sdk.GetMachineFingerprint()))) {
+		t.Errorf("expected OSC 52 sequence with the fingerprint, got %q", out.String())
+	}
+	if !strings.Contains(manager.View(), "Fingerprint copied") {
+		t.Error("expected copy confirmation")
+	}
+}
+
+// rebindingSDK is a FakeSDK that can rebind, failing with err if set.
+type rebindingSDK struct {
+	*tuishtest.FakeSDK
+	err     error
+	rebinds int
+}
+
+func (s *rebindingSDK) RebindMachine(ctx context.Context) error {
+	s.rebinds++
+	return s.err
+}
+
+func TestLicenseManagerOffersRebind(t *testing.T) {
+	sdk := &rebindingSDK{FakeSDK: tuishtest.NewFakeSDK()}
+	manager := NewLicenseManager(sdk)
+	mismatch := LicenseCheckedMsg{Result: tuishtest.InvalidResult(tuish.ReasonMachineMismatch)}
+
+	manager.Update(mismatch)
+	if manager.Screen() != ScreenConfirmRebind {
+		t.Fatalf("expected rebind prompt, got screen %d", manager.Screen())
+	}
+	if view := manager.View(); !strings.Contains(view, "bound to another machine") || !strings.Contains(view, "[y/n]") {
+		t.Errorf("expected rebind prompt, got:\n%s", view)
+	}
+
+	_, cmd := manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Fatal("expected rebind command")
+	}
+	if !strings.Contains(manager.View(), "Rebinding") {
+		t.Error("expected progress while rebinding")
+	}
+	msg, ok := cmd().(LicenseReboundMsg)
+	if !ok || msg.Error != nil || sdk.rebinds != 1 {
+		t.Fatalf("expected one successful rebind, got %#v after %d calls", msg, sdk.rebinds)
+	}
+
+	_, cmd = manager.Update(msg)
+	if manager.Screen() != ScreenMenu {
+		t.Errorf("expected menu after rebinding, got screen %d", manager.Screen())
+	}
+	if cmd == nil {
+		t.Fatal("expected a license check after rebinding")
+	}
+
+	// The prompt is only offered once
+	manager.Update(mismatch)
+	if manager.Screen() != ScreenMenu {
+		t.Errorf("expected no second rebind prompt, got screen %d", manager.Screen())
+	}
+}
+
+func TestLicenseManagerRebindNotAllowed(t *testing.T) {
+	sdk := &rebindingSDK{
+		FakeSDK: tuishtest.NewFakeSDK(),
+		err: &tuish.RebindNotAllowedError{Err: &tuish.APIError{
+			StatusCode: 403,
+			Code:       tuish.ErrCodeRebindDenied,
+			Message:    "rebinding is disabled",
+		}},
+	}
+	manager := NewLicenseManager(sdk)
+	manager.Update(LicenseCheckedMsg{Result: tuishtest.InvalidResult(tuish.ReasonMachineMismatch)})
+
+	_, cmd := manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	manager.Update(cmd())
+	if manager.Screen() != ScreenConfirmRebind {
+		t.Fatalf("expected to stay on the rebind screen, got %d", manager.Screen())
+	}
+	if view := manager.View(); !strings.Contains(view, "can't be moved to this machine") {
+		t.Errorf("expected not-allowed message, got:\n%s", view)
+	}
+
+	if _, cmd := manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd != nil {
+		t.Error("expected no retry once rebinding was refused")
+	}
+	manager.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if manager.Screen() != ScreenMenu {
+		t.Errorf("expected Esc to return to the menu, got screen %d", manager.Screen())
+	}
+}
+
+func TestLicenseManagerNoRebindWithoutSupport(t *testing.T) {
+	manager := NewLicenseManager(tuishtest.NewFakeSDK())
+	manager.Update(LicenseCheckedMsg{Result: tuishtest.InvalidResult(tuish.ReasonMachineMismatch)})
+	if manager.Screen() != ScreenMenu {
+		t.Errorf("expected no rebind prompt for a checker that can't rebind, got screen %d", manager.Screen())
+	}
+}
+
+func TestLicenseManagerFullWidthHighlight(t *testing.T) {
+	// Render colors so the background is visible (0 is termenv.TrueColor)
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(0)
+	defer lipgloss.SetColorProfile(profile)
+
+	manager := NewLicenseManager(nil, LicenseManagerConfig{FullWidthHighlight: true})
+	manager.buildMenuItems()
+	manager.selectedIndex = 1
+
+	var rows []string
+	for _, line := range strings.Split(manager.View(), "\n") {
+		for _, item := range manager.menuItems {
+			if strings.Contains(line, item.Label) {
+				rows = append(rows, line)
+			}
+		}
+	}
+	if len(rows) != len(manager.menuItems) {
+		t.Fatalf("expected %d menu rows, got %d", len(manager.menuItems), len(rows))
+	}
+
+	width := lipgloss.Width(rows[0])
+	for i, row := range rows {
+		if lipgloss.Width(row) != width {
+			t.Errorf("row %d is %d columns wide, expected %d", i, lipgloss.Width(row), width)
+		}
+		// 48; starts a background color
+		hasBackground := strings.Contains(row, "48;")
+		if i == manager.selectedIndex && !hasBackground {
+			t.Errorf("expected the active row to have a background, got %q", row)
+		}
+		if i != manager.selectedIndex && hasBackground {
+			t.Errorf("expected inactive row %d to have no background, got %q", i, row)
+		}
+	}
+}
+
+func TestLicenseManagerDiagnosticsHiddenByDefault(t *testing.T) {
+	manager := NewLicenseManager(nil)
+	manager.Update(LicenseCheckedMsg{Result: &tuish.LicenseCheckResult{Valid: false}})
+
+	for _, item := range manager.menuItems {
+		if item.Value == "diagnostics" {
+			t.Fatal("expected no Diagnostics item without ShowDiagnostics")
+		}
+	}
+}
+
+func pressKey(manager *LicenseManager, key string) {
+	manager.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+}
+
+func TestLicenseManagerVimKeys(t *testing.T) {
+	manager := NewLicenseManager(nil)
+	manager.Update(LicenseCheckedMsg{Result: &tuish.LicenseCheckResult{Valid: false, Reason: tuish.ReasonNotFound}})
+
+	pressKey(manager, "j")
+	pressKey(manager, "j")
+	if got := manager.menuItems[manager.selectedIndex].Value; got != "enter-key" {
+		t.Fatalf("expected j to move down to enter-key, got %q", got)
+	}
+
+	pressKey(manager, "k")
+	if got := manager.menuItems[manager.selectedIndex].Value; got != "purchase" {
+		t.Fatalf("expected k to move up to purchase, got %q", got)
+	}
+
+	// Arrow keys still work alongside
+	manager.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if manager.selectedIndex != 0 {
+		t.Errorf("expected up arrow to move to the top, got %d", manager.selectedIndex)
+	}
+}
+
+func TestLicenseManagerVimKeysConfirmClear(t *testing.T) {
+	manager := NewLicenseManager(nil)
+	manager.screen = ScreenConfirmClear
+
+	pressKey(manager, "l")
+	if manager.confirmSelected != 1 {
+		t.Fatal("expected l to move to Yes")
+	}
+	pressKey(manager, "h")
+	if manager.confirmSelected != 0 {
+		t.Error("expected h to move back to No")
+	}
+}
+
+func TestLicenseManagerVimKeysDisabled(t *testing.T) {
+	manager := NewLicenseManager(nil, LicenseManagerConfig{DisableVimKeys: true})
+	manager.Update(LicenseCheckedMsg{Result: &tuish.LicenseCheckResult{Valid: false, Reason: tuish.ReasonNotFound}})
+
+	pressKey(manager, "j")
+	if manager.selectedIndex != 0 {
+		t.Errorf("expected j to be ignored with DisableVimKeys, got index %d", manager.selectedIndex)
+	}
+}
@@ -1,8 +1,8 @@
 package tui
 
 import (
+	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,9 +18,28 @@ type LicenseStatusConfig struct {
 	// ShowExpiry displays the expiration date (default: true).
 	ShowExpiry bool
 
+	// RenewWithin shows a "renew soon" warning when a valid license expires
+	// within this duration (0 disables it).
+	RenewWithin time.Duration
+
+	// DisableVimKeys turns off the j/k aliases for scrolling features.
+	DisableVimKeys bool
+
 	// Compact uses single-line display mode.
 	Compact bool
 
+	// BadgeMode shows features as a row of badges like "[pro] [export]" in
+	// compact mode, instead of a feature count.
+	BadgeMode bool
+
+	// BadgeWidth is the most columns the badge row may use; badges that
+	// don't fit are summarized as "+N more" (0 = no limit).
+	BadgeWidth int
+
+	// Inline renders a single block with no leading or trailing newlines,
+	// for composing into a parent layout with lipgloss.JoinVertical.
+	Inline bool
+
 	// Styles allows custom styling (uses DefaultStyles if nil).
 	Styles *Styles
 }
@@ -36,17 +55,21 @@ func DefaultLicenseStatusConfig() LicenseStatusConfig {
 
 // LicenseStatus displays the current license status.
 type LicenseStatus struct {
-	sdk         *tuish.SDK
+	sdk         LicenseChecker
 	config      LicenseStatusConfig
 	styles      Styles
 	result      *tuish.LicenseCheckResult
 	loading     bool
 	offlineMode bool
 	err         error
+
+	// height is the number of rows available for rendering (0 = unbounded).
+	height        int
+	featureOffset int
 }
 
 // NewLicenseStatus creates a new LicenseStatus component.
-func NewLicenseStatus(sdk *tuish.SDK, config ...LicenseStatusConfig) *LicenseStatus {
+func NewLicenseStatus(sdk LicenseChecker, config ...LicenseStatusConfig) *LicenseStatus {
 	cfg := DefaultLicenseStatusConfig()
 	if len(config) > 0 {
 		cfg = config[0]
@@ -80,15 +103,36 @@ func (m *LicenseStatus) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.offlineMode = true
 		} else {
 			m.result = msg.Result
-			m.offlineMode = !msg.Result.OfflineVerified
+			m.offlineMode = msg.Result.Source == tuish.LicenseSourceOffline
+		}
+		return m, nil
+
+	case LicenseRefreshedMsg:
+		if msg.Error == nil && msg.Result != nil {
+			m.err = nil
+			m.result = msg.Result
+			m.offlineMode = msg.Result.Source == tuish.LicenseSourceOffline
 		}
 		return m, nil
 
+	case LicenseRevokedMsg:
+		m.err = nil
+		m.result = msg.Result
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.SetHeight(msg.Height)
+		return m, nil
+
 	case tea.KeyMsg:
-		switch msg.String() {
+		switch navigationKey(msg.String(), m.config.DisableVimKeys) {
 		case KeyR:
 			m.loading = true
 			return m, m.checkLicense
+		case KeyUp:
+			m.scrollFeatures(-1)
+		case KeyDown:
+			m.scrollFeatures(1)
 		}
 	}
 
@@ -97,156 +141,305 @@ func (m *LicenseStatus) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the LicenseStatus component.
 func (m *LicenseStatus) View() string {
+	if m.config.Inline {
+		return inlineBlock(m.render())
+	}
+	return m.render()
+}
+
+func (m *LicenseStatus) render() string {
 	if m.loading {
-		return m.styles.Muted.Render("Checking license...")
+		return m.styles.Muted.Render(tr("checking_license"))
 	}
 
 	if m.err != nil {
-		return m.styles.Error.Render(fmt.Sprintf("Error: %v", m.err))
+		return m.styles.Error.Render(tr("status.error", m.err))
+	}
+
+	return renderLicenseStatus(m.result, m.config, m.styles, m.offlineMode, m.visibleFeatureRange)
+}
+
+// renderLicenseStatus renders a check result for both the LicenseStatus
+// component and RenderLicenseStatus, so the two can't drift apart. visible
+// returns the bounds of the features in view; nil shows them all.
+func renderLicenseStatus(result *tuish.LicenseCheckResult, cfg LicenseStatusConfig, styles Styles, offline bool, visible func() (int, int)) string {
+	if isRevoked(result) {
+		return renderRevoked(styles, cfg.Compact)
+	}
+
+	if result == nil || result.License == nil {
+		return renderNoLicense(styles)
+	}
+
+	if cfg.Compact {
+		return renderStatusCompact(result, cfg, styles, offline)
 	}
 
-	if m.result == nil || m.result.License == nil {
-		return m.renderNoLicense()
+	return renderStatusFull(result, cfg, styles, offline, visible)
+}
+
+// isRevoked reports whether a check result represents a revoked license.
+func isRevoked(result *tuish.LicenseCheckResult) bool {
+	if result == nil || result.Valid {
+		return false
+	}
+	if result.Reason == tuish.ReasonRevoked {
+		return true
 	}
+	return result.License != nil && result.License.Status == tuish.LicenseStatusRevoked
+}
 
-	if m.config.Compact {
-		return m.renderCompact()
+// renderRevoked renders a revoked license with guidance on what to do next.
+func renderRevoked(styles Styles, compact bool) string {
+	if compact {
+		return styles.StatusRevoked.Render(styles.glyphs().Cross + " " + tr("status.revoked"))
 	}
 
-	return m.renderFull()
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		styles.StatusRevoked.Render(styles.glyphs().Cross+" "+tr("status.revoked_title")),
+		"",
+		styles.Body.Render(tr("status.revoked_body")),
+		styles.Muted.Render(tr("status.revoked_help")),
+	)
 }
 
-func (m *LicenseStatus) renderNoLicense() string {
+func renderNoLicense(styles Styles) string {
 	return lipgloss.JoinHorizontal(
 		lipgloss.Top,
-		m.styles.Warning.Render(WarningSign+" "),
-		m.styles.Warning.Render("No license"),
+		styles.Warning.Render(styles.glyphs().Warning+" "),
+		styles.Warning.Render(tr("status.no_license")),
 	)
 }
 
-func (m *LicenseStatus) renderCompact() string {
-	license := m.result.License
-	isValid := m.result.Valid
+func renderStatusCompact(result *tuish.LicenseCheckResult, cfg LicenseStatusConfig, styles Styles, offline bool) string {
+	license := result.License
 
 	var status string
 	var statusStyle lipgloss.Style
-	if isValid {
-		status = CheckMark
-		statusStyle = m.styles.StatusValid
+	if result.Valid {
+		status = styles.glyphs().Check
+		statusStyle = styles.StatusValid
 	} else {
-		status = CrossMark
-		statusStyle = m.styles.StatusInvalid
+		status = styles.glyphs().Cross
+		statusStyle = styles.StatusInvalid
 	}
 
 	name := license.ProductName
 	if name == "" {
-		name = "Licensed"
+		name = tr("status.licensed")
 	}
 
-	featureCount := len(license.Features)
-	featureText := fmt.Sprintf("%d feature", featureCount)
-	if featureCount != 1 {
-		featureText += "s"
+	offlineNote := ""
+	if offline {
+		offlineNote = " " + offlineIndicator(result, styles)
 	}
 
-	offlineIndicator := ""
-	if m.offlineMode {
-		offlineIndicator = " (offline)"
+	if cfg.BadgeMode {
+		badges := renderFeatureBadges(license.Features, cfg.BadgeWidth, statusStyle, styles.Muted)
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			statusStyle.Render(status),
+			" ",
+			styles.Body.Render(fmt.Sprintf("%s %s ", name, styles.glyphs().Bullet)),
+			badges,
+			offlineNote,
+		)
 	}
 
+	featureText := featureCount(len(license.Features))
+
 	return lipgloss.JoinHorizontal(
 		lipgloss.Top,
 		statusStyle.Render(status),
 		" ",
-		m.styles.Body.Render(fmt.Sprintf("%s %s %s%s", name, BulletPoint, featureText, offlineIndicator)),
+		styles.Body.Render(fmt.Sprintf("%s %s %s", name, styles.glyphs().Bullet, featureText)),
+		offlineNote,
 	)
 }
 
-func (m *LicenseStatus) renderFull() string {
-	license := m.result.License
-	isValid := m.result.Valid
+// offlineIndicator notes that a result was verified offline. It is amber
+// when the cache was due for revalidation but the server couldn't be reached.
+func offlineIndicator(result *tuish.LicenseCheckResult, styles Styles) string {
+	if result.StaleOffline {
+		return styles.Warning.Render(tr("status.offline_stale"))
+	}
+	return styles.Muted.Render(tr("status.offline"))
+}
+
+func renderStatusFull(result *tuish.LicenseCheckResult, cfg LicenseStatusConfig, styles Styles, offline bool, visible func() (int, int)) string {
+	license := result.License
 
 	var lines []string
 
 	// Status line with icon
-	var statusIcon, statusText string
+	var statusIcon string
 	var statusStyle lipgloss.Style
-	if isValid {
-		statusIcon = CheckMark
-		statusStyle = m.styles.StatusValid
+	if result.Valid {
+		statusIcon = styles.glyphs().Check
+		statusStyle = styles.StatusValid
 	} else {
-		statusIcon = CrossMark
-		statusStyle = m.styles.StatusInvalid
+		statusIcon = styles.glyphs().Cross
+		statusStyle = styles.StatusInvalid
 	}
 
 	name := license.ProductName
 	if name == "" {
-		name = "License"
+		name = tr("status.license")
 	}
 
 	statusLine := lipgloss.JoinHorizontal(
 		lipgloss.Top,
 		statusStyle.Render(statusIcon),
 		" ",
-		m.styles.Bold.Render(name),
+		styles.Bold.Render(name),
 	)
-	if m.offlineMode {
-		statusLine = lipgloss.JoinHorizontal(lipgloss.Top, statusLine, " ", m.styles.Muted.Render("(offline)"))
+	if offline {
+		statusLine = lipgloss.JoinHorizontal(lipgloss.Top, statusLine, " ", offlineIndicator(result, styles))
 	}
 	lines = append(lines, statusLine)
 
 	// Status detail
-	statusText = string(license.Status)
 	var statusColor lipgloss.Style
 	switch license.Status {
 	case tuish.LicenseStatusActive:
-		statusColor = m.styles.Success
+		statusColor = styles.Success
 	case tuish.LicenseStatusExpired:
-		statusColor = m.styles.Error
-	case tuish.LicenseStatusRevoked:
-		statusColor = m.styles.Error
+		statusColor = styles.Error
+	case tuish.LicenseStatusRevoked, tuish.LicenseStatusInvalid:
+		statusColor = styles.Error
 	default:
-		statusColor = m.styles.Muted
+		statusColor = styles.Muted
 	}
 
 	lines = append(lines, lipgloss.JoinHorizontal(
 		lipgloss.Top,
-		m.styles.Muted.Render("Status: "),
-		statusColor.Render(statusText),
+		styles.Muted.Render(tr("status.status_label")),
+		statusColor.Render(string(license.Status)),
 	))
 
 	// Features
-	if m.config.ShowFeatures && len(license.Features) > 0 {
-		lines = append(lines, m.styles.Muted.Render("Features:"))
-		for _, feature := range license.Features {
-			lines = append(lines, m.styles.ListItem.Render(BulletPoint+" "+feature))
+	if cfg.ShowFeatures && len(license.Features) > 0 {
+		lines = append(lines, styles.Muted.Render(tr("status.features_label")))
+
+		start, end := 0, len(license.Features)
+		if visible != nil {
+			start, end = visible()
+		}
+		for _, feature := range license.Features[start:end] {
+			lines = append(lines, styles.ListItem.Render(styles.glyphs().Bullet+" "+feature))
+		}
+
+		if below := len(license.Features) - end; below > 0 {
+			lines = append(lines, styles.ListItem.Render(styles.Muted.Render(tr("status.more_features", below)+styles.glyphs().Ellipsis)))
+		} else if start > 0 {
+			lines = append(lines, styles.ListItem.Render(styles.Muted.Render(tr("status.features_above", start))))
 		}
 	}
 
 	// Expiry
-	if m.config.ShowExpiry {
-		expiryText := m.formatExpiry(license.ExpiresAt)
+	if cfg.ShowExpiry {
 		lines = append(lines, lipgloss.JoinHorizontal(
 			lipgloss.Top,
-			m.styles.Muted.Render("Expires: "),
-			m.styles.Body.Render(expiryText),
+			styles.Muted.Render(tr("status.expires_label")),
+			styles.Body.Render(formatExpiry(license.ExpiresAt)),
 		))
 	}
 
+	if notice := renewSoonNotice(result, cfg.RenewWithin, styles); notice != "" {
+		lines = append(lines, notice)
+	}
+
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
-func (m *LicenseStatus) formatExpiry(timestamp *int64) string {
+// featureCount describes how many features a license has, e.g. "3 features".
+func featureCount(n int) string {
+	if n == 1 {
+		return tr("status.feature_count_one", n)
+	}
+	return tr("status.feature_count_other", n)
+}
+
+// renewSoonNotice returns a warning line when the license expires within
+// the given duration, or "" otherwise.
+func renewSoonNotice(result *tuish.LicenseCheckResult, within time.Duration, styles Styles) string {
+	if within <= 0 || !result.ExpiringSoon(within) {
+		return ""
+	}
+
+	text := tr("status.renew_today")
+	if days := *result.License.DaysUntilExpiry(); days > 1 {
+		text = tr("status.renew_days", days)
+	} else if days == 1 {
+		text = tr("status.renew_tomorrow")
+	}
+	return styles.Warning.Render(styles.glyphs().Warning + " " + text)
+}
+
+// formatExpiry formats an expiry timestamp as a date, or "Never".
+func formatExpiry(timestamp *int64) string {
 	if timestamp == nil {
-		return "Never"
+		return tr("date.never")
 	}
 
 	t := time.UnixMilli(*timestamp)
-	return t.Format("Jan 2, 2006")
+	return t.Format(tr("date.format"))
+}
+
+// featureRows returns how many feature rows fit in the available height.
+// Returns -1 when the height is unbounded.
+func (m *LicenseStatus) featureRows() int {
+	if m.height <= 0 {
+		return -1
+	}
+
+	// Status line, status detail, "Features:" header and the scroll indicator
+	fixed := 4
+	if m.config.ShowExpiry {
+		fixed++
+	}
+	if m.result.ExpiringSoon(m.config.RenewWithin) {
+		fixed++
+	}
+
+	rows := m.height - fixed
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// visibleFeatureRange returns the slice bounds of the features currently in view.
+func (m *LicenseStatus) visibleFeatureRange() (int, int) {
+	total := 0
+	if m.result != nil && m.result.License != nil {
+		total = len(m.result.License.Features)
+	}
+
+	rows := m.featureRows()
+	if rows < 0 || total <= rows {
+		return 0, total
+	}
+
+	start := m.featureOffset
+	if start > total-rows {
+		start = total - rows
+	}
+	if start < 0 {
+		start = 0
+	}
+	return start, start + rows
+}
+
+func (m *LicenseStatus) scrollFeatures(delta int) {
+	m.featureOffset += delta
+	start, _ := m.visibleFeatureRange()
+	m.featureOffset = start
 }
 
 func (m *LicenseStatus) checkLicense() tea.Msg {
-	result, err := m.sdk.CheckLicense(nil)
+	result, err := m.sdk.CheckLicense(context.Background())
 	return LicenseCheckedMsg{Result: result, Error: err}
 }
 
@@ -260,6 +453,11 @@ func (m *LicenseStatus) IsValid() bool {
 	return m.result != nil && m.result.Valid
 }
 
+// IsRevoked returns whether the license was revoked.
+func (m *LicenseStatus) IsRevoked() bool {
+	return isRevoked(m.result)
+}
+
 // IsLoading returns whether the component is loading.
 func (m *LicenseStatus) IsLoading() bool {
 	return m.loading
@@ -271,8 +469,26 @@ func (m *LicenseStatus) Refresh() tea.Cmd {
 	return m.checkLicense
 }
 
+// SetHeight sets the number of rows available to the component. When the
+// feature list doesn't fit, it becomes scrollable with the up/down keys.
+// A height of 0 disables scrolling.
+func (m *LicenseStatus) SetHeight(height int) {
+	m.height = height
+	m.scrollFeatures(0)
+}
+
+// CanScroll returns whether the feature list overflows the available height.
+func (m *LicenseStatus) CanScroll() bool {
+	if m.result == nil || m.result.License == nil || !m.config.ShowFeatures {
+		return false
+	}
+	rows := m.featureRows()
+	return rows >= 0 && len(m.result.License.Features) > rows
+}
+
 // RenderLicenseStatus is a helper function to render license status as a string
-// without needing the full Bubble Tea model.
+// without needing the full Bubble Tea model. It renders exactly what a
+// LicenseStatus with the same config shows for the result.
 func RenderLicenseStatus(result *tuish.LicenseCheckResult, config ...LicenseStatusConfig) string {
 	cfg := DefaultLicenseStatusConfig()
 	if len(config) > 0 {
@@ -284,87 +500,19 @@ func RenderLicenseStatus(result *tuish.LicenseCheckResult, config ...LicenseStat
 		styles = *cfg.Styles
 	}
 
-	if result == nil || result.License == nil {
-		return styles.Warning.Render(WarningSign + " No license")
-	}
-
-	license := result.License
-
-	if cfg.Compact {
-		// Compact mode
-		var status string
-		var statusStyle lipgloss.Style
-		if result.Valid {
-			status = CheckMark
-			statusStyle = styles.StatusValid
-		} else {
-			status = CrossMark
-			statusStyle = styles.StatusInvalid
-		}
-
-		name := license.ProductName
-		if name == "" {
-			name = "Licensed"
-		}
-
-		featureCount := len(license.Features)
-		featureText := fmt.Sprintf("%d feature", featureCount)
-		if featureCount != 1 {
-			featureText += "s"
-		}
-
-		return statusStyle.Render(status) + " " + styles.Body.Render(name+" "+BulletPoint+" "+featureText)
-	}
-
-	// Full mode
-	var sb strings.Builder
-
-	// Status line
-	var statusIcon string
-	var statusStyle lipgloss.Style
-	if result.Valid {
-		statusIcon = CheckMark
-		statusStyle = styles.StatusValid
-	} else {
-		statusIcon = CrossMark
-		statusStyle = styles.StatusInvalid
-	}
-
-	name := license.ProductName
-	if name == "" {
-		name = "License"
-	}
-
-	sb.WriteString(statusStyle.Render(statusIcon) + " " + styles.Bold.Render(name) + "\n")
-
-	// Status detail
-	var statusColor lipgloss.Style
-	switch license.Status {
-	case tuish.LicenseStatusActive:
-		statusColor = styles.Success
-	default:
-		statusColor = styles.Error
-	}
-	sb.WriteString(styles.Muted.Render("Status: ") + statusColor.Render(string(license.Status)) + "\n")
-
-	// Features
-	if cfg.ShowFeatures && len(license.Features) > 0 {
-		sb.WriteString(styles.Muted.Render("Features:") + "\n")
-		for _, feature := range license.Features {
-			sb.WriteString(styles.ListItem.Render(BulletPoint+" "+feature) + "\n")
-		}
-	}
-
-	// Expiry
-	if cfg.ShowExpiry {
-		var expiryText string
-		if license.ExpiresAt == nil {
-			expiryText = "Never"
-		} else {
-			expiryText = time.UnixMilli(*license.ExpiresAt).Format("Jan 2, 2006")
-		}
-		sb.WriteString(styles.Muted.Render("Expires: ") + styles.Body.Render(expiryText))
+	offline := result != nil && result.Source == tuish.LicenseSourceOffline
+	view := renderLicenseStatus(result, cfg, styles, offline, nil)
+	if cfg.Inline {
+		return inlineBlock(view)
 	}
+	return view
+}
 
-	return sb.String()
+// RenderLicenseStatusThemed renders license status like RenderLicenseStatus
+// with the default config, styled with theme.
+func RenderLicenseStatusThemed(result *tuish.LicenseCheckResult, theme Theme) string {
+	styles := NewStyles(theme)
+	cfg := DefaultLicenseStatusConfig()
+	cfg.Styles = &styles
+	return RenderLicenseStatus(result, cfg)
 }
@@ -0,0 +1,379 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	tuish "github.com/tuishdotdev/tuish/go"
+)
+
+func manyFeaturesResult(n int) *tuish.LicenseCheckResult {
+	features := make([]string, n)
+	for i := range features {
+		features[i] = fmt.Sprintf("feature-%02d", i)
+	}
+	return &tuish.LicenseCheckResult{
+		Valid:           true,
+		OfflineVerified: true,
+		License: &tuish.LicenseDetails{
+			ID:        "lic_test",
+			ProductID: "prod_test",
+			Features:  features,
+			Status:    tuish.LicenseStatusActive,
+		},
+	}
+}
+
+func TestLicenseStatusFeatureListBoundedByHeight(t *testing.T) {
+	status := NewLicenseStatus(nil)
+	status.Update(LicenseCheckedMsg{Result: manyFeaturesResult(50)})
+	status.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+	view := status.View()
+	if h := lipgloss.Height(view); h > 20 {
+		t.Fatalf("expected view height <= 20, got %d", h)
+	}
+	if !strings.Contains(view, "feature-00") {
+		t.Error("expected first feature to be visible")
+	}
+	if strings.Contains(view, "feature-49") {
+		t.Error("expected last feature to be scrolled out of view")
+	}
+	if !strings.Contains(view, "more"+Ellipsis) {
+		t.Error("expected a more indicator")
+	}
+	if !status.CanScroll() {
+		t.Error("expected CanScroll to be true")
+	}
+}
+
+func TestLicenseStatusScrollsFeatures(t *testing.T) {
+	status := NewLicenseStatus(nil)
+	status.Update(LicenseCheckedMsg{Result: manyFeaturesResult(50)})
+	status.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+	for i := 0; i < 100; i++ {
+		status.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+
+	view := status.View()
+	if h := lipgloss.Height(view); h > 20 {
+		t.Fatalf("expected view height <= 20, got %d", h)
+	}
+	if !strings.Contains(view, "feature-49") {
+		t.Error("expected last feature after scrolling to the end")
+	}
+	if strings.Contains(view, "feature-00") {
+		t.Error("expected first feature to be scrolled out of view")
+	}
+
+	status.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if strings.Contains(status.View(), "feature-49") {
+		t.Error("expected scrolling up to move the window")
+	}
+}
+
+func TestLicenseStatusScrollsWithVimKeys(t *testing.T) {
+	status := NewLicenseStatus(nil)
+	status.Update(LicenseCheckedMsg{Result: manyFeaturesResult(50)})
+	status.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+	status.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if status.featureOffset != 1 {
+		t.Fatalf("expected j to scroll down, got offset %d", status.featureOffset)
+	}
+	status.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	if status.featureOffset != 0 {
+		t.Errorf("expected k to scroll up, got offset %d", status.featureOffset)
+	}
+}
+
+func TestLicenseStatusUnboundedShowsAllFeatures(t *testing.T) {
+	status := NewLicenseStatus(nil)
+	status.Update(LicenseCheckedMsg{Result: manyFeaturesResult(50)})
+
+	view := status.View()
+	if !strings.Contains(view, "feature-00") || !strings.Contains(view, "feature-49") {
+		t.Error("expected all features without a height limit")
+	}
+	if status.CanScroll() {
+		t.Error("expected CanScroll to be false without a height limit")
+	}
+}
+
+func revokedResult() *tuish.LicenseCheckResult {
+	return &tuish.LicenseCheckResult{
+		Valid:  false,
+		Reason: tuish.ReasonRevoked,
+		Source: tuish.LicenseSourceOnline,
+	}
+}
+
+func TestLicenseStatusRendersRevoked(t *testing.T) {
+	status := NewLicenseStatus(nil)
+	status.Update(LicenseCheckedMsg{Result: revokedResult()})
+
+	view := status.View()
+	if !strings.Contains(view, "License revoked") {
+		t.Errorf("expected revoked heading, got:\n%s", view)
+	}
+	if !strings.Contains(view, "contact support") {
+		t.Errorf("expected guidance for revoked licenses, got:\n%s", view)
+	}
+	if strings.Contains(view, "No license") {
+		t.Error("expected revoked license not to render as missing")
+	}
+	if !status.IsRevoked() {
+		t.Error("expected IsRevoked to be true")
+	}
+}
+
+func TestRenderLicenseStatusRevokedCompact(t *testing.T) {
+	view := RenderLicenseStatus(revokedResult(), LicenseStatusConfig{Compact: true})
+	if !strings.Contains(view, "Revoked") {
+		t.Errorf("expected compact revoked indicator, got %q", view)
+	}
+}
+
+func TestRenderLicenseStatusBadgeMode(t *testing.T) {
+	view := RenderLicenseStatus(manyFeaturesResult(3), LicenseStatusConfig{Compact: true, BadgeMode: true, BadgeWidth: 33})
+	if !strings.Contains(view, "[feature-00] [feature-01] +1 more") {
+		t.Errorf("expected truncated badge row, got %q", view)
+	}
+	if strings.Contains(view, "3 features") {
+		t.Errorf("expected badges instead of a feature count, got %q", view)
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// assertGolden compares got against testdata/<name>.golden.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("create testdata: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("write golden: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden (run with -update to create): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func goldenStatusResult() *tuish.LicenseCheckResult {
+	expires := time.Date(2030, time.January, 15, 12, 0, 0, 0, time.UTC).UnixMilli()
+	return &tuish.LicenseCheckResult{
+		Valid:           true,
+		OfflineVerified: true,
+		License: &tuish.LicenseDetails{
+			ID:          "lic_golden",
+			ProductName: "Golden App",
+			Features:    []string{"pro", "export"},
+			Status:      tuish.LicenseStatusActive,
+			ExpiresAt:   &expires,
+		},
+	}
+}
+
+func TestLicenseStatusRenewSoon(t *testing.T) {
+	result := manyFeaturesResult(1)
+	expiresAt := time.Now().Add(3*24*time.Hour - time.Minute).UnixMilli()
+	result.License.ExpiresAt = &expiresAt
+
+	cfg := DefaultLicenseStatusConfig()
+	cfg.RenewWithin = 7 * 24 * time.Hour
+	status := NewLicenseStatus(nil, cfg)
+	status.Update(LicenseCheckedMsg{Result: result})
+
+	if view := status.View(); !strings.Contains(view, "Expires in 3 days, renew soon") {
+		t.Errorf("expected renew soon notice, got:\n%s", view)
+	}
+	if view := RenderLicenseStatus(result, cfg); !strings.Contains(view, "renew soon") {
+		t.Errorf("expected renew soon notice from RenderLicenseStatus, got:\n%s", view)
+	}
+
+	cfg.RenewWithin = 24 * time.Hour
+	status = NewLicenseStatus(nil, cfg)
+	status.Update(LicenseCheckedMsg{Result: result})
+	if view := status.View(); strings.Contains(view, "renew soon") {
+		t.Errorf("expected no notice outside the window, got:\n%s", view)
+	}
+}
+
+func TestLicenseStatusInlineGolden(t *testing.T) {
+	render := func(inline bool) string {
+		config := DefaultLicenseStatusConfig()
+		config.Inline = inline
+		status := NewLicenseStatus(nil, config)
+		status.Update(LicenseCheckedMsg{Result: goldenStatusResult()})
+		return status.View()
+	}
+
+	view := render(false)
+	assertGolden(t, "license_status_default", view)
+
+	// LicenseStatus draws no box and no surrounding newlines, so unlike
+	// PurchaseFlow its inline view is the default view unchanged
+	inline := render(true)
+	if inline != view {
+		t.Errorf("expected the inline view to match the default view\ninline:\n%s\ndefault:\n%s", inline, view)
+	}
+	if strings.HasPrefix(inline, "\n") || strings.HasSuffix(inline, "\n") {
+		t.Error("expected inline view without surrounding newlines")
+	}
+}
+
+func TestRenderLicenseStatusMatchesComponent(t *testing.T) {
+	expiresAt := time.Now().Add(3 * 24 * time.Hour).UnixMilli()
+	expiring := manyFeaturesResult(3)
+	expiring.License.ExpiresAt = &expiresAt
+	offline := goldenStatusResult()
+	offline.Source = tuish.LicenseSourceOffline
+
+	configs := map[string]LicenseStatusConfig{
+		"default":   DefaultLicenseStatusConfig(),
+		"compact":   {Compact: true},
+		"no expiry": {ShowFeatures: true},
+		"renew":     {ShowExpiry: true, RenewWithin: 7 * 24 * time.Hour},
+		"inline":    {ShowFeatures: true, ShowExpiry: true, Inline: true},
+	}
+
+	for name, cfg := range configs {
+		for _, result := range []*tuish.LicenseCheckResult{goldenStatusResult(), expiring, offline, {Reason: tuish.ReasonNotFound}} {
+			status := NewLicenseStatus(nil, cfg)
+			status.Update(LicenseCheckedMsg{Result: result})
+
+			if got, want := RenderLicenseStatus(result, cfg), status.View(); got != want {
+				t.Errorf("%s: helper output differs from component\nhelper:\n%s\ncomponent:\n%s", name, got, want)
+			}
+		}
+	}
+}
+
+func TestRenderLicenseStatusWithoutExpiry(t *testing.T) {
+	cfg := DefaultLicenseStatusConfig()
+	cfg.ShowExpiry = false
+
+	view := RenderLicenseStatus(goldenStatusResult(), cfg)
+	if strings.Contains(view, "Expires") {
+		t.Errorf("expected no expiry line, got:\n%s", view)
+	}
+	if strings.HasSuffix(view, "\n") {
+		t.Errorf("expected no trailing newline, got %q", view)
+	}
+}
+
+func TestRenderLicenseStatusThemed(t *testing.T) {
+	theme := DefaultTheme
+	theme.Success = lipgloss.Color("#00FF00")
+	styles := NewStyles(theme)
+
+	cfg := DefaultLicenseStatusConfig()
+	cfg.Styles = &styles
+
+	result := goldenStatusResult()
+	if got, want := RenderLicenseStatusThemed(result, theme), RenderLicenseStatus(result, cfg); got != want {
+		t.Errorf("expected themed output to match styles built from the theme\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderLicenseStatusAgreesAcrossStatuses(t *testing.T) {
+	// Mark each style so the status color shows up in plain output
+	styles := DefaultStyles().With(func(s *Styles) {
+		s.Success = s.Success.Transform(func(v string) string { return "<success>" + v })
+		s.Error = s.Error.Transform(func(v string) string { return "<error>" + v })
+		s.Muted = s.Muted.Transform(func(v string) string { return "<muted>" + v })
+	})
+	cfg := DefaultLicenseStatusConfig()
+	cfg.Styles = &styles
+
+	withStatus := func(status tuish.LicenseStatus, valid bool) *tuish.LicenseCheckResult {
+		result := goldenStatusResult()
+		result.Valid = valid
+		result.License.Status = status
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		result *tuish.LicenseCheckResult
+		want   string
+	}{
+		{"active", withStatus(tuish.LicenseStatusActive, true), "<success>active"},
+		{"expired", withStatus(tuish.LicenseStatusExpired, false), "<error>expired"},
+		{"revoked", withStatus(tuish.LicenseStatusRevoked, false), "License revoked"},
+		{"unknown", withStatus("suspended", false), "<muted>suspended"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := NewLicenseStatus(nil, cfg)
+			status.Update(LicenseCheckedMsg{Result: tt.result})
+
+			component := status.View()
+			helper := RenderLicenseStatus(tt.result, cfg)
+			if component != helper {
+				t.Errorf("helper output differs from component\nhelper:\n%s\ncomponent:\n%s", helper, component)
+			}
+			if !strings.Contains(helper, tt.want) {
+				t.Errorf("expected %q in output, got:\n%s", tt.want, helper)
+			}
+		})
+	}
+}
+
+func TestLicenseStatusOfflineIndicators(t *testing.T) {
+	// Render colors so the amber warning is visible (0 is termenv.TrueColor)
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(0)
+	defer lipgloss.SetColorProfile(profile)
+
+	fresh := manyFeaturesResult(1)
+	fresh.Source = tuish.LicenseSourceOffline
+
+	stale := manyFeaturesResult(1)
+	stale.Source = tuish.LicenseSourceOffline
+	stale.StaleOffline = true
+
+	staleNote := DefaultStyles().Warning.Render("(offline — server unreachable, using cached license)")
+
+	for _, compact := range []bool{false, true} {
+		cfg := DefaultLicenseStatusConfig()
+		cfg.Compact = compact
+
+		status := NewLicenseStatus(nil, cfg)
+		status.Update(LicenseCheckedMsg{Result: fresh})
+		if view := status.View(); !strings.Contains(view, "(offline)") || strings.Contains(view, "server unreachable") {
+			t.Errorf("compact=%v: expected the plain offline indicator for a fresh cache, got:\n%s", compact, view)
+		}
+
+		status.Update(LicenseCheckedMsg{Result: stale})
+		if view := status.View(); !strings.Contains(view, staleNote) {
+			t.Errorf("compact=%v: expected the amber stale indicator, got:\n%s", compact, view)
+		}
+
+		online := manyFeaturesResult(1)
+		online.Source = tuish.LicenseSourceOnline
+		status.Update(LicenseCheckedMsg{Result: online})
+		if view := status.View(); strings.Contains(view, "(offline") {
+			t.Errorf("compact=%v: expected no offline indicator for an online result, got:\n%s", compact, view)
+		}
+	}
+}
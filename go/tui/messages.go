@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"time"
 
 	tuish "github.com/tuishdotdev/tuish/go"
@@ -18,6 +19,12 @@ type LicenseRefreshedMsg struct {
 	Error  error
 }
 
+// LicenseRevokedMsg is sent when a refresh finds that a previously valid
+// license has been revoked server-side.
+type LicenseRevokedMsg struct {
+	Result *tuish.LicenseCheckResult
+}
+
 // LicenseClearedMsg is sent when the license is cleared.
 type LicenseClearedMsg struct {
 	Error error
@@ -28,6 +35,12 @@ type LicenseStoredMsg struct {
 	Error error
 }
 
+// LicenseReboundMsg is sent when rebinding the license to this machine
+// finishes.
+type LicenseReboundMsg struct {
+	Error error
+}
+
 // CheckoutSessionCreatedMsg is sent when a checkout session is created.
 type CheckoutSessionCreatedMsg struct {
 	Session *tuish.CheckoutSessionResult
@@ -40,6 +53,21 @@ type CheckoutStatusMsg struct {
 	License   *tuish.LicenseDetails
 	Error     error
 	Completed bool
+
+	// AmountPaid, Currency and ReceiptURL describe the payment once the
+	// checkout is complete
+	AmountPaid int
+	Currency   string
+	ReceiptURL string
+
+	// poll identifies the PurchaseFlow poll chain that produced the message
+	poll int
+}
+
+// PurchasePriceMsg is sent when the price summary for a purchase is fetched.
+type PurchasePriceMsg struct {
+	Info  *tuish.PurchaseInitResult
+	Error error
 }
 
 // CheckoutTimeoutMsg is sent when checkout polling times out.
@@ -51,6 +79,9 @@ type CheckoutCancelledMsg struct{}
 // SpinnerTickMsg is sent to animate the spinner.
 type SpinnerTickMsg struct {
 	Time time.Time
+
+	// ID is the spinner the tick is for; zero ticks every spinner.
+	ID int64
 }
 
 // ElapsedTickMsg is sent to update elapsed time display.
@@ -123,20 +154,44 @@ const (
 	KeyC         = "c"
 	KeyY         = "y"
 	KeyN         = "n"
+	KeyP         = "p"
+
+	// Vim-style navigation aliases for the arrow keys
+	KeyJ = "j"
+	KeyK = "k"
+	KeyH = "h"
+	KeyL = "l"
 )
 
+// vimKeys maps vim-style navigation keys to the arrow keys they stand for.
+var vimKeys = map[string]string{
+	KeyJ: KeyDown,
+	KeyK: KeyUp,
+	KeyH: KeyLeft,
+	KeyL: KeyRight,
+}
+
+// navigationKey returns the arrow key a vim-style key stands for, or key
+// unchanged if it isn't one or vim keys are disabled.
+func navigationKey(key string, disableVimKeys bool) string {
+	if arrow, ok := vimKeys[key]; ok && !disableVimKeys {
+		return arrow
+	}
+	return key
+}
+
 // Command creators for common operations
 
 // DoLicenseCheck returns a tea.Cmd that checks the license.
-func DoLicenseCheck(sdk *tuish.SDK) func() LicenseCheckedMsg {
+func DoLicenseCheck(sdk LicenseChecker) func() LicenseCheckedMsg {
 	return func() LicenseCheckedMsg {
-		result, err := sdk.CheckLicense(nil)
+		result, err := sdk.CheckLicense(context.Background())
 		return LicenseCheckedMsg{Result: result, Error: err}
 	}
 }
 
 // DoCreateCheckout returns a tea.Cmd that creates a checkout session.
-func DoCreateCheckout(sdk *tuish.SDK, email string) func() CheckoutSessionCreatedMsg {
+func DoCreateCheckout(sdk LicenseChecker, email string) func() CheckoutSessionCreatedMsg {
 	return func() CheckoutSessionCreatedMsg {
 		session, err := sdk.PurchaseInBrowser(nil, email)
 		return CheckoutSessionCreatedMsg{Session: session, Error: err}
@@ -144,7 +199,7 @@ func DoCreateCheckout(sdk *tuish.SDK, email string) func() CheckoutSessionCreate
 }
 
 // DoStoreLicense returns a tea.Cmd that stores a license key.
-func DoStoreLicense(sdk *tuish.SDK, licenseKey string) func() LicenseStoredMsg {
+func DoStoreLicense(sdk LicenseChecker, licenseKey string) func() LicenseStoredMsg {
 	return func() LicenseStoredMsg {
 		err := sdk.StoreLicense(licenseKey)
 		return LicenseStoredMsg{Error: err}
@@ -152,7 +207,7 @@ func DoStoreLicense(sdk *tuish.SDK, licenseKey string) func() LicenseStoredMsg {
 }
 
 // DoClearLicense returns a tea.Cmd that clears the license.
-func DoClearLicense(sdk *tuish.SDK) func() LicenseClearedMsg {
+func DoClearLicense(sdk LicenseChecker) func() LicenseClearedMsg {
 	return func() LicenseClearedMsg {
 		err := sdk.ClearLicense()
 		return LicenseClearedMsg{Error: err}
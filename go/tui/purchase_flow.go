@@ -2,7 +2,11 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"os"
 	"strings"
 	"time"
 
@@ -31,22 +35,131 @@ type PurchaseFlowConfig struct {
 	// ShowQRCode enables QR code display (default: true).
 	ShowQRCode bool
 
+	// QRCellStyle selects how the checkout QR code is drawn. Use
+	// QRCellFullBlock if the default half-blocks don't scan in your font.
+	QRCellStyle QRCellStyle
+
+	// Terminal controls plain-text rendering. When headless (by default,
+	// when stdout isn't a terminal) the flow prints the checkout URL without
+	// a QR code or animation.
+	Terminal TerminalMode
+
 	// PollInterval is the checkout polling interval (default: 2s).
 	PollInterval time.Duration
 
+	// MaxPollInterval enables backoff: while the checkout status stays the
+	// same, the interval doubles from PollInterval up to this ceiling, and
+	// any status change resets it. 0 polls every PollInterval.
+	MaxPollInterval time.Duration
+
+	// PollJitter randomizes each poll delay by up to this fraction, e.g. 0.2
+	// for ±20%, so many clients waiting at once don't poll in step.
+	PollJitter float64
+
 	// Timeout is the checkout timeout (default: 10m).
 	Timeout time.Duration
 
+	// Inline drops the outer boxes and surrounding newlines so the flow can
+	// be composed into a parent layout with lipgloss.JoinVertical.
+	Inline bool
+
+	// ShowPrice fetches the product price and shows it while waiting for
+	// payment. Requires an identity token on the SDK client; skipped otherwise.
+	ShowPrice bool
+
+	// ProductName is shown at the top of the success screen. Defaults to the
+	// product name on the purchased license.
+	ProductName string
+
+	// SupportEmail, if set, is shown on the success and error screens.
+	SupportEmail string
+
+	// Messages overrides the flow's copy; empty fields keep the defaults.
+	Messages PurchaseFlowMessages
+
+	// Bell rings the terminal bell when the purchase succeeds or fails, in
+	// case the user tabbed away to pay. It is never rung when headless.
+	Bell bool
+
+	// Notify, if set, is called when the purchase succeeds or fails, e.g. to
+	// show a desktop notification with a library such as beeep. It runs in
+	// a command, so it may block.
+	Notify NotifyFunc
+
 	// OnComplete is called when purchase completes.
 	OnComplete func(*tuish.LicenseDetails)
 
 	// OnCancel is called when user cancels.
 	OnCancel func()
 
+	// OnStepChange is called once for every step transition, e.g. to record
+	// where users abandon checkout.
+	OnStepChange func(from, to PurchaseFlowStep)
+
 	// Styles allows custom styling.
 	Styles *Styles
 }
 
+// NotifyFunc shows a notification with a title and body.
+type NotifyFunc func(title, body string)
+
+// bellOutput is where the terminal bell is written.
+var bellOutput io.Writer = os.Stdout
+
+// PurchaseFlowMessages holds the lines of copy PurchaseFlow shows, so they
+// can be rebranded or localized.
+type PurchaseFlowMessages struct {
+	// WaitingTitle heads the waiting screen.
+	WaitingTitle string
+
+	// ScanStep, PayStep and ReturnStep are the numbered checkout instructions.
+	ScanStep   string
+	PayStep    string
+	ReturnStep string
+
+	// Waiting is the status line while polling for payment.
+	Waiting string
+
+	// SuccessTitle heads the success screen.
+	SuccessTitle string
+
+	// ThankYou closes the success screen.
+	ThankYou string
+}
+
+// DefaultPurchaseFlowMessages returns the default copy from the current
+// catalog.
+func DefaultPurchaseFlowMessages() PurchaseFlowMessages {
+	return PurchaseFlowMessages{
+		WaitingTitle: tr("purchase.waiting_title"),
+		ScanStep:     tr("purchase.scan_step"),
+		PayStep:      tr("purchase.pay_step"),
+		ReturnStep:   tr("purchase.return_step"),
+		Waiting:      tr("purchase.waiting"),
+		SuccessTitle: tr("purchase.success_title"),
+		ThankYou:     tr("purchase.thank_you"),
+	}
+}
+
+// withDefaults fills empty messages from DefaultPurchaseFlowMessages.
+func (m PurchaseFlowMessages) withDefaults() PurchaseFlowMessages {
+	defaults := DefaultPurchaseFlowMessages()
+	for _, field := range [][2]*string{
+		{&m.WaitingTitle, &defaults.WaitingTitle},
+		{&m.ScanStep, &defaults.ScanStep},
+		{&m.PayStep, &defaults.PayStep},
+		{&m.ReturnStep, &defaults.ReturnStep},
+		{&m.Waiting, &defaults.Waiting},
+		{&m.SuccessTitle, &defaults.SuccessTitle},
+		{&m.ThankYou, &defaults.ThankYou},
+	} {
+		if *field[0] == "" {
+			*field[0] = *field[1]
+		}
+	}
+	return m
+}
+
 // DefaultPurchaseFlowConfig returns the default configuration.
 func DefaultPurchaseFlowConfig() PurchaseFlowConfig {
 	return PurchaseFlowConfig{
@@ -58,7 +171,7 @@ func DefaultPurchaseFlowConfig() PurchaseFlowConfig {
 
 // PurchaseFlow manages the complete purchase flow with QR code and polling.
 type PurchaseFlow struct {
-	sdk    *tuish.SDK
+	sdk    LicenseChecker
 	config PurchaseFlowConfig
 	styles Styles
 
@@ -66,11 +179,27 @@ type PurchaseFlow struct {
 	sessionID      string
 	checkoutURL    string
 	license        *tuish.LicenseDetails
+	payment        *CheckoutStatusMsg
 	err            error
 	retryable      bool
 	elapsedSeconds int
-	spinnerFrame   int
+	spinner        *Spinner
 	qrCode         *QRCode
+	price          *tuish.PurchaseInitResult
+	headless       bool
+
+	// width is the terminal width from the last WindowSizeMsg, kept for
+	// sizing a QR code created after it arrived
+	width int
+
+	// pollSeq identifies the current poll chain; it changes whenever a
+	// session starts or the flow stops waiting
+	pollSeq int
+
+	// pollDelay is the wait before the next poll, before jitter, and
+	// pollStatus the last status it was backed off for
+	pollDelay  time.Duration
+	pollStatus string
 
 	// For polling
 	ctx        context.Context
@@ -78,11 +207,18 @@ type PurchaseFlow struct {
 }
 
 // NewPurchaseFlow creates a new PurchaseFlow component.
-func NewPurchaseFlow(sdk *tuish.SDK, config ...PurchaseFlowConfig) *PurchaseFlow {
+func NewPurchaseFlow(sdk LicenseChecker, config ...PurchaseFlowConfig) *PurchaseFlow {
 	cfg := DefaultPurchaseFlowConfig()
 	if len(config) > 0 {
 		cfg = config[0]
 	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPurchaseFlowConfig().PollInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultPurchaseFlowConfig().Timeout
+	}
+	cfg.Messages = cfg.Messages.withDefaults()
 
 	styles := DefaultStyles()
 	if cfg.Styles != nil {
@@ -90,10 +226,12 @@ func NewPurchaseFlow(sdk *tuish.SDK, config ...PurchaseFlowConfig) *PurchaseFlow
 	}
 
 	return &PurchaseFlow{
-		sdk:    sdk,
-		config: cfg,
-		styles: styles,
-		step:   PurchaseStepIdle,
+		sdk:      sdk,
+		config:   cfg,
+		styles:   styles,
+		step:     PurchaseStepIdle,
+		spinner:  NewSpinner(SpinnerConfig{Styles: &styles}),
+		headless: cfg.Terminal.headless(),
 	}
 }
 
@@ -106,87 +244,115 @@ func (m *PurchaseFlow) Init() tea.Cmd {
 func (m *PurchaseFlow) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case CheckoutSessionCreatedMsg:
+		// A session created after Cancel (or any other step change) is stale
+		if m.step != PurchaseStepCreating {
+			return m, nil
+		}
 		if msg.Error != nil {
-			m.step = PurchaseStepError
+			m.setStep(PurchaseStepError)
 			m.err = msg.Error
-			m.retryable = true
+			m.retryable = isRetryableError(msg.Error)
 			return m, nil
 		}
 
-		m.step = PurchaseStepWaiting
+		m.setStep(PurchaseStepWaiting)
 		m.sessionID = msg.Session.SessionID
 		m.checkoutURL = msg.Session.CheckoutURL
+		m.pollSeq++
+		m.pollDelay = m.config.PollInterval
+		m.pollStatus = ""
 
 		// Create QR code
 		m.qrCode = NewQRCode(m.checkoutURL, QRCodeConfig{
-			URLOnly: !m.config.ShowQRCode,
+			URLOnly:   !m.config.ShowQRCode,
+			CellStyle: m.config.QRCellStyle,
+			Terminal:  m.config.Terminal,
 		})
+		m.qrCode.width = m.width
 
-		// Start polling and timer
+		// Start polling and timer; headless output isn't animated
+		if m.headless {
+			return m, tea.Batch(m.pollCheckout(), m.tickElapsed())
+		}
 		return m, tea.Batch(
 			m.qrCode.Init(),
 			m.pollCheckout(),
-			m.tickSpinner(),
+			m.spinner.Tick(),
 			m.tickElapsed(),
 		)
 
 	case CheckoutStatusMsg:
+		// Only the current poll chain may move the flow on, so a reply that
+		// lands after Cancel, or for the session before a Retry, is dropped
+		if m.step != PurchaseStepWaiting || msg.poll != m.pollSeq {
+			return m, nil
+		}
+
 		if msg.Completed {
 			if msg.License != nil {
-				m.step = PurchaseStepSuccess
+				m.setStep(PurchaseStepSuccess)
 				m.license = msg.License
+				if msg.AmountPaid > 0 || msg.ReceiptURL != "" {
+					payment := msg
+					m.payment = &payment
+				}
 				if m.config.OnComplete != nil {
 					m.config.OnComplete(msg.License)
 				}
-				return m, nil
+				return m, m.announce()
 			}
 			// Timeout or expired
-			m.step = PurchaseStepError
+			m.setStep(PurchaseStepError)
 			m.err = fmt.Errorf("checkout session expired")
 			m.retryable = true
-			return m, nil
+			return m, m.announce()
 		}
 
-		if msg.Error != nil {
-			// Continue polling on error
-			return m, tea.Tick(m.config.PollInterval, func(t time.Time) tea.Msg {
-				return m.doPoll()
-			})
-		}
+		// Continue polling, errors included
+		m.backOff(msg)
+		return m, m.pollCheckout()
 
-		// Continue polling
-		return m, tea.Tick(m.config.PollInterval, func(t time.Time) tea.Msg {
-			return m.doPoll()
-		})
+	case PurchasePriceMsg:
+		// The price summary is optional, so errors are ignored
+		if msg.Error == nil && msg.Info != nil {
+			m.price = msg.Info
+		}
 
 	case SpinnerTickMsg:
-		if m.step == PurchaseStepWaiting {
-			m.spinnerFrame = (m.spinnerFrame + 1) % len(SpinnerFrames)
-			return m, m.tickSpinner()
+		if m.step == PurchaseStepWaiting && !m.headless {
+			_, cmd := m.spinner.Update(msg)
+			return m, cmd
 		}
 
 	case ElapsedTickMsg:
 		if m.step == PurchaseStepWaiting {
 			m.elapsedSeconds++
 			if time.Duration(m.elapsedSeconds)*time.Second >= m.config.Timeout {
-				m.step = PurchaseStepError
+				m.setStep(PurchaseStepError)
 				m.err = fmt.Errorf("checkout timed out")
 				m.retryable = true
-				return m, nil
+				return m, m.announce()
 			}
 			return m, m.tickElapsed()
 		}
 
-	case QRGeneratedMsg:
+	case QRGeneratedMsg, tea.WindowSizeMsg:
+		if size, ok := msg.(tea.WindowSizeMsg); ok {
+			m.width = size.Width
+		}
 		if m.qrCode != nil {
-			m.qrCode.Update(msg)
+			model, cmd := m.qrCode.Update(msg)
+			if qr, ok := model.(*QRCode); ok {
+				m.qrCode = qr
+			}
+			return m, cmd
 		}
 
 	case tea.KeyMsg:
 		switch msg.String() {
 		case KeyEscape, KeyQ:
 			if m.step == PurchaseStepWaiting || m.step == PurchaseStepCreating {
-				return m, m.cancel()
+				return m, m.Cancel()
 			}
 		case KeyR:
 			if (m.step == PurchaseStepError && m.retryable) || m.step == PurchaseStepCancelled {
@@ -195,8 +361,9 @@ func (m *PurchaseFlow) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case CheckoutCancelledMsg:
-		m.step = PurchaseStepCancelled
-		if m.config.OnCancel != nil {
+		// Cancel has already stopped the flow; don't undo a retry started
+		// before this message arrived
+		if m.step == PurchaseStepCancelled && m.config.OnCancel != nil {
 			m.config.OnCancel()
 		}
 	}
@@ -204,8 +371,57 @@ func (m *PurchaseFlow) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// announce rings the bell and calls Notify for the purchase's outcome, as
+// configured.
+func (m *PurchaseFlow) announce() tea.Cmd {
+	bell := m.config.Bell && !m.headless
+	notify := m.config.Notify
+	if !bell && notify == nil {
+		return nil
+	}
+
+	title, body := tr("purchase.failed_title"), tr("purchase.unexpected")
+	if m.step == PurchaseStepSuccess {
+		title, body = m.config.Messages.SuccessTitle, m.config.Messages.ThankYou
+	} else if m.err != nil {
+		body = m.err.Error()
+	}
+
+	return func() tea.Msg {
+		if bell {
+			io.WriteString(bellOutput, "\a")
+		}
+		if notify != nil {
+			notify(title, body)
+		}
+		return nil
+	}
+}
+
+// setStep moves the flow to step, notifying OnStepChange if it changed.
+func (m *PurchaseFlow) setStep(step PurchaseFlowStep) {
+	if step == m.step {
+		return
+	}
+	from := m.step
+	m.step = step
+	if from == PurchaseStepWaiting {
+		m.stopPolling()
+	}
+	if m.config.OnStepChange != nil {
+		m.config.OnStepChange(from, step)
+	}
+}
+
 // View renders the PurchaseFlow component.
 func (m *PurchaseFlow) View() string {
+	if m.config.Inline {
+		return inlineBlock(m.render())
+	}
+	return m.render()
+}
+
+func (m *PurchaseFlow) render() string {
 	switch m.step {
 	case PurchaseStepIdle:
 		return m.renderIdle()
@@ -224,36 +440,53 @@ func (m *PurchaseFlow) View() string {
 	}
 }
 
+// box wraps content in an outer box style, unless rendering inline.
+func (m *PurchaseFlow) box(style lipgloss.Style, content string) string {
+	if m.config.Inline {
+		return content
+	}
+	return style.Render(content)
+}
+
 func (m *PurchaseFlow) renderIdle() string {
-	return m.styles.BoxFocused.Render(
-		m.styles.Highlight.Render("Initializing..."),
+	return m.box(m.styles.BoxFocused,
+		m.styles.Highlight.Render(tr("purchase.initializing")),
 	)
 }
 
 func (m *PurchaseFlow) renderCreating() string {
+	if m.headless {
+		return tr("purchase.setting_up") + "\n"
+	}
+
 	content := lipgloss.JoinVertical(
 		lipgloss.Left,
-		m.styles.BoxHeader.Render("CHECKOUT"),
+		m.styles.BoxHeader.Render(tr("purchase.checkout")),
 		"",
-		SpinnerFrames[m.spinnerFrame]+" Setting up secure checkout...",
+		m.spinner.View()+" "+tr("purchase.setting_up"),
 	)
 
-	return m.styles.BoxFocused.Render(content)
+	return m.box(m.styles.BoxFocused, content)
 }
 
 func (m *PurchaseFlow) renderWaiting() string {
+	if m.headless {
+		return m.renderWaitingPlain()
+	}
+
 	var sb strings.Builder
 
 	// Header
-	header := m.styles.BannerInfo.Render(CreditCard + " COMPLETE YOUR PURCHASE")
+	msgs := m.config.Messages
+	header := m.styles.BannerInfo.Render(CreditCard + " " + msgs.WaitingTitle)
 	sb.WriteString(header)
 	sb.WriteString("\n\n")
 
 	// Instructions
 	instructions := []string{
-		CircleNumber1 + " Scan the QR code with your phone",
-		CircleNumber2 + " Complete payment in your browser",
-		CircleNumber3 + " Return here - we'll detect it automatically",
+		CircleNumber1 + " " + msgs.ScanStep,
+		CircleNumber2 + " " + msgs.PayStep,
+		CircleNumber3 + " " + msgs.ReturnStep,
 	}
 	for _, inst := range instructions {
 		sb.WriteString(m.styles.Body.Render(inst))
@@ -261,6 +494,12 @@ func (m *PurchaseFlow) renderWaiting() string {
 	}
 	sb.WriteString("\n")
 
+	// Price summary
+	if m.price != nil {
+		sb.WriteString(m.renderPrice())
+		sb.WriteString("\n\n")
+	}
+
 	// QR Code
 	if m.qrCode != nil {
 		qrBox := m.styles.Box.Render(m.qrCode.View())
@@ -269,14 +508,14 @@ func (m *PurchaseFlow) renderWaiting() string {
 	}
 
 	// Status bar
-	spinner := SpinnerFrames[m.spinnerFrame]
+	spinner := m.spinner.View()
 	elapsed := m.formatTime(m.elapsedSeconds)
 	progress := float64(m.elapsedSeconds%30) / 30.0
 
 	statusLine := lipgloss.JoinHorizontal(
 		lipgloss.Top,
-		spinner+" Waiting for payment ",
-		m.styles.Muted.Render(BulletPoint+" "),
+		spinner+" "+msgs.Waiting+" ",
+		m.styles.Muted.Render(m.styles.glyphs().Bullet+" "),
 		m.styles.Highlight.Render(elapsed),
 	)
 	sb.WriteString(statusLine)
@@ -288,33 +527,72 @@ func (m *PurchaseFlow) renderWaiting() string {
 	sb.WriteString("\n\n")
 
 	// Controls
-	controls := RenderKeyHint("Esc", "Cancel", m.styles)
+	controls := RenderKeyHint("Esc", tr("purchase.cancel"), m.styles)
 	sb.WriteString(controls)
 
 	return sb.String()
 }
 
+// renderWaitingPlain is the waiting screen for non-interactive output.
+func (m *PurchaseFlow) renderWaitingPlain() string {
+	var sb strings.Builder
+	sb.WriteString(tr("purchase.open_url", m.checkoutURL) + "\n")
+	if m.price != nil {
+		price := m.formatPrice()
+		if m.price.ProductName != "" {
+			price = m.price.ProductName + " - " + price
+		}
+		sb.WriteString(price + "\n")
+	}
+	sb.WriteString(m.config.Messages.Waiting + "...\n")
+	return sb.String()
+}
+
+// formatPrice returns the price with its currency code, e.g. "$19.99 USD".
+func (m *PurchaseFlow) formatPrice() string {
+	price := tuish.FormatMoney(m.price.Amount, m.price.Currency)
+	if code := strings.ToUpper(m.price.Currency); !strings.HasPrefix(price, code) {
+		price += " " + code
+	}
+	return price
+}
+
+func (m *PurchaseFlow) renderPrice() string {
+	price := m.formatPrice()
+	if m.price.ProductName == "" {
+		return m.styles.Highlight.Render(price)
+	}
+	return m.styles.Bold.Render(m.price.ProductName) +
+		m.styles.Muted.Render(" \u2014 ") +
+		m.styles.Highlight.Render(price)
+}
+
 func (m *PurchaseFlow) renderSuccess() string {
 	var sb strings.Builder
 
 	// Success banner
-	banner := m.styles.BannerSuccess.Render(CheckMark + " PURCHASE SUCCESSFUL!")
+	msgs := m.config.Messages
+	banner := m.styles.BannerSuccess.Render(m.styles.glyphs().Check + " " + msgs.SuccessTitle)
 	sb.WriteString(banner)
 	sb.WriteString("\n\n")
 
+	productName := m.config.ProductName
+	if productName == "" && m.license != nil {
+		productName = m.license.ProductName
+	}
+	if productName != "" {
+		sb.WriteString(m.styles.Title.Render(productName))
+		sb.WriteString("\n\n")
+	}
+
 	// License details box
 	var details []string
-	details = append(details, m.styles.Bold.Render("License Activated"))
+	details = append(details, m.styles.Bold.Render(tr("purchase.activated")))
 
 	if m.license != nil {
-		if m.license.ProductName != "" {
-			details = append(details, "")
-			details = append(details, m.styles.Muted.Render("Product: ")+m.styles.Body.Render(m.license.ProductName))
-		}
-
 		if len(m.license.Features) > 0 {
 			details = append(details, "")
-			details = append(details, m.styles.Muted.Render("Features unlocked:"))
+			details = append(details, m.styles.Muted.Render(tr("purchase.features")))
 			for _, f := range m.license.Features {
 				details = append(details, m.styles.CheckMark.Render("")+m.styles.Body.Render(f))
 			}
@@ -322,19 +600,31 @@ func (m *PurchaseFlow) renderSuccess() string {
 
 		if m.license.ExpiresAt != nil {
 			details = append(details, "")
-			expiry := time.UnixMilli(*m.license.ExpiresAt).Format("Jan 2, 2006")
-			details = append(details, m.styles.Muted.Render("Valid until: ")+m.styles.Body.Render(expiry))
+			expiry := time.UnixMilli(*m.license.ExpiresAt).Format(tr("date.format"))
+			details = append(details, m.styles.Muted.Render(tr("purchase.valid_until"))+m.styles.Body.Render(expiry))
+		}
+	}
+
+	if m.payment != nil {
+		details = append(details, "")
+		if m.payment.AmountPaid > 0 {
+			paid := tuish.FormatMoney(m.payment.AmountPaid, m.payment.Currency)
+			details = append(details, m.styles.Muted.Render(tr("purchase.paid"))+m.styles.Body.Render(paid))
+		}
+		if m.payment.ReceiptURL != "" {
+			details = append(details, m.styles.Muted.Render(tr("purchase.receipt"))+m.styles.Link.Render(m.payment.ReceiptURL))
 		}
 	}
 
-	detailsBox := m.styles.BoxSuccess.Render(
+	detailsBox := m.box(m.styles.BoxSuccess,
 		lipgloss.JoinVertical(lipgloss.Left, details...),
 	)
 	sb.WriteString(detailsBox)
 	sb.WriteString("\n\n")
 
 	// Thank you message
-	sb.WriteString(m.styles.Success.Render("Thank you for your purchase! " + Celebration))
+	sb.WriteString(m.styles.Success.Render(msgs.ThankYou + " " + Celebration))
+	sb.WriteString(m.renderSupport())
 
 	return sb.String()
 }
@@ -343,48 +633,61 @@ func (m *PurchaseFlow) renderError() string {
 	var sb strings.Builder
 
 	// Error banner
-	banner := m.styles.BannerError.Render(CrossMark + " PURCHASE FAILED")
+	banner := m.styles.BannerError.Render(m.styles.glyphs().Cross + " " + tr("purchase.failed_title"))
 	sb.WriteString(banner)
 	sb.WriteString("\n\n")
 
 	// Error details
-	errMsg := "An unexpected error occurred"
+	errMsg := tr("purchase.unexpected")
 	if m.err != nil {
 		errMsg = m.err.Error()
 	}
 
-	errBox := m.styles.BoxError.Render(
-		m.styles.Bold.Render("Error Details:") + "\n\n" +
+	errBox := m.box(m.styles.BoxError,
+		m.styles.Bold.Render(tr("purchase.error_details"))+"\n\n"+
 			m.styles.Body.Render(errMsg),
 	)
 	sb.WriteString(errBox)
 	sb.WriteString("\n\n")
 
+	if support := m.renderSupport(); support != "" {
+		sb.WriteString(strings.TrimPrefix(support, "\n"))
+		sb.WriteString("\n\n")
+	}
+
 	// Controls
 	var hints [][2]string
 	if m.retryable {
-		hints = append(hints, [2]string{"R", "Retry"})
+		hints = append(hints, [2]string{"R", tr("purchase.retry")})
 	}
-	hints = append(hints, [2]string{"Q", "Exit"})
+	hints = append(hints, [2]string{"Q", tr("hint.exit")})
 	sb.WriteString(RenderKeyHints(hints, m.styles))
 
 	return sb.String()
 }
 
+// renderSupport returns the support contact line, if SupportEmail is set.
+func (m *PurchaseFlow) renderSupport() string {
+	if m.config.SupportEmail == "" {
+		return ""
+	}
+	return "\n" + m.styles.Muted.Render(tr("purchase.support")) + m.styles.Link.Render(m.config.SupportEmail)
+}
+
 func (m *PurchaseFlow) renderCancelled() string {
 	var sb strings.Builder
 
 	// Warning box
-	box := m.styles.BoxWarning.Render(
-		m.styles.Warning.Render(WarningSign+" Purchase Cancelled"),
+	box := m.box(m.styles.BoxWarning,
+		m.styles.Warning.Render(m.styles.glyphs().Warning+" "+tr("purchase.cancelled_title")),
 	)
 	sb.WriteString(box)
 	sb.WriteString("\n\n")
 
 	// Controls
 	hints := [][2]string{
-		{"R", "Try Again"},
-		{"Q", "Exit"},
+		{"R", tr("purchase.try_again")},
+		{"Q", tr("hint.exit")},
 	}
 	sb.WriteString(RenderKeyHints(hints, m.styles))
 
@@ -398,75 +701,151 @@ func (m *PurchaseFlow) formatTime(seconds int) string {
 }
 
 func (m *PurchaseFlow) start() tea.Cmd {
-	m.step = PurchaseStepCreating
+	m.setStep(PurchaseStepCreating)
 	m.elapsedSeconds = 0
-	m.spinnerFrame = 0
+	m.spinner.Reset()
 	m.err = nil
 	m.retryable = false
 
 	// Create cancellable context
 	m.ctx, m.cancelFunc = context.WithTimeout(context.Background(), m.config.Timeout)
 
-	return func() tea.Msg {
+	createCheckout := func() tea.Msg {
 		session, err := m.sdk.PurchaseInBrowser(m.ctx, m.config.Email)
 		return CheckoutSessionCreatedMsg{Session: session, Error: err}
 	}
+
+	if !m.config.ShowPrice || m.price != nil || !m.sdk.HasIdentityToken() {
+		return createCheckout
+	}
+
+	return tea.Batch(createCheckout, m.fetchPrice)
 }
 
-func (m *PurchaseFlow) cancel() tea.Cmd {
-	if m.cancelFunc != nil {
-		m.cancelFunc()
+func (m *PurchaseFlow) fetchPrice() tea.Msg {
+	info, err := m.sdk.InitTerminalPurchase(m.ctx)
+	return PurchasePriceMsg{Info: info, Error: err}
+}
+
+// Cancel stops the purchase: any request in flight is cancelled and the
+// poll chain, spinner and elapsed timer stop straight away. It returns a
+// command that reports CheckoutCancelledMsg, which calls OnCancel. Hosts
+// that tear the flow down should call it so nothing outlives the flow; the
+// command may be dropped if the flow is being discarded.
+//
+// Cancel is idempotent, and a no-op returning nil unless a checkout is
+// being created or awaited.
+func (m *PurchaseFlow) Cancel() tea.Cmd {
+	if m.step != PurchaseStepCreating && m.step != PurchaseStepWaiting {
+		return nil
 	}
+	m.stopPolling()
+	m.setStep(PurchaseStepCancelled)
 	return func() tea.Msg {
 		return CheckoutCancelledMsg{}
 	}
 }
 
+// pollCheckout schedules the next status check. Each result reschedules at
+// most one more, so there is a single poll chain per session.
 func (m *PurchaseFlow) pollCheckout() tea.Cmd {
-	return tea.Tick(m.config.PollInterval, func(t time.Time) tea.Msg {
-		return m.doPoll()
+	ctx, sessionID, seq := m.ctx, m.sessionID, m.pollSeq
+	delay := m.pollDelay
+	if delay <= 0 {
+		delay = m.config.PollInterval
+	}
+	return tea.Tick(jitter(delay, m.config.PollJitter), func(t time.Time) tea.Msg {
+		return m.doPoll(ctx, sessionID, seq)
 	})
 }
 
-func (m *PurchaseFlow) doPoll() tea.Msg {
-	if m.ctx == nil || m.sessionID == "" {
-		return CheckoutStatusMsg{Error: fmt.Errorf("no active session")}
+// backOff updates the poll delay after a poll: a new status resets it to
+// PollInterval, and an unchanged status or an error doubles it up to
+// MaxPollInterval.
+func (m *PurchaseFlow) backOff(msg CheckoutStatusMsg) {
+	if msg.Error == nil && msg.Status != m.pollStatus {
+		m.pollStatus = msg.Status
+		m.pollDelay = m.config.PollInterval
+		return
+	}
+	if m.config.MaxPollInterval > m.config.PollInterval {
+		m.pollDelay = min(m.pollDelay*2, m.config.MaxPollInterval)
+	}
+}
+
+// jitter randomizes d by up to ±fraction (clamped to [0, 1]).
+func jitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return d
 	}
+	fraction = min(fraction, 1)
+	return d + time.Duration((rand.Float64()*2-1)*fraction*float64(d))
+}
+
+// stopPolling ends the current poll chain, cancelling any request in flight.
+func (m *PurchaseFlow) stopPolling() {
+	m.pollSeq++
+	if m.cancelFunc != nil {
+		m.cancelFunc()
+	}
+}
 
-	status, err := m.sdk.GetClient().GetCheckoutStatus(m.ctx, m.sessionID)
+func (m *PurchaseFlow) doPoll(ctx context.Context, sessionID string, seq int) tea.Msg {
+	if ctx == nil || sessionID == "" {
+		return CheckoutStatusMsg{Error: fmt.Errorf("no active session"), poll: seq}
+	}
+
+	// The flow stopped waiting before this poll fired
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	status, err := m.sdk.GetCheckoutStatus(ctx, sessionID)
 	if err != nil {
-		return CheckoutStatusMsg{Error: err}
+		return CheckoutStatusMsg{Error: err, poll: seq}
 	}
 
 	switch status.Status {
 	case "complete":
 		return CheckoutStatusMsg{
-			Status:    status.Status,
-			License:   status.License,
-			Completed: true,
+			Status:     status.Status,
+			License:    status.License,
+			Completed:  true,
+			AmountPaid: status.AmountPaid,
+			Currency:   status.Currency,
+			ReceiptURL: status.ReceiptURL,
+			poll:       seq,
 		}
 	case "expired":
 		return CheckoutStatusMsg{
 			Status:    status.Status,
 			Completed: true,
+			poll:      seq,
 		}
 	default:
-		return CheckoutStatusMsg{Status: status.Status}
+		return CheckoutStatusMsg{Status: status.Status, poll: seq}
 	}
 }
 
-func (m *PurchaseFlow) tickSpinner() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
-		return SpinnerTickMsg{Time: t}
-	})
-}
-
 func (m *PurchaseFlow) tickElapsed() tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
 		return ElapsedTickMsg{Elapsed: time.Duration(m.elapsedSeconds+1) * time.Second}
 	})
 }
 
+// isRetryableError reports whether a failed checkout is worth retrying.
+// Client errors from the API (4xx other than 429) are permanent; network
+// failures, timeouts, rate limits, and server errors may succeed on retry.
+func isRetryableError(err error) bool {
+	var apiErr *tuish.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != 429 {
+			return false
+		}
+	}
+	return true
+}
+
 // Step returns the current step in the purchase flow.
 func (m *PurchaseFlow) Step() PurchaseFlowStep {
 	return m.step
@@ -477,6 +856,18 @@ func (m *PurchaseFlow) License() *tuish.LicenseDetails {
 	return m.license
 }
 
+// CheckoutURL returns the checkout URL of the current session, or "" before
+// one is created.
+func (m *PurchaseFlow) CheckoutURL() string {
+	return m.checkoutURL
+}
+
+// SessionID returns the ID of the current checkout session, or "" before
+// one is created.
+func (m *PurchaseFlow) SessionID() string {
+	return m.sessionID
+}
+
 // Error returns the current error (if any).
 func (m *PurchaseFlow) Error() error {
 	return m.err
@@ -0,0 +1,687 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	tuish "github.com/tuishdotdev/tuish/go"
+	"github.com/tuishdotdev/tuish/go/tui/tuishtest"
+)
+
+func TestPurchaseFlowPermanentErrorNotRetryable(t *testing.T) {
+	flow := NewPurchaseFlow(nil)
+
+	flow.setStep(PurchaseStepCreating)
+	flow.Update(CheckoutSessionCreatedMsg{Error: &tuish.APIError{
+		StatusCode: 400,
+		Code:       tuish.ErrCodeInvalidRequest,
+		Message:    "Product not found",
+	}})
+
+	if flow.Step() != PurchaseStepError {
+		t.Fatalf("expected error step, got %d", flow.Step())
+	}
+	if flow.retryable {
+		t.Error("expected 400 error to be non-retryable")
+	}
+	if strings.Contains(flow.View(), "Retry") {
+		t.Error("expected retry hint to be hidden for permanent errors")
+	}
+}
+
+func TestPurchaseFlowTransientErrorRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"network", errors.New("do request: connection refused")},
+		{"rate limited", &tuish.APIError{StatusCode: 429, Code: tuish.ErrCodeRateLimited}},
+		{"server error", &tuish.APIError{StatusCode: 503, Message: "unavailable"}},
+		{"wrapped server error", fmt.Errorf("checkout: %w", &tuish.APIError{StatusCode: 500})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := NewPurchaseFlow(nil)
+			flow.setStep(PurchaseStepCreating)
+			flow.Update(CheckoutSessionCreatedMsg{Error: tt.err})
+
+			if !flow.retryable {
+				t.Errorf("expected %v to be retryable", tt.err)
+			}
+			if !strings.Contains(flow.View(), "Retry") {
+				t.Error("expected retry hint to be shown")
+			}
+		})
+	}
+}
+
+func TestPurchaseFlowShowsPriceWhileWaiting(t *testing.T) {
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{ShowPrice: true, Terminal: TerminalInteractive})
+	flow.setStep(PurchaseStepCreating)
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_123",
+		CheckoutURL: "https://checkout.example.com/sess_123",
+	}})
+
+	if strings.Contains(flow.View(), "$19.99") {
+		t.Fatal("expected no price before it is fetched")
+	}
+
+	flow.Update(PurchasePriceMsg{Info: &tuish.PurchaseInitResult{
+		Amount:      1999,
+		Currency:    "usd",
+		ProductName: "Test Product",
+	}})
+
+	view := flow.View()
+	if !strings.Contains(view, "Test Product") || !strings.Contains(view, "$19.99 USD") {
+		t.Errorf("expected price summary in waiting view, got:\n%s", view)
+	}
+}
+
+func TestPurchaseFlowIgnoresPriceError(t *testing.T) {
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{ShowPrice: true, Terminal: TerminalInteractive})
+	flow.setStep(PurchaseStepCreating)
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_123",
+		CheckoutURL: "https://checkout.example.com/sess_123",
+	}})
+	flow.Update(PurchasePriceMsg{Error: &tuish.APIError{StatusCode: 401, Code: tuish.ErrCodeUnauthorized}})
+
+	if flow.Step() != PurchaseStepWaiting {
+		t.Errorf("expected price errors not to affect the flow, got step %d", flow.Step())
+	}
+	if flow.price != nil {
+		t.Error("expected no price after an error")
+	}
+}
+
+func TestPurchaseFlowInlineGolden(t *testing.T) {
+	for _, inline := range []bool{false, true} {
+		config := DefaultPurchaseFlowConfig()
+		config.Inline = inline
+
+		flow := NewPurchaseFlow(nil, config)
+		flow.setStep(PurchaseStepCreating)
+		flow.Update(CheckoutSessionCreatedMsg{Error: errors.New("card declined")})
+
+		name := "purchase_flow_error_boxed"
+		if inline {
+			name = "purchase_flow_error_inline"
+		}
+		view := flow.View()
+		assertGolden(t, name, view)
+
+		hasBorder := strings.ContainsAny(view, "┌┐└┘╭╮╰╯")
+		if inline == hasBorder {
+			t.Errorf("inline=%v but border present=%v", inline, hasBorder)
+		}
+		if inline && strings.HasSuffix(view, "\n") {
+			t.Error("expected inline view without trailing newline")
+		}
+	}
+}
+
+func TestPurchaseFlowSpinnerPerStyles(t *testing.T) {
+	ascii := ASCIIStyles()
+	asciiFlow := NewPurchaseFlow(nil, PurchaseFlowConfig{Styles: &ascii, Terminal: TerminalInteractive})
+	defaultFlow := NewPurchaseFlow(nil, PurchaseFlowConfig{Terminal: TerminalInteractive})
+
+	asciiFlow.step = PurchaseStepCreating
+	defaultFlow.step = PurchaseStepCreating
+
+	if view := asciiFlow.View(); !strings.Contains(view, "| Setting up") {
+		t.Errorf("expected ASCII spinner, got:\n%s", view)
+	}
+	if view := defaultFlow.View(); !strings.Contains(view, SpinnerFrames[0]+" Setting up") {
+		t.Errorf("expected default spinner, got:\n%s", view)
+	}
+}
+
+func TestPurchaseFlowOnStepChange(t *testing.T) {
+	type transition struct{ from, to PurchaseFlowStep }
+	var got []transition
+
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{
+		PollInterval: time.Second,
+		Timeout:      time.Minute,
+		OnStepChange: func(from, to PurchaseFlowStep) {
+			got = append(got, transition{from, to})
+		},
+	})
+
+	flow.Init()
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_1",
+		CheckoutURL: "https://example.com/checkout",
+	}})
+	// Ticks and pending polls don't change the step
+	flow.Update(SpinnerTickMsg{})
+	flow.Update(ElapsedTickMsg{})
+	flow.Update(CheckoutStatusMsg{poll: flow.pollSeq})
+	flow.Update(CheckoutStatusMsg{
+		Completed: true,
+		License:   &tuish.LicenseDetails{ID: "lic_1", Status: tuish.LicenseStatusActive},
+		poll:      flow.pollSeq,
+	})
+
+	want := []transition{
+		{PurchaseStepIdle, PurchaseStepCreating},
+		{PurchaseStepCreating, PurchaseStepWaiting},
+		{PurchaseStepWaiting, PurchaseStepSuccess},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d transitions %v, want %v", len(got), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("transition %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPurchaseFlowFetchesPriceOnlyWithIdentityToken(t *testing.T) {
+	fake := tuishtest.NewFakeSDK()
+	fake.Session = &tuish.CheckoutSessionResult{SessionID: "sess_1"}
+
+	flow := NewPurchaseFlow(fake, PurchaseFlowConfig{ShowPrice: true, Terminal: TerminalInteractive})
+	if _, ok := flow.Init()().(CheckoutSessionCreatedMsg); !ok {
+		t.Error("expected only the checkout to be created without an identity token")
+	}
+
+	fake.IdentityToken = true
+	flow = NewPurchaseFlow(fake, PurchaseFlowConfig{ShowPrice: true, Terminal: TerminalInteractive})
+	if _, ok := flow.Init()().(tea.BatchMsg); !ok {
+		t.Error("expected the price to be fetched alongside the checkout")
+	}
+}
+
+func TestPurchaseFlowHeadless(t *testing.T) {
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{
+		ShowQRCode: true,
+		ShowPrice:  true,
+		Terminal:   TerminalHeadless,
+	})
+
+	flow.setStep(PurchaseStepCreating)
+	if view := flow.View(); view != "Setting up secure checkout...\n" {
+		t.Errorf("unexpected creating view: %q", view)
+	}
+
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_123",
+		CheckoutURL: "https://checkout.example.com/sess_123",
+	}})
+	flow.Update(PurchasePriceMsg{Info: &tuish.PurchaseInitResult{
+		Amount:      1999,
+		Currency:    "usd",
+		ProductName: "Test Product",
+	}})
+	flow.Update(SpinnerTickMsg{})
+
+	view := flow.View()
+	want := "Open this URL to purchase: https://checkout.example.com/sess_123\n" +
+		"Test Product - $19.99 USD\n" +
+		"Waiting for payment...\n"
+	if view != want {
+		t.Errorf("headless waiting view = %q, want %q", view, want)
+	}
+	if strings.Contains(view, "\x1b") {
+		t.Error("expected no escape sequences in headless output")
+	}
+	for _, frame := range SpinnerFrames {
+		if strings.Contains(view, frame) {
+			t.Errorf("expected no spinner in headless output, found %q", frame)
+		}
+	}
+	if strings.ContainsAny(view, "▀▄█") {
+		t.Error("expected no QR code in headless output")
+	}
+	if flow.spinner.frame != 0 {
+		t.Error("expected spinner not to advance when headless")
+	}
+}
+
+func TestPurchaseFlowPollsOnceAtATime(t *testing.T) {
+	statuses := []string{"pending", "pending", "complete"}
+	var mu sync.Mutex
+	calls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/checkout/status/sess_123" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		status := statuses[min(calls, len(statuses)-1)]
+		calls++
+		mu.Unlock()
+
+		body := map[string]any{"status": status}
+		if status == "complete" {
+			body["license"] = map[string]any{"id": "lic_123", "status": "active"}
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	sdk, err := tuish.New(tuish.Config{
+		ProductID:  "prod_test",
+		PublicKey:  strings.Repeat("ab", 32),
+		APIBaseURL: server.URL,
+		Store:      tuish.NewMemoryStore(),
+	})
+	if err != nil {
+		t.Fatalf("create SDK: %v", err)
+	}
+
+	flow := NewPurchaseFlow(sdk, PurchaseFlowConfig{
+		PollInterval: time.Millisecond,
+		Timeout:      time.Minute,
+		Terminal:     TerminalHeadless,
+	})
+	flow.start()
+
+	// Run commands in the background and feed their messages back in,
+	// like a tea.Program would
+	msgs := make(chan tea.Msg, 16)
+	var run func(tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		go func() {
+			msg := cmd()
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, c := range batch {
+					run(c)
+				}
+				return
+			}
+			if msg != nil {
+				msgs <- msg
+			}
+		}()
+	}
+
+	flow.setStep(PurchaseStepCreating)
+	_, cmd := flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_123",
+		CheckoutURL: "https://checkout.example.com/sess_123",
+	}})
+	run(cmd)
+
+	// A duplicate status message must not start a second poll chain
+	_, cmd = flow.Update(CheckoutStatusMsg{Status: "pending"})
+	run(cmd)
+
+	deadline := time.After(5 * time.Second)
+	for flow.Step() == PurchaseStepWaiting {
+		select {
+		case msg := <-msgs:
+			if _, ok := msg.(ElapsedTickMsg); ok {
+				continue
+			}
+			_, cmd := flow.Update(msg)
+			run(cmd)
+		case <-deadline:
+			t.Fatalf("timed out waiting for checkout, step %d", flow.Step())
+		}
+	}
+
+	if flow.Step() != PurchaseStepSuccess {
+		t.Fatalf("expected success, got step %d", flow.Step())
+	}
+
+	// Give any stray polls time to fire
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != len(statuses) {
+		t.Errorf("expected %d status requests, got %d", len(statuses), calls)
+	}
+}
+
+func TestPurchaseFlowShowsGeneratedQRCode(t *testing.T) {
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{
+		ShowQRCode: true,
+		Terminal:   TerminalInteractive,
+	})
+	flow.setStep(PurchaseStepCreating)
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_123",
+		CheckoutURL: "https://checkout.example.com/sess_123",
+	}})
+
+	if strings.Contains(flow.View(), "▀") {
+		t.Fatal("expected no QR code before it is generated")
+	}
+
+	qr, err := generateQRMatrix("https://checkout.example.com/sess_123", QRCellHalfBlock)
+	if err != nil {
+		t.Fatalf("generate QR: %v", err)
+	}
+	flow.Update(QRGeneratedMsg{QRString: qr, CanFit: true})
+
+	view := flow.View()
+	firstRow := strings.Split(qr, "\n")[1]
+	if !strings.Contains(view, firstRow) {
+		t.Errorf("expected QR code in waiting view, got:\n%s", view)
+	}
+}
+
+func TestPurchaseFlowSuccessShowsReceipt(t *testing.T) {
+	flow := NewPurchaseFlow(nil)
+	flow.step = PurchaseStepWaiting
+
+	flow.Update(CheckoutStatusMsg{
+		Status:     "complete",
+		Completed:  true,
+		License:    &tuish.LicenseDetails{ID: "lic_test", ProductName: "Test App"},
+		AmountPaid: 1999,
+		Currency:   "usd",
+		ReceiptURL: "https://pay.example.com/receipts/rcpt_123",
+	})
+
+	view := flow.View()
+	if !strings.Contains(view, "Paid: $19.99") {
+		t.Errorf("expected amount paid, got:\n%s", view)
+	}
+	if !strings.Contains(view, "Receipt: https://pay.example.com/receipts/rcpt_123") {
+		t.Errorf("expected receipt link, got:\n%s", view)
+	}
+}
+
+func TestPurchaseFlowBell(t *testing.T) {
+	var out strings.Builder
+	bellOutput = &out
+	t.Cleanup(func() { bellOutput = os.Stdout })
+
+	tests := []struct {
+		name     string
+		bell     bool
+		terminal TerminalMode
+		want     string
+	}{
+		{"rings", true, TerminalInteractive, "\a"},
+		{"off by default", false, TerminalInteractive, ""},
+		{"suppressed when headless", true, TerminalHeadless, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			flow := NewPurchaseFlow(nil, PurchaseFlowConfig{Bell: tt.bell, Terminal: tt.terminal})
+			flow.step = PurchaseStepWaiting
+
+			_, cmd := flow.Update(CheckoutStatusMsg{Completed: true, License: &tuish.LicenseDetails{ID: "lic_test"}})
+			if cmd != nil {
+				cmd()
+			}
+			if out.String() != tt.want {
+				t.Errorf("expected %q written, got %q", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestPurchaseFlowNotify(t *testing.T) {
+	var titles, bodies []string
+	notify := func(title, body string) {
+		titles = append(titles, title)
+		bodies = append(bodies, body)
+	}
+
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{Notify: notify, Terminal: TerminalHeadless})
+	flow.step = PurchaseStepWaiting
+	_, cmd := flow.Update(CheckoutStatusMsg{Completed: true, License: &tuish.LicenseDetails{ID: "lic_test"}})
+	cmd()
+
+	flow = NewPurchaseFlow(nil, PurchaseFlowConfig{Notify: notify, Terminal: TerminalHeadless})
+	flow.step = PurchaseStepWaiting
+	_, cmd = flow.Update(CheckoutStatusMsg{Completed: true})
+	cmd()
+
+	want := []string{"PURCHASE SUCCESSFUL!", "PURCHASE FAILED"}
+	if fmt.Sprint(titles) != fmt.Sprint(want) {
+		t.Errorf("expected notifications %q, got %q", want, titles)
+	}
+	if len(bodies) == 2 && bodies[1] != "checkout session expired" {
+		t.Errorf("expected the failure reason in the body, got %q", bodies[1])
+	}
+}
+
+func TestPurchaseFlowCustomMessages(t *testing.T) {
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{
+		Terminal:     TerminalInteractive,
+		ProductName:  "Acme Pro",
+		SupportEmail: "help@acme.dev",
+		Messages: PurchaseFlowMessages{
+			WaitingTitle: "FINISH CHECKOUT",
+			ReturnStep:   "Come back when you're done",
+			ThankYou:     "Welcome to Acme Pro!",
+		},
+	})
+	flow.setStep(PurchaseStepCreating)
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_123",
+		CheckoutURL: "https://checkout.example.com/sess_123",
+	}})
+
+	waiting := flow.View()
+	for _, want := range []string{"FINISH CHECKOUT", "Come back when you're done", "Scan the QR code with your phone"} {
+		if !strings.Contains(waiting, want) {
+			t.Errorf("expected %q in waiting view, got:\n%s", want, waiting)
+		}
+	}
+	if strings.Contains(waiting, "COMPLETE YOUR PURCHASE") {
+		t.Error("expected the default title to be replaced")
+	}
+
+	flow.Update(CheckoutStatusMsg{
+		Status:    "complete",
+		Completed: true,
+		License:   &tuish.LicenseDetails{ID: "lic_test", ProductName: "acme"},
+		poll:      flow.pollSeq,
+	})
+
+	success := flow.View()
+	for _, want := range []string{"Acme Pro", "Welcome to Acme Pro!", "PURCHASE SUCCESSFUL!", "Need help? Contact help@acme.dev"} {
+		if !strings.Contains(success, want) {
+			t.Errorf("expected %q in success view, got:\n%s", want, success)
+		}
+	}
+	if strings.Contains(success, "Thank you for your purchase!") {
+		t.Error("expected the default thank-you line to be replaced")
+	}
+}
+
+func TestPurchaseFlowSessionAccessors(t *testing.T) {
+	flow := NewPurchaseFlow(nil)
+	if flow.CheckoutURL() != "" || flow.SessionID() != "" {
+		t.Fatal("expected no session before checkout is created")
+	}
+
+	flow.setStep(PurchaseStepCreating)
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_123",
+		CheckoutURL: "https://checkout.example.com/sess_123?plan=pro&seats=2",
+	}})
+
+	if got := flow.SessionID(); got != "sess_123" {
+		t.Errorf("expected session ID sess_123, got %q", got)
+	}
+	if got := flow.CheckoutURL(); got != "https://checkout.example.com/sess_123?plan=pro&seats=2" {
+		t.Errorf("expected checkout URL, got %q", got)
+	}
+}
+
+func TestPurchaseFlowCancel(t *testing.T) {
+	cancels := 0
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{
+		PollInterval: time.Second,
+		Timeout:      time.Minute,
+		Terminal:     TerminalInteractive,
+		OnCancel:     func() { cancels++ },
+	})
+	flow.Init()
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_1",
+		CheckoutURL: "https://example.com/checkout",
+	}})
+	if _, cmd := flow.Update(SpinnerTickMsg{}); cmd == nil {
+		t.Fatal("expected the spinner to tick while waiting")
+	}
+
+	cmd := flow.Cancel()
+	if cmd == nil {
+		t.Fatal("expected a cancel command")
+	}
+	if flow.Step() != PurchaseStepCancelled {
+		t.Errorf("expected the flow to stop straight away, got step %d", flow.Step())
+	}
+	if _, tick := flow.Update(SpinnerTickMsg{}); tick != nil {
+		t.Error("expected no spinner tick after Cancel")
+	}
+	if _, tick := flow.Update(ElapsedTickMsg{}); tick != nil {
+		t.Error("expected no elapsed tick after Cancel")
+	}
+
+	if again := flow.Cancel(); again != nil {
+		t.Error("expected a second Cancel to be a no-op")
+	}
+
+	flow.Update(cmd())
+	if cancels != 1 {
+		t.Errorf("expected OnCancel once, got %d", cancels)
+	}
+}
+
+func TestPurchaseFlowSizesQRCodeForStartupWidth(t *testing.T) {
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{ShowQRCode: true, Terminal: TerminalInteractive})
+	flow.Init()
+
+	// The size arrives before there is a QR code to pass it to
+	flow.Update(tea.WindowSizeMsg{Width: 20})
+	_, cmd := flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_1",
+		CheckoutURL: "https://example.com/checkout",
+	}})
+	if cmd == nil {
+		t.Fatal("expected commands once the session is created")
+	}
+	flow.Update(flow.qrCode.Init()())
+
+	if view := flow.View(); strings.ContainsAny(view, "▀▄█") {
+		t.Errorf("expected URL only in a 20 column terminal, got:\n%s", view)
+	}
+}
+
+func TestPurchaseFlowIgnoresStaleCompletion(t *testing.T) {
+	completions := 0
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{
+		Terminal:   TerminalInteractive,
+		OnComplete: func(*tuish.LicenseDetails) { completions++ },
+	})
+	flow.Init()
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{SessionID: "sess_1"}})
+	oldSeq := flow.pollSeq
+
+	// An expired reply for the cancelled session
+	flow.Cancel()
+	flow.Update(CheckoutStatusMsg{Completed: true, poll: oldSeq})
+	if flow.Step() != PurchaseStepCancelled {
+		t.Errorf("expected a stale reply after Cancel to be ignored, got step %d", flow.Step())
+	}
+
+	// A completed reply for the previous session after a retry
+	flow.Retry()
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{SessionID: "sess_2"}})
+	flow.Update(CheckoutStatusMsg{Completed: true, License: &tuish.LicenseDetails{ID: "lic_old"}, poll: oldSeq})
+	if flow.Step() != PurchaseStepWaiting {
+		t.Errorf("expected a stale reply after Retry to be ignored, got step %d", flow.Step())
+	}
+	flow.Update(CheckoutStatusMsg{Completed: true, poll: oldSeq})
+	if flow.Step() != PurchaseStepWaiting {
+		t.Errorf("expected a stale expiry after Retry to be ignored, got step %d", flow.Step())
+	}
+	if completions != 0 {
+		t.Errorf("expected no OnComplete for stale replies, got %d", completions)
+	}
+}
+
+func TestPurchaseFlowIgnoresSessionAfterCancel(t *testing.T) {
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{Terminal: TerminalInteractive})
+	flow.Init()
+	flow.Cancel()
+
+	// The checkout request finishes after the user has already cancelled
+	_, cmd := flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_late",
+		CheckoutURL: "https://example.com/checkout",
+	}})
+	if cmd != nil {
+		t.Error("expected no polling for a session created after Cancel")
+	}
+	if flow.Step() != PurchaseStepCancelled {
+		t.Errorf("expected the flow to stay cancelled, got step %d", flow.Step())
+	}
+	if flow.SessionID() != "" {
+		t.Errorf("expected the late session to be ignored, got %q", flow.SessionID())
+	}
+}
+
+func TestPurchaseFlowPollBackoff(t *testing.T) {
+	flow := NewPurchaseFlow(nil, PurchaseFlowConfig{
+		PollInterval:    time.Second,
+		MaxPollInterval: 5 * time.Second,
+		Timeout:         time.Hour,
+		Terminal:        TerminalHeadless,
+	})
+	flow.Init()
+	flow.Update(CheckoutSessionCreatedMsg{Session: &tuish.CheckoutSessionResult{
+		SessionID:   "sess_1",
+		CheckoutURL: "https://example.com/checkout",
+	}})
+
+	poll := func(status string) time.Duration {
+		t.Helper()
+		if _, cmd := flow.Update(CheckoutStatusMsg{Status: status, poll: flow.pollSeq}); cmd == nil {
+			t.Fatalf("expected another poll after %q", status)
+		}
+		return flow.pollDelay
+	}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := poll("pending"); got != w {
+			t.Errorf("pending poll %d: expected delay %s, got %s", i+1, w, got)
+		}
+	}
+
+	if got := poll("processing"); got != time.Second {
+		t.Errorf("expected a status change to reset the delay, got %s", got)
+	}
+}
+
+func TestPurchaseFlowPollJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := jitter(10*time.Second, 0.2)
+		if d < 8*time.Second || d > 12*time.Second {
+			t.Fatalf("expected jitter within ±20%%, got %s", d)
+		}
+	}
+	if d := jitter(10*time.Second, 0); d != 10*time.Second {
+		t.Errorf("expected no jitter by default, got %s", d)
+	}
+}
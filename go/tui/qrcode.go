@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"os"
 	"strings"
 
@@ -9,15 +10,53 @@ import (
 	qrcode "github.com/skip2/go-qrcode"
 )
 
+// QR codes larger than version 13 (69x69 modules) would flood an 80-column
+// terminal, so longer values are shown as a plain URL instead. 425 bytes is
+// the byte-mode capacity of version 13 at low error correction.
+const (
+	maxQRModules = 69
+	maxQRBytes   = 425
+)
+
+// ErrQRTooLarge is returned when a value is too long to render as a QR code
+// that fits in the terminal.
+var ErrQRTooLarge = errors.New("URL too long for a QR code")
+
+// QRCellStyle selects how QR modules are drawn in the terminal.
+type QRCellStyle int
+
+const (
+	// QRCellHalfBlock packs two rows of modules into each line using ▀/▄.
+	// It is the most compact style.
+	QRCellHalfBlock QRCellStyle = iota
+
+	// QRCellFullBlock draws each module as "██" or two spaces. It is twice
+	// as tall as QRCellHalfBlock but scans reliably in fonts where
+	// half-blocks render with gaps.
+	QRCellFullBlock
+
+	// QRCellTwoSpace draws each module as two spaces with a black or white
+	// background, for fonts without usable block characters.
+	QRCellTwoSpace
+)
+
 // QRCodeConfig contains configuration for the QRCode component.
 type QRCodeConfig struct {
 	// URLOnly forces URL-only display (no QR code).
 	URLOnly bool
 
 	// MinWidth is the minimum terminal width to display QR code.
-	// Falls back to URL-only if terminal is narrower.
+	// Falls back to URL-only if terminal is narrower, or narrower than the
+	// rendered code itself.
 	MinWidth int
 
+	// CellStyle selects how modules are drawn (default: QRCellHalfBlock).
+	CellStyle QRCellStyle
+
+	// Terminal controls plain-text rendering. When headless (by default,
+	// when stdout isn't a terminal) only the URL is shown.
+	Terminal TerminalMode
+
 	// Styles allows custom styling.
 	Styles *Styles
 }
@@ -39,6 +78,11 @@ type QRCode struct {
 	canFit   bool
 	err      error
 	loading  bool
+	headless bool
+
+	// width is the terminal width from the last WindowSizeMsg (0 until
+	// one arrives)
+	width int
 }
 
 // NewQRCode creates a new QRCode component.
@@ -53,17 +97,19 @@ func NewQRCode(value string, config ...QRCodeConfig) *QRCode {
 		styles = *cfg.Styles
 	}
 
+	headless := cfg.Terminal.headless()
 	return &QRCode{
-		value:   value,
-		config:  cfg,
-		styles:  styles,
-		loading: true,
+		value:    value,
+		config:   cfg,
+		styles:   styles,
+		loading:  !headless,
+		headless: headless,
 	}
 }
 
 // Init initializes the QRCode component.
 func (m *QRCode) Init() tea.Cmd {
-	return m.generateQR
+	return m.generate(m.terminalWidth())
 }
 
 // Update handles messages for the QRCode component.
@@ -74,11 +120,25 @@ func (m *QRCode) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.qrString = msg.QRString
 		m.canFit = msg.CanFit
 		m.err = msg.Error
+		// The terminal may have been resized while the code was generated
+		if m.qrString != "" {
+			m.canFit = m.fits(m.qrString, m.terminalWidth())
+		}
 		return m, nil
 
 	case tea.WindowSizeMsg:
-		// Re-check if QR fits on resize
-		m.canFit = msg.Width >= m.config.MinWidth
+		// Switch between the QR code and the URL as the terminal resizes
+		m.width = msg.Width
+		if m.qrString != "" {
+			m.canFit = m.fits(m.qrString, msg.Width)
+			return m, nil
+		}
+		// The code was skipped for a narrow terminal; generate it now if
+		// there may be room
+		if !m.loading && !m.headless && !m.config.URLOnly && m.err == nil && msg.Width >= m.config.MinWidth {
+			m.loading = true
+			return m, m.generate(msg.Width)
+		}
 		return m, nil
 	}
 
@@ -87,10 +147,18 @@ func (m *QRCode) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the QRCode component.
 func (m *QRCode) View() string {
+	if m.headless {
+		return "Visit: " + m.value
+	}
+
 	if m.loading {
 		return m.styles.Muted.Render("Generating QR code...")
 	}
 
+	if errors.Is(m.err, ErrQRTooLarge) {
+		return renderQRTooLarge(m.value, m.styles)
+	}
+
 	if m.err != nil {
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
@@ -114,6 +182,15 @@ func (m *QRCode) renderURLOnly() string {
 	)
 }
 
+// renderQRTooLarge explains why no QR code is shown and prints the URL.
+func renderQRTooLarge(url string, styles Styles) string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		styles.Muted.Render(ErrQRTooLarge.Error()+"; visit:"),
+		styles.Link.Render(url),
+	)
+}
+
 func (m *QRCode) renderWithQR() string {
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -123,31 +200,50 @@ func (m *QRCode) renderWithQR() string {
 	)
 }
 
-func (m *QRCode) generateQR() tea.Msg {
-	// Check terminal width
-	width := getTerminalWidth()
-	canFit := width >= m.config.MinWidth
+// generate returns a command that renders the QR code for a terminal width
+// columns wide. The code is kept even if it doesn't fit, so widening the
+// terminal can show it without generating it again.
+func (m *QRCode) generate(width int) tea.Cmd {
+	value, config, headless := m.value, m.config, m.headless
+	return func() tea.Msg {
+		if config.URLOnly || headless || width < config.MinWidth {
+			return QRGeneratedMsg{CanFit: false}
+		}
 
-	if m.config.URLOnly || !canFit {
-		return QRGeneratedMsg{CanFit: false}
-	}
+		qr, err := generateQRMatrix(value, config.CellStyle)
+		if err != nil {
+			return QRGeneratedMsg{Error: err, CanFit: false}
+		}
 
-	qr, err := generateQRMatrix(m.value)
-	if err != nil {
-		return QRGeneratedMsg{Error: err, CanFit: false}
+		return QRGeneratedMsg{
+			QRString: qr,
+			CanFit:   m.fits(qr, width),
+		}
 	}
+}
 
-	return QRGeneratedMsg{
-		QRString: qr,
-		CanFit:   true,
+// fits reports whether a rendered QR code, quiet zone included, fits in a
+// terminal width columns wide. As in CanFitQRCode, that is the code's real
+// width, which depends on its module count and cell style.
+func (m *QRCode) fits(qr string, width int) bool {
+	return width >= m.config.MinWidth && width >= lipgloss.Width(qr)
+}
+
+// terminalWidth returns the width from the last WindowSizeMsg, or a guess
+// before one arrives.
+func (m *QRCode) terminalWidth() int {
+	if m.width > 0 {
+		return m.width
 	}
+	return getTerminalWidth()
 }
 
 // SetValue updates the QR code value.
 func (m *QRCode) SetValue(value string) tea.Cmd {
 	m.value = value
-	m.loading = true
-	return m.generateQR
+	m.qrString = ""
+	m.loading = !m.headless
+	return m.generate(m.terminalWidth())
 }
 
 // getTerminalWidth returns the terminal width, defaulting to 80 if unknown.
@@ -165,19 +261,78 @@ func getTerminalWidth() int {
 	return width
 }
 
-// generateQRMatrix generates a QR code as a string using Unicode half-blocks.
-func generateQRMatrix(text string) (string, error) {
+// newQRBitmap encodes text at the smallest QR version that holds it, and
+// returns ErrQRTooLarge if that exceeds maxQRModules.
+func newQRBitmap(text string) ([][]bool, error) {
+	// Bail out before encoding so very long values don't stall rendering
+	if len(text) > maxQRBytes {
+		return nil, ErrQRTooLarge
+	}
+
 	// Generate QR code with low error correction for smaller size
 	qr, err := qrcode.New(text, qrcode.Low)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Disable border for tighter rendering
 	qr.DisableBorder = true
 
-	// Get the bitmap
 	bitmap := qr.Bitmap()
+	if len(bitmap) > maxQRModules {
+		return nil, ErrQRTooLarge
+	}
+	return bitmap, nil
+}
+
+// generateQRMatrix generates a QR code as a string in the given cell style.
+func generateQRMatrix(text string, style QRCellStyle) (string, error) {
+	bitmap, err := newQRBitmap(text)
+	if err != nil {
+		return "", err
+	}
+
+	switch style {
+	case QRCellFullBlock:
+		return renderQRCells(bitmap, "██", "  "), nil
+	case QRCellTwoSpace:
+		dark := lipgloss.NewStyle().Background(lipgloss.Color("#000000")).Render("  ")
+		light := lipgloss.NewStyle().Background(lipgloss.Color("#FFFFFF")).Render("  ")
+		return renderQRCells(bitmap, dark, light), nil
+	default:
+		return renderQRHalfBlocks(bitmap), nil
+	}
+}
+
+// renderQRCells draws one line per module row, using dark and light for each
+// module, surrounded by a one-cell quiet zone.
+func renderQRCells(bitmap [][]bool, dark, light string) string {
+	size := len(bitmap)
+	border := strings.Repeat(light, size+2)
+
+	var sb strings.Builder
+	sb.WriteString(border)
+	sb.WriteString("\n")
+
+	for _, row := range bitmap {
+		sb.WriteString(light)
+		for _, module := range row {
+			if module {
+				sb.WriteString(dark)
+			} else {
+				sb.WriteString(light)
+			}
+		}
+		sb.WriteString(light)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(border)
+	return sb.String()
+}
+
+// renderQRHalfBlocks draws two module rows per line using Unicode half-blocks.
+func renderQRHalfBlocks(bitmap [][]bool) string {
 	size := len(bitmap)
 
 	// Use Unicode half-block characters for 2:1 aspect ratio
@@ -233,7 +388,7 @@ func generateQRMatrix(text string) (string, error) {
 	// White border on bottom
 	sb.WriteString(strings.Repeat(empty, borderWidth))
 
-	return sb.String(), nil
+	return sb.String()
 }
 
 // RenderQRCode generates and returns a QR code string for the given URL.
@@ -244,7 +399,10 @@ func RenderQRCode(url string, styles ...Styles) string {
 		s = styles[0]
 	}
 
-	qr, err := generateQRMatrix(url)
+	qr, err := generateQRMatrix(url, QRCellHalfBlock)
+	if errors.Is(err, ErrQRTooLarge) {
+		return renderQRTooLarge(url, s)
+	}
 	if err != nil {
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
@@ -263,12 +421,12 @@ func RenderQRCode(url string, styles ...Styles) string {
 
 // CanFitQRCode checks if a QR code would fit in the given terminal width.
 func CanFitQRCode(url string, terminalWidth int) bool {
-	qr, err := qrcode.New(url, qrcode.Low)
+	bitmap, err := newQRBitmap(url)
 	if err != nil {
 		return false
 	}
 
 	// QR code width + borders
-	qrWidth := len(qr.Bitmap()) + 4
+	qrWidth := len(bitmap) + 4
 	return terminalWidth >= qrWidth
 }
@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+func longURL() string {
+	return "https://checkout.example.com/session?token=" + strings.Repeat("a", 2000)
+}
+
+func TestGenerateQRMatrixRejectsLongURL(t *testing.T) {
+	if _, err := generateQRMatrix(longURL(), QRCellHalfBlock); !errors.Is(err, ErrQRTooLarge) {
+		t.Fatalf("err = %v, want ErrQRTooLarge", err)
+	}
+}
+
+func TestGenerateQRMatrixCapsModules(t *testing.T) {
+	// The byte limit is exactly what fits under the module cap
+	url := "https://example.com/" + strings.Repeat("x", maxQRBytes-len("https://example.com/"))
+	bitmap, err := newQRBitmap(url)
+	if err != nil {
+		t.Fatalf("expected a %d-byte value to encode, got %v", maxQRBytes, err)
+	}
+	if len(bitmap) > maxQRModules {
+		t.Fatalf("bitmap size %d exceeds cap %d", len(bitmap), maxQRModules)
+	}
+
+	// One byte more needs a larger version, without being encoded
+	qr, err := qrcode.New(url+"x", qrcode.Low)
+	if err != nil {
+		t.Fatalf("encode %d bytes: %v", maxQRBytes+1, err)
+	}
+	if qr.VersionNumber <= 13 {
+		t.Errorf("expected %d bytes to need a version above 13, got %d", maxQRBytes+1, qr.VersionNumber)
+	}
+	if _, err := newQRBitmap(url + "x"); !errors.Is(err, ErrQRTooLarge) {
+		t.Errorf("expected ErrQRTooLarge one byte over the limit, got %v", err)
+	}
+}
+
+func TestRenderQRCodeLongURLFallsBackToLink(t *testing.T) {
+	url := longURL()
+	out := RenderQRCode(url)
+
+	if !strings.Contains(out, ErrQRTooLarge.Error()) {
+		t.Errorf("expected too-large message, got:\n%s", out)
+	}
+	if !strings.Contains(out, url) {
+		t.Error("expected URL in fallback output")
+	}
+	if strings.Contains(out, "▀") || strings.Contains(out, "█") {
+		t.Error("expected no QR blocks in fallback output")
+	}
+}
+
+func TestQRCodeComponentLongURL(t *testing.T) {
+	url := longURL()
+	qr := NewQRCode(url, QRCodeConfig{Terminal: TerminalInteractive})
+	qr.Update(QRGeneratedMsg{Error: ErrQRTooLarge})
+
+	view := qr.View()
+	if strings.Contains(view, "QR Error") {
+		t.Errorf("expected graceful fallback, got:\n%s", view)
+	}
+	if !strings.Contains(view, ErrQRTooLarge.Error()) || !strings.Contains(view, url) {
+		t.Errorf("expected too-large message and URL, got:\n%s", view)
+	}
+}
+
+func TestCanFitQRCodeLongURL(t *testing.T) {
+	if CanFitQRCode(longURL(), 1000) {
+		t.Error("expected oversized URL not to fit")
+	}
+	if !CanFitQRCode("https://example.com", 80) {
+		t.Error("expected short URL to fit in 80 columns")
+	}
+}
+
+func TestGenerateQRMatrixFullBlockWidth(t *testing.T) {
+	url := "https://example.com/checkout/abc123"
+	bitmap, err := newQRBitmap(url)
+	if err != nil {
+		t.Fatalf("newQRBitmap: %v", err)
+	}
+	size := len(bitmap)
+
+	out, err := generateQRMatrix(url, QRCellFullBlock)
+	if err != nil {
+		t.Fatalf("generateQRMatrix: %v", err)
+	}
+
+	lines := strings.Split(out, "\n")
+	if len(lines) != size+2 {
+		t.Errorf("got %d lines, want %d", len(lines), size+2)
+	}
+	want := 2*size + 4
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w != want {
+			t.Fatalf("line %d width = %d, want %d", i, w, want)
+		}
+	}
+	if strings.ContainsAny(out, "▀▄") {
+		t.Error("expected no half-blocks in full-block output")
+	}
+}
+
+func TestGenerateQRMatrixTwoSpaceWidth(t *testing.T) {
+	url := "https://example.com/checkout/abc123"
+	bitmap, err := newQRBitmap(url)
+	if err != nil {
+		t.Fatalf("newQRBitmap: %v", err)
+	}
+
+	out, err := generateQRMatrix(url, QRCellTwoSpace)
+	if err != nil {
+		t.Fatalf("generateQRMatrix: %v", err)
+	}
+	if strings.ContainsAny(out, "▀▄█") {
+		t.Error("expected no block characters in two-space output")
+	}
+	want := 2*len(bitmap) + 4
+	for i, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w != want {
+			t.Fatalf("line %d width = %d, want %d", i, w, want)
+		}
+	}
+}
+
+func TestQRCodeHeadlessShowsURLOnly(t *testing.T) {
+	qr := NewQRCode("https://example.com/checkout", QRCodeConfig{Terminal: TerminalHeadless})
+
+	if msg := qr.generate(80)().(QRGeneratedMsg); msg.QRString != "" {
+		t.Error("expected no QR code to be generated when headless")
+	}
+	if view := qr.View(); view != "Visit: https://example.com/checkout" {
+		t.Errorf("unexpected headless view: %q", view)
+	}
+}
+
+func TestQRCodeSwitchesModesOnResize(t *testing.T) {
+	const url = "https://example.com/checkout/sess_123"
+	isQR := func(view string) bool { return strings.ContainsAny(view, "▀▄█") }
+
+	qr := NewQRCode(url, QRCodeConfig{Terminal: TerminalInteractive})
+	qr.Update(tea.WindowSizeMsg{Width: 20})
+	qr.Update(qr.Init()())
+	if view := qr.View(); isQR(view) || !strings.Contains(view, url) {
+		t.Fatalf("expected URL only in a terminal narrower than the code, got:\n%s", view)
+	}
+
+	// The code was generated, so widening shows it straight away
+	if _, cmd := qr.Update(tea.WindowSizeMsg{Width: 120}); cmd != nil {
+		t.Error("expected no regeneration when the code is already generated")
+	}
+	if !isQR(qr.View()) {
+		t.Fatalf("expected a QR code in a wide terminal, got:\n%s", qr.View())
+	}
+
+	qr.Update(tea.WindowSizeMsg{Width: 20})
+	if isQR(qr.View()) {
+		t.Errorf("expected URL only after narrowing, got:\n%s", qr.View())
+	}
+}
+
+func TestQRCodeGeneratesWhenWidened(t *testing.T) {
+	qr := NewQRCode("https://example.com/checkout", QRCodeConfig{MinWidth: 50, Terminal: TerminalInteractive})
+	qr.Update(tea.WindowSizeMsg{Width: 30})
+	qr.Update(qr.Init()())
+	if qr.qrString != "" {
+		t.Fatal("expected no code to be generated below MinWidth")
+	}
+
+	_, cmd := qr.Update(tea.WindowSizeMsg{Width: 120})
+	if cmd == nil {
+		t.Fatal("expected the code to be generated once the terminal is wide enough")
+	}
+	if _, again := qr.Update(tea.WindowSizeMsg{Width: 121}); again != nil {
+		t.Error("expected no second generation while one is in flight")
+	}
+	qr.Update(cmd())
+	if !strings.ContainsAny(qr.View(), "▀▄█") {
+		t.Errorf("expected a QR code, got:\n%s", qr.View())
+	}
+}
+
+func TestQRCodeFitsWidthAfterGeneration(t *testing.T) {
+	qr := NewQRCode("https://example.com/checkout", QRCodeConfig{Terminal: TerminalInteractive})
+	qr.Update(tea.WindowSizeMsg{Width: 120})
+	cmd := qr.Init()
+
+	// The terminal narrows while the code is being generated
+	qr.Update(tea.WindowSizeMsg{Width: 20})
+	qr.Update(cmd())
+	if strings.ContainsAny(qr.View(), "▀▄█") {
+		t.Errorf("expected URL only in a terminal narrowed during generation, got:\n%s", qr.View())
+	}
+}
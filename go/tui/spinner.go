@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// spinnerIDs hands out spinner IDs so each spinner only consumes its own ticks.
+var spinnerIDs atomic.Int64
+
+// SpinnerConfig contains configuration for the Spinner component.
+type SpinnerConfig struct {
+	// Frames are the animation frames (default: Styles.Spinner).
+	Frames []string
+
+	// Interval is the time between frames (default: 100ms).
+	Interval time.Duration
+
+	// Styles allows custom styling.
+	Styles *Styles
+}
+
+// DefaultSpinnerConfig returns the default configuration.
+func DefaultSpinnerConfig() SpinnerConfig {
+	return SpinnerConfig{
+		Interval: 100 * time.Millisecond,
+	}
+}
+
+// Spinner is an animated activity indicator. It schedules a SpinnerTickMsg
+// for itself after every frame, so it animates as long as its parent keeps
+// passing the ticks to Update.
+type Spinner struct {
+	id       int64
+	frames   []string
+	interval time.Duration
+	frame    int
+}
+
+// NewSpinner creates a new Spinner component.
+func NewSpinner(config ...SpinnerConfig) *Spinner {
+	cfg := DefaultSpinnerConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultSpinnerConfig().Interval
+	}
+
+	frames := cfg.Frames
+	if len(frames) == 0 {
+		styles := DefaultStyles()
+		if cfg.Styles != nil {
+			styles = *cfg.Styles
+		}
+		frames = styles.Spinner
+	}
+	if len(frames) == 0 {
+		frames = SpinnerFrames
+	}
+
+	return &Spinner{
+		id:       spinnerIDs.Add(1),
+		frames:   frames,
+		interval: cfg.Interval,
+	}
+}
+
+// Init starts the animation.
+func (m *Spinner) Init() tea.Cmd {
+	return m.Tick()
+}
+
+// Update advances the spinner on its own ticks. A tick without an ID, such
+// as one from SpinnerTick, advances every spinner.
+func (m *Spinner) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(SpinnerTickMsg); ok && (msg.ID == 0 || msg.ID == m.id) {
+		m.frame = (m.frame + 1) % len(m.frames)
+		return m, m.Tick()
+	}
+	return m, nil
+}
+
+// View renders the current frame.
+func (m *Spinner) View() string {
+	return m.frames[m.frame]
+}
+
+// Tick schedules the spinner's next frame.
+func (m *Spinner) Tick() tea.Cmd {
+	id := m.id
+	return tea.Tick(m.interval, func(t time.Time) tea.Msg {
+		return SpinnerTickMsg{Time: t, ID: id}
+	})
+}
+
+// Reset returns the spinner to its first frame.
+func (m *Spinner) Reset() {
+	m.frame = 0
+}
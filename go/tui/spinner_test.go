@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpinnerAdvancesAndWraps(t *testing.T) {
+	spinner := NewSpinner(SpinnerConfig{Frames: []string{"a", "b", "c"}})
+
+	var got []string
+	for i := 0; i < 5; i++ {
+		got = append(got, spinner.View())
+		if _, cmd := spinner.Update(SpinnerTickMsg{ID: spinner.id}); cmd == nil {
+			t.Fatal("expected the next tick to be scheduled")
+		}
+	}
+
+	want := []string{"a", "b", "c", "a", "b"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("frame %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	spinner.Reset()
+	if spinner.View() != "a" {
+		t.Errorf("expected reset to the first frame, got %q", spinner.View())
+	}
+}
+
+func TestSpinnerIgnoresOtherSpinnersTicks(t *testing.T) {
+	first := NewSpinner(SpinnerConfig{Frames: []string{"a", "b"}})
+	second := NewSpinner(SpinnerConfig{Frames: []string{"a", "b"}})
+
+	if _, cmd := first.Update(SpinnerTickMsg{ID: second.id}); cmd != nil {
+		t.Error("expected no tick for another spinner's message")
+	}
+	if first.View() != "a" {
+		t.Errorf("expected first spinner not to advance, got %q", first.View())
+	}
+
+	// Untargeted ticks advance every spinner
+	first.Update(SpinnerTick())
+	second.Update(SpinnerTick())
+	if first.View() != "b" || second.View() != "b" {
+		t.Error("expected an untargeted tick to advance both spinners")
+	}
+}
+
+func TestSpinnerFramesFromStyles(t *testing.T) {
+	ascii := ASCIIStyles()
+	spinner := NewSpinner(SpinnerConfig{Styles: &ascii, Interval: time.Second})
+	if got := spinner.View(); got != ascii.Spinner[0] {
+		t.Errorf("expected ASCII frame %q, got %q", ascii.Spinner[0], got)
+	}
+	if spinner.interval != time.Second {
+		t.Errorf("expected 1s interval, got %s", spinner.interval)
+	}
+
+	if got := NewSpinner().View(); got != SpinnerFrames[0] {
+		t.Errorf("expected default frame %q, got %q", SpinnerFrames[0], got)
+	}
+}
@@ -1,6 +1,10 @@
 package tui
 
 import (
+	"os"
+	"runtime"
+	"strings"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -81,6 +85,7 @@ type Styles struct {
 	StatusValid   lipgloss.Style
 	StatusInvalid lipgloss.Style
 	StatusPending lipgloss.Style
+	StatusRevoked lipgloss.Style
 
 	// Keyboard hints
 	KeyHint  lipgloss.Style
@@ -93,6 +98,54 @@ type Styles struct {
 
 	// Prompt style
 	Prompt lipgloss.Style
+
+	// Spinner animation frames and progress bar characters
+	Spinner       []string
+	ProgressFull  string
+	ProgressEmpty string
+
+	// Glyphs are the symbols components draw with
+	Glyphs Glyphs
+
+	// ASCII indicates the styles avoid non-ASCII characters
+	ASCII bool
+}
+
+// Glyphs holds the symbols used by components, so they can be swapped for
+// ASCII on terminals that can't render Unicode.
+type Glyphs struct {
+	Check     string
+	Cross     string
+	Bullet    string
+	Arrow     string
+	ArrowUp   string
+	ArrowDown string
+	Warning   string
+	Ellipsis  string
+}
+
+// UnicodeGlyphs are the default glyphs.
+var UnicodeGlyphs = Glyphs{
+	Check:     CheckMark,
+	Cross:     CrossMark,
+	Bullet:    BulletPoint,
+	Arrow:     ArrowRight,
+	ArrowUp:   ArrowUp,
+	ArrowDown: ArrowDown,
+	Warning:   WarningSign,
+	Ellipsis:  Ellipsis,
+}
+
+// ASCIIGlyphs are plain ASCII replacements for UnicodeGlyphs.
+var ASCIIGlyphs = Glyphs{
+	Check:     "+",
+	Cross:     "x",
+	Bullet:    "*",
+	Arrow:     "->",
+	ArrowUp:   "^",
+	ArrowDown: "v",
+	Warning:   "!",
+	Ellipsis:  "...",
 }
 
 // DefaultStyles returns the default styled components.
@@ -205,6 +258,12 @@ func NewStyles(theme Theme) Styles {
 		StatusPending: lipgloss.NewStyle().
 			Foreground(theme.Muted),
 
+		StatusRevoked: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Inverted).
+			Background(theme.Error).
+			Padding(0, 1),
+
 		// Keyboard hints
 		KeyHint: lipgloss.NewStyle().
 			Foreground(theme.Muted),
@@ -240,9 +299,145 @@ func NewStyles(theme Theme) Styles {
 			Bold(true).
 			Foreground(theme.Primary).
 			SetString("$ "),
+
+		// Animation
+		Spinner:       SpinnerFrames,
+		ProgressFull:  ProgressBarChars.Full,
+		ProgressEmpty: ProgressBarChars.Empty,
+
+		Glyphs: UnicodeGlyphs,
 	}
 }
 
+// ASCIIStyles returns the default styles using only ASCII characters: plain
+// glyphs, a |/-\\ spinner, a #/- progress bar and +-| box borders.
+func ASCIIStyles() Styles {
+	styles := DefaultStyles()
+	styles.ASCII = true
+	styles.Spinner = []string{"|", "/", "-", "\\"}
+	styles.ProgressFull = "#"
+	styles.ProgressEmpty = "-"
+	styles.Glyphs = ASCIIGlyphs
+
+	styles.Bullet = styles.Bullet.SetString(ASCIIGlyphs.Bullet + " ")
+	styles.CheckMark = styles.CheckMark.SetString(ASCIIGlyphs.Check + " ")
+	styles.CrossMark = styles.CrossMark.SetString(ASCIIGlyphs.Cross + " ")
+
+	border := asciiBorder
+	styles.Box = styles.Box.Border(border)
+	styles.BoxFocused = styles.BoxFocused.Border(border)
+	styles.BoxSuccess = styles.BoxSuccess.Border(border)
+	styles.BoxWarning = styles.BoxWarning.Border(border)
+	styles.BoxError = styles.BoxError.Border(border)
+
+	return styles
+}
+
+// asciiBorder draws boxes with +, - and |.
+var asciiBorder = lipgloss.Border{
+	Top:          "-",
+	Bottom:       "-",
+	Left:         "|",
+	Right:        "|",
+	TopLeft:      "+",
+	TopRight:     "+",
+	BottomLeft:   "+",
+	BottomRight:  "+",
+	MiddleLeft:   "+",
+	MiddleRight:  "+",
+	Middle:       "+",
+	MiddleTop:    "+",
+	MiddleBottom: "+",
+}
+
+// DetectStyles returns ASCIIStyles when the terminal is unlikely to render
+// UTF-8 (TERM=dumb, a non-UTF-8 locale, or a legacy Windows console) and
+// DefaultStyles otherwise.
+func DetectStyles() Styles {
+	if supportsUnicode() {
+		return DefaultStyles()
+	}
+	return ASCIIStyles()
+}
+
+func supportsUnicode() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		// Windows Terminal and most third-party terminals handle UTF-8;
+		// the legacy console host doesn't
+		return os.Getenv("WT_SESSION") != "" || os.Getenv("TERM_PROGRAM") != ""
+	}
+
+	// The first locale variable that is set wins, per POSIX precedence
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := strings.ToLower(os.Getenv(key)); value != "" {
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return false
+}
+
+// Clone returns an independent copy of the styles. lipgloss.Style values are
+// cheap to copy, so this only has to duplicate the spinner frames, which
+// DefaultStyles shares with the SpinnerFrames global.
+func (s Styles) Clone() Styles {
+	clone := s
+	if s.Spinner != nil {
+		clone.Spinner = append([]string(nil), s.Spinner...)
+	}
+	return clone
+}
+
+// With returns a clone of the styles modified by fn, leaving s untouched:
+//
+//	styles := tui.DefaultStyles().With(func(s *tui.Styles) {
+//		s.Error = s.Error.Foreground(lipgloss.Color("#FF8800"))
+//	})
+func (s Styles) With(fn func(*Styles)) Styles {
+	clone := s.Clone()
+	fn(&clone)
+	return clone
+}
+
+// SpinnerFrame returns the spinner frame for the given tick, falling back
+// to SpinnerFrames when the styles don't define any.
+func (s Styles) SpinnerFrame(tick int) string {
+	frames := s.Spinner
+	if len(frames) == 0 {
+		frames = SpinnerFrames
+	}
+	return frames[tick%len(frames)]
+}
+
+// glyphs returns the styles' glyphs, filling any left empty from
+// UnicodeGlyphs (or ASCIIGlyphs for ASCII styles), the way SpinnerFrame falls
+// back to SpinnerFrames.
+func (s Styles) glyphs() Glyphs {
+	fallback := UnicodeGlyphs
+	if s.ASCII {
+		fallback = ASCIIGlyphs
+	}
+	g := s.Glyphs
+	g.Check = orDefault(g.Check, fallback.Check)
+	g.Cross = orDefault(g.Cross, fallback.Cross)
+	g.Bullet = orDefault(g.Bullet, fallback.Bullet)
+	g.Arrow = orDefault(g.Arrow, fallback.Arrow)
+	g.ArrowUp = orDefault(g.ArrowUp, fallback.ArrowUp)
+	g.ArrowDown = orDefault(g.ArrowDown, fallback.ArrowDown)
+	g.Warning = orDefault(g.Warning, fallback.Warning)
+	g.Ellipsis = orDefault(g.Ellipsis, fallback.Ellipsis)
+	return g
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
 // Unicode symbols used in UI
 const (
 	CheckMark    = "\u2713" // ✓
@@ -250,10 +445,13 @@ const (
 	BulletPoint  = "\u2022" // •
 	ArrowRight   = "\u2192" // →
 	ArrowLeft    = "\u2190" // ←
+	ArrowUp      = "\u2191" // ↑
+	ArrowDown    = "\u2193" // ↓
 	WarningSign  = "\u26A0" // ⚠
 	InfoSign     = "\u2139" // ℹ
 	PointerRight = "\u25B8" // ▸
 	PointerDown  = "\u25BE" // ▾
+	Ellipsis     = "\u2026" // …
 
 	// Additional symbols
 	CircleNumber1 = "\u2460" // ①
@@ -270,10 +468,12 @@ const (
 	Wave          = "\U0001F44B" // 👋
 )
 
-// SpinnerFrames contains the frames for the spinner animation.
+// SpinnerFrames contains the default frames for the spinner animation.
+// Components read frames from Styles.Spinner; this is kept for compatibility.
 var SpinnerFrames = []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
 
-// ProgressBarChars contains characters for progress bar rendering.
+// ProgressBarChars contains the default progress bar characters.
+// Components read them from Styles.ProgressFull/ProgressEmpty.
 var ProgressBarChars = struct {
 	Full  string
 	Empty string
@@ -300,23 +500,85 @@ func RenderProgressBar(progress float64, width int, styles Styles) string {
 		filled = 0
 	}
 
+	full, empty := styles.ProgressFull, styles.ProgressEmpty
+	if full == "" {
+		full = ProgressBarChars.Full
+	}
+	if empty == "" {
+		empty = ProgressBarChars.Empty
+	}
+
 	bar := ""
 	for i := 0; i < width; i++ {
 		if i < filled {
-			bar += ProgressBarChars.Full
+			bar += full
 		} else {
-			bar += ProgressBarChars.Empty
+			bar += empty
 		}
 	}
 
 	return styles.Highlight.Render(bar)
 }
 
+// RenderFeatureBadges renders features as a one-line row of badges like
+// "[pro] [export] [analytics]" for status bars, in the StatusValid style.
+// Pass styles with StatusValid set to StatusInvalid to render an invalid
+// license's features.
+func RenderFeatureBadges(features []string, styles Styles) string {
+	return renderFeatureBadges(features, 0, styles.StatusValid, styles.Muted)
+}
+
+// renderFeatureBadges renders a badge row at most width columns wide,
+// replacing the badges that don't fit with "+N more" (width 0 = no limit).
+func renderFeatureBadges(features []string, width int, style, moreStyle lipgloss.Style) string {
+	badges := make([]string, len(features))
+	for i, feature := range features {
+		badges[i] = style.Render("[" + feature + "]")
+	}
+	all := strings.Join(badges, " ")
+	if width <= 0 || lipgloss.Width(all) <= width {
+		return all
+	}
+
+	shown, used := 0, 0
+	for shown < len(badges) {
+		w := lipgloss.Width(badges[shown])
+		if shown > 0 {
+			w++
+		}
+		// Leave room for the summary if this isn't the last badge
+		reserve := 0
+		if rest := len(badges) - shown - 1; rest > 0 {
+			reserve = 1 + lipgloss.Width("+"+tr("status.more_features", rest))
+		}
+		if used+w+reserve > width {
+			break
+		}
+		used += w
+		shown++
+	}
+
+	row := strings.Join(badges[:shown], " ")
+	if hidden := len(badges) - shown; hidden > 0 {
+		if shown > 0 {
+			row += " "
+		}
+		row += moreStyle.Render("+" + tr("status.more_features", hidden))
+	}
+	return row
+}
+
 // RenderKeyHint renders a keyboard shortcut hint like "[Esc] Cancel".
 func RenderKeyHint(key, label string, styles Styles) string {
 	return styles.KeyLabel.Render("["+key+"]") + " " + styles.KeyHint.Render(label)
 }
 
+// inlineBlock trims leading and trailing newlines so a rendered component
+// composes cleanly with lipgloss.JoinVertical.
+func inlineBlock(s string) string {
+	return strings.Trim(s, "\n")
+}
+
 // RenderKeyHints renders multiple keyboard hints separated by spaces.
 func RenderKeyHints(hints [][2]string, styles Styles) string {
 	result := ""
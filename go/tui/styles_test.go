@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRenderProgressBarUsesStyleChars(t *testing.T) {
+	bar := RenderProgressBar(0.5, 10, ASCIIStyles())
+	if !strings.Contains(bar, "#####-----") {
+		t.Errorf("expected ASCII progress bar, got %q", bar)
+	}
+
+	bar = RenderProgressBar(0.5, 10, DefaultStyles())
+	if !strings.Contains(bar, strings.Repeat(ProgressBarChars.Full, 5)+strings.Repeat(ProgressBarChars.Empty, 5)) {
+		t.Errorf("expected default progress bar, got %q", bar)
+	}
+}
+
+func TestRenderProgressBarZeroStylesFallsBack(t *testing.T) {
+	bar := RenderProgressBar(1, 4, Styles{})
+	if !strings.Contains(bar, strings.Repeat(ProgressBarChars.Full, 4)) {
+		t.Errorf("expected fallback progress chars, got %q", bar)
+	}
+}
+
+func TestSpinnerFrame(t *testing.T) {
+	ascii := ASCIIStyles()
+	frames := []string{"|", "/", "-", "\\", "|"}
+	for tick, want := range frames {
+		if got := ascii.SpinnerFrame(tick); got != want {
+			t.Errorf("SpinnerFrame(%d) = %q, want %q", tick, got, want)
+		}
+	}
+
+	if got := (Styles{}).SpinnerFrame(1); got != SpinnerFrames[1] {
+		t.Errorf("expected fallback to SpinnerFrames, got %q", got)
+	}
+}
+
+func TestZeroGlyphsFallBack(t *testing.T) {
+	styles := DefaultStyles()
+	styles.Glyphs = Glyphs{Arrow: ">"}
+
+	got := styles.glyphs()
+	want := UnicodeGlyphs
+	want.Arrow = ">"
+	if got != want {
+		t.Errorf("glyphs() = %+v, want %+v", got, want)
+	}
+
+	status := NewLicenseStatus(nil, LicenseStatusConfig{ShowFeatures: true, Styles: &Styles{}})
+	status.Update(LicenseCheckedMsg{Result: goldenStatusResult()})
+	view := status.View()
+	if !strings.Contains(view, CheckMark) || !strings.Contains(view, BulletPoint) {
+		t.Errorf("expected default glyphs in zero-styles view, got %q", view)
+	}
+
+	ascii := ASCIIStyles()
+	ascii.Glyphs = Glyphs{}
+	if got := ascii.glyphs(); got != ASCIIGlyphs {
+		t.Errorf("expected ASCII styles to fall back to ASCIIGlyphs, got %+v", got)
+	}
+}
+
+func TestASCIIStylesGolden(t *testing.T) {
+	for _, ascii := range []bool{false, true} {
+		styles := DefaultStyles()
+		suffix := "unicode"
+		if ascii {
+			styles = ASCIIStyles()
+			suffix = "ascii"
+		}
+
+		status := NewLicenseStatus(nil, LicenseStatusConfig{ShowFeatures: true, ShowExpiry: true, Styles: &styles})
+		status.Update(LicenseCheckedMsg{Result: goldenStatusResult()})
+		assertGolden(t, "glyphs_status_"+suffix, status.View())
+
+		flow := NewPurchaseFlow(nil, PurchaseFlowConfig{Styles: &styles})
+		flow.setStep(PurchaseStepCreating)
+		flow.Update(CheckoutSessionCreatedMsg{Error: errors.New("card declined")})
+		view := flow.View()
+		assertGolden(t, "glyphs_purchase_error_"+suffix, view)
+
+		if ascii {
+			for _, r := range view + status.View() {
+				if r > 127 {
+					t.Errorf("expected ASCII-only output, found %q", r)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestDetectStyles(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_CTYPE", "")
+	t.Setenv("WT_SESSION", "1")
+
+	t.Setenv("LANG", "en_US.UTF-8")
+	if DetectStyles().ASCII {
+		t.Error("expected Unicode styles for a UTF-8 locale")
+	}
+
+	t.Setenv("LANG", "C")
+	if !DetectStyles().ASCII {
+		t.Error("expected ASCII styles for the C locale")
+	}
+
+	t.Setenv("LANG", "en_US.UTF-8")
+	t.Setenv("TERM", "dumb")
+	if !DetectStyles().ASCII {
+		t.Error("expected ASCII styles for a dumb terminal")
+	}
+}
+
+func TestStylesWithDoesNotMutateOriginal(t *testing.T) {
+	original := DefaultStyles()
+	originalError := original.Error.GetForeground()
+
+	derived := original.With(func(s *Styles) {
+		s.Error = s.Error.Foreground(lipgloss.Color("#FF8800"))
+		s.Glyphs.Check = "+"
+		s.Spinner[0] = "*"
+	})
+
+	if derived.Error.GetForeground() != lipgloss.Color("#FF8800") {
+		t.Errorf("expected derived error color to change, got %v", derived.Error.GetForeground())
+	}
+	if original.Error.GetForeground() != originalError {
+		t.Errorf("original error color changed to %v", original.Error.GetForeground())
+	}
+	if original.Glyphs.Check != CheckMark {
+		t.Errorf("original check glyph changed to %q", original.Glyphs.Check)
+	}
+	if original.Spinner[0] == "*" || SpinnerFrames[0] == "*" {
+		t.Error("expected spinner frames to be copied, not shared")
+	}
+}
+
+func TestStylesClone(t *testing.T) {
+	original := ASCIIStyles()
+	clone := original.Clone()
+	clone.Spinner[1] = "?"
+	clone.ProgressFull = "="
+
+	if original.Spinner[1] == "?" || original.ProgressFull == "=" {
+		t.Error("expected clone changes not to affect the original")
+	}
+}
+
+func TestRenderFeatureBadges(t *testing.T) {
+	features := []string{"pro", "export", "analytics"}
+	styles := DefaultStyles()
+
+	if got := RenderFeatureBadges(features, styles); got != "[pro] [export] [analytics]" {
+		t.Errorf("unexpected badge row %q", got)
+	}
+
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{30, "[pro] [export] [analytics]"},
+		{26, "[pro] [export] [analytics]"},
+		{22, "[pro] [export] +1 more"},
+		{21, "[pro] +2 more"},
+		{5, "+3 more"},
+	}
+	for _, tt := range tests {
+		got := renderFeatureBadges(features, tt.width, styles.StatusValid, styles.Muted)
+		if got != tt.want {
+			t.Errorf("width %d: expected %q, got %q", tt.width, tt.want, got)
+		}
+		if w := lipgloss.Width(got); w > tt.width && tt.width >= len("+3 more") {
+			t.Errorf("width %d: badge row is %d columns wide", tt.width, w)
+		}
+	}
+}
+
+func TestRenderFeatureBadgesFitWithoutSummary(t *testing.T) {
+	styles := DefaultStyles()
+
+	// The room "+1 more" would need isn't needed when every badge fits
+	if got := renderFeatureBadges([]string{"a", "b"}, 7, styles.StatusValid, styles.Muted); got != "[a] [b]" {
+		t.Errorf("expected both badges at width 7, got %q", got)
+	}
+}
+
+func TestListItemActiveDefault(t *testing.T) {
+	styles := DefaultStyles()
+
+	// The inverted bar belongs to FullWidthHighlight, not the shared style
+	if _, ok := styles.ListItemActive.GetBackground().(lipgloss.NoColor); !ok {
+		t.Errorf("expected ListItemActive to have no background, got %v", styles.ListItemActive.GetBackground())
+	}
+	if styles.ListItemActive.GetForeground() != styles.Theme.Primary {
+		t.Errorf("expected ListItemActive in the primary color, got %v", styles.ListItemActive.GetForeground())
+	}
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// TerminalMode controls whether components render for an interactive
+// terminal or as plain text for CI logs and piped output.
+type TerminalMode int
+
+const (
+	// TerminalAuto renders plain text when stdout is not a terminal. It is
+	// the default, so components rendered to a pipe or file no longer draw
+	// QR codes and animations unless TerminalInteractive is set.
+	TerminalAuto TerminalMode = iota
+
+	// TerminalInteractive always renders QR codes and animations.
+	TerminalInteractive
+
+	// TerminalHeadless always renders plain text without animation.
+	TerminalHeadless
+)
+
+// stdoutIsTerminal reports whether stdout is attached to a terminal.
+func stdoutIsTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// headless reports whether components should render plain text.
+func (t TerminalMode) headless() bool {
+	switch t {
+	case TerminalInteractive:
+		return false
+	case TerminalHeadless:
+		return true
+	default:
+		return !stdoutIsTerminal()
+	}
+}
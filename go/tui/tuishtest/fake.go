@@ -0,0 +1,264 @@
+// Package tuishtest provides a scripted stand-in for the tuish SDK, for
+// testing Bubble Tea models built on the tui components without a server
+// or license storage.
+//
+//	fake := tuishtest.NewFakeSDK(tuishtest.ValidResult("pro"))
+//	gate := tui.NewLicenseGate(fake, child)
+//	...
+//	fake.SetResult(tuishtest.InvalidResult(tuish.ReasonRevoked))
+package tuishtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	tuish "github.com/tuishdotdev/tuish/go"
+)
+
+// ErrNoSession is returned by GetCheckoutStatus when no checkout session
+// was scripted.
+var ErrNoSession = errors.New("tuishtest: no checkout session")
+
+// FakeSDK implements tui.LicenseChecker with scripted results. It is safe
+// for use from concurrent commands.
+type FakeSDK struct {
+	mu sync.Mutex
+
+	results  []*tuish.LicenseCheckResult
+	checkErr error
+	checks   int
+
+	licenseKey string
+	licenses   map[string]*tuish.LicenseDetails
+
+	// Session is returned by PurchaseInBrowser.
+	Session *tuish.CheckoutSessionResult
+
+	// Statuses are returned by successive GetCheckoutStatus calls; the last
+	// one repeats.
+	Statuses []*tuish.CheckoutStatus
+
+	// PurchaseInit is returned by InitTerminalPurchase.
+	PurchaseInit *tuish.PurchaseInitResult
+
+	// Fingerprint is returned by GetMachineFingerprint.
+	Fingerprint string
+
+	// IdentityToken is returned by HasIdentityToken.
+	IdentityToken bool
+}
+
+// NewFakeSDK returns a FakeSDK whose CheckLicense returns results in order,
+// repeating the last one. With no results, it reports no license.
+func NewFakeSDK(results ...*tuish.LicenseCheckResult) *FakeSDK {
+	return &FakeSDK{
+		results:     results,
+		licenses:    make(map[string]*tuish.LicenseDetails),
+		Fingerprint: "fake-fingerprint",
+	}
+}
+
+// ValidResult returns a valid license check result with the given features.
+func ValidResult(features ...string) *tuish.LicenseCheckResult {
+	if features == nil {
+		features = []string{}
+	}
+	return &tuish.LicenseCheckResult{
+		Valid:           true,
+		OfflineVerified: true,
+		Source:          tuish.LicenseSourceOffline,
+		License: &tuish.LicenseDetails{
+			ID:        "lic_fake",
+			ProductID: "prod_fake",
+			Features:  features,
+			Status:    tuish.LicenseStatusActive,
+			IssuedAt:  time.Now().UnixMilli(),
+		},
+	}
+}
+
+// InvalidResult returns an invalid license check result with the given reason.
+func InvalidResult(reason tuish.LicenseInvalidReason) *tuish.LicenseCheckResult {
+	result := &tuish.LicenseCheckResult{
+		Valid:  false,
+		Reason: reason,
+		Source: tuish.LicenseSourceOffline,
+	}
+	if reason == tuish.ReasonNotFound {
+		result.Source = tuish.LicenseSourceNotFound
+	}
+	return result
+}
+
+// SetResult makes every later CheckLicense call return result.
+func (f *FakeSDK) SetResult(result *tuish.LicenseCheckResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results = []*tuish.LicenseCheckResult{result}
+	f.checkErr = nil
+}
+
+// SetError makes every later CheckLicense call fail with err.
+func (f *FakeSDK) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checkErr = err
+}
+
+// AddLicense registers a license key that StoreLicense and PreviewLicense
+// accept, with the details it grants.
+func (f *FakeSDK) AddLicense(licenseKey string, details *tuish.LicenseDetails) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.licenses[licenseKey] = details
+}
+
+// Checks returns how many times CheckLicense has been called.
+func (f *FakeSDK) Checks() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.checks
+}
+
+// CheckLicense returns the next scripted result. Storing a registered
+// license makes it return that license as valid until it is cleared.
+func (f *FakeSDK) CheckLicense(ctx context.Context) (*tuish.LicenseCheckResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.checks++
+	if f.checkErr != nil {
+		return nil, f.checkErr
+	}
+	if details, ok := f.licenses[f.licenseKey]; ok {
+		return &tuish.LicenseCheckResult{
+			Valid:           true,
+			License:         details,
+			OfflineVerified: true,
+			Source:          tuish.LicenseSourceOffline,
+		}, nil
+	}
+	if len(f.results) == 0 {
+		return InvalidResult(tuish.ReasonNotFound), nil
+	}
+
+	result := f.results[0]
+	if len(f.results) > 1 {
+		f.results = f.results[1:]
+	}
+	return result, nil
+}
+
+// StoreLicense stores a license key registered with AddLicense.
+func (f *FakeSDK) StoreLicense(licenseKey string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.licenses[licenseKey]; !ok {
+		return tuish.ErrInvalidFormat
+	}
+	f.licenseKey = licenseKey
+	return nil
+}
+
+// ClearLicense clears the stored license key.
+func (f *FakeSDK) ClearLicense() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.licenseKey = ""
+	return nil
+}
+
+// GetCachedLicenseKey returns the stored license key.
+func (f *FakeSDK) GetCachedLicenseKey() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.licenseKey
+}
+
+// GetCachedLicense returns a cache entry for the stored license key, or nil.
+func (f *FakeSDK) GetCachedLicense() (*tuish.CachedLicenseData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.licenseKey == "" {
+		return nil, nil
+	}
+	now := time.Now()
+	return &tuish.CachedLicenseData{
+		LicenseKey:         f.licenseKey,
+		CachedAt:           now.UnixMilli(),
+		RefreshAt:          now.Add(24 * time.Hour).UnixMilli(),
+		ProductID:          f.licenses[f.licenseKey].ProductID,
+		MachineFingerprint: f.Fingerprint,
+	}, nil
+}
+
+// ExtractLicenseInfo returns the details registered for licenseKey.
+func (f *FakeSDK) ExtractLicenseInfo(licenseKey string) (*tuish.LicenseDetails, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	details, ok := f.licenses[licenseKey]
+	if !ok {
+		return nil, tuish.ErrInvalidFormat
+	}
+	return details, nil
+}
+
+// PreviewLicense accepts license keys registered with AddLicense, unless
+// their details say they have expired.
+func (f *FakeSDK) PreviewLicense(licenseKey string) (*tuish.LicenseDetails, *tuish.VerifyResult, error) {
+	details, err := f.ExtractLicenseInfo(licenseKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if details.Status == tuish.LicenseStatusExpired {
+		return details, &tuish.VerifyResult{Valid: false, Reason: tuish.ReasonExpired}, nil
+	}
+	return details, &tuish.VerifyResult{Valid: true}, nil
+}
+
+// PurchaseInBrowser returns Session without opening a browser.
+func (f *FakeSDK) PurchaseInBrowser(ctx context.Context, email string) (*tuish.CheckoutSessionResult, error) {
+	if f.Session == nil {
+		return nil, ErrNoSession
+	}
+	return f.Session, nil
+}
+
+// GetCheckoutStatus returns the next scripted status.
+func (f *FakeSDK) GetCheckoutStatus(ctx context.Context, sessionID string) (*tuish.CheckoutStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.Statuses) == 0 {
+		return nil, ErrNoSession
+	}
+	status := f.Statuses[0]
+	if len(f.Statuses) > 1 {
+		f.Statuses = f.Statuses[1:]
+	}
+	return status, nil
+}
+
+// InitTerminalPurchase returns PurchaseInit.
+func (f *FakeSDK) InitTerminalPurchase(ctx context.Context) (*tuish.PurchaseInitResult, error) {
+	if f.PurchaseInit == nil {
+		return nil, errors.New("tuishtest: no purchase scripted")
+	}
+	return f.PurchaseInit, nil
+}
+
+// GetMachineFingerprint returns Fingerprint.
+func (f *FakeSDK) GetMachineFingerprint() string {
+	return f.Fingerprint
+}
+
+// StorageDir returns "", as for a custom store.
+func (f *FakeSDK) StorageDir() string {
+	return ""
+}
+
+// HasIdentityToken returns IdentityToken.
+func (f *FakeSDK) HasIdentityToken() bool {
+	return f.IdentityToken
+}
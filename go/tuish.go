@@ -33,18 +33,32 @@ import (
 	"crypto/ed25519"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 )
 
+// Version is the version of the Go SDK.
+const Version = "0.1.0"
+
 // SDK is the main entry point for the tuish SDK.
 type SDK struct {
 	config             Config
 	client             *Client
-	storage            *Storage
+	storage            LicenseStore
 	publicKey          ed25519.PublicKey
+	fingerprintMu      sync.Mutex
 	machineFingerprint string
+	revoked            map[string]bool
+	verified           *verifyCache
+	logger             func(format string, args ...any)
+
+	// noEnvLicense ignores Config.LicenseKeyEnv, for the SDKs CheckProducts
+	// derives for other products
+	noEnvLicense bool
 }
 
 // New creates a new tuish SDK instance.
@@ -65,31 +79,184 @@ func New(config Config) (*SDK, error) {
 		config.APIBaseURL = defaultAPIURL
 	}
 
+	revoked := make(map[string]bool)
+	for _, id := range config.RevokedLicenseIDs {
+		revoked[id] = true
+	}
+	if config.RevocationListFile != "" {
+		list, err := loadRevocationList(config.RevocationListFile, publicKey)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range list.LicenseIDs {
+			revoked[id] = true
+		}
+	}
+
 	sdk := &SDK{
 		config:    config,
 		client:    NewClient(config.APIBaseURL, config.APIKey, config.Debug),
-		storage:   NewStorage(config.StorageDir, config.Debug),
+		storage:   config.Store,
 		publicKey: publicKey,
+		revoked:   revoked,
+		verified:  newVerifyCache(),
+	}
+
+	sdk.client.SetAPIVersion(config.APIVersion)
+
+	var fileStorage *Storage
+	if sdk.storage == nil {
+		storageDir := config.StorageDir
+		if dir := os.Getenv(StorageDirEnv); dir != "" {
+			storageDir = dir
+		}
+		fileStorage = NewStorage(storageDir, config.Debug)
+		fileStorage.legacyFilenames = config.LegacyCacheFilenames
+		fileStorage.namespace = config.CacheNamespace
+		sdk.storage = fileStorage
+	}
+
+	if config.Debug {
+		logger := config.Logger
+		if logger == nil {
+			logger = defaultLogger
+		}
+		sdk.logger = logger
+		sdk.client.logger = logger
+		if fileStorage != nil {
+			fileStorage.logger = logger
+		}
 	}
 
 	return sdk, nil
 }
 
+// logf writes a debug line if logging is enabled.
+func (s *SDK) logf(format string, args ...any) {
+	if s.logger != nil {
+		s.logger(format, args...)
+	}
+}
+
 // GetMachineFingerprint returns the machine fingerprint (cached after first call).
+// It is safe for concurrent use.
 func (s *SDK) GetMachineFingerprint() string {
+	s.fingerprintMu.Lock()
+	defer s.fingerprintMu.Unlock()
 	if s.machineFingerprint == "" {
 		s.machineFingerprint = GetMachineFingerprint()
 	}
 	return s.machineFingerprint
 }
 
+// WarmFingerprint computes the machine fingerprint in the background, so the
+// hostname and user lookups, which can block on slow NSS or LDAP setups, are
+// done by the first license check. Call it right after New, or from a
+// Bubble Tea Init.
+func (s *SDK) WarmFingerprint() {
+	go s.GetMachineFingerprint()
+}
+
+// onlineMode controls when a license check contacts the server.
+type onlineMode int
+
+const (
+	onlineWhenStale onlineMode = iota
+	onlineForce
+	onlineSkip
+)
+
+// checkOptions holds the settings for CheckLicenseWith.
+type checkOptions struct {
+	online onlineMode
+}
+
+// CheckOption configures a single CheckLicenseWith call.
+type CheckOption func(*checkOptions)
+
+// ForceOnline revalidates a valid license with the server even if the cache
+// is fresh, e.g. after the network comes back. If the server can't be
+// reached the offline result is returned with StaleOffline set.
+func ForceOnline() CheckOption {
+	return func(o *checkOptions) {
+		o.online = onlineForce
+	}
+}
+
+// SkipOnline verifies offline only, without contacting the server even if
+// the cache is due for a refresh. Useful for fast startup.
+func SkipOnline() CheckOption {
+	return func(o *checkOptions) {
+		o.online = onlineSkip
+	}
+}
+
 // CheckLicense checks if the user has a valid license.
 // Performs offline verification first, then online validation if needed.
 func (s *SDK) CheckLicense(ctx context.Context) (*LicenseCheckResult, error) {
+	return s.CheckLicenseWith(ctx)
+}
+
+// CheckLicenseWith is CheckLicense with per-call options such as
+// ForceOnline and SkipOnline. If both are given, the last one wins.
+func (s *SDK) CheckLicenseWith(ctx context.Context, opts ...CheckOption) (*LicenseCheckResult, error) {
+	var o checkOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	result, err := s.checkLicense(ctx, o)
+	if err == nil {
+		s.reportCheck(result)
+	}
+	return result, err
+}
+
+// reportCheck passes a final check result to Config.OnCheck.
+func (s *SDK) reportCheck(result *LicenseCheckResult) {
+	if result != nil && s.config.OnCheck != nil {
+		report := *result
+		s.config.OnCheck(&report, string(checkSource(result)))
+	}
+}
+
+// checkSource returns where a check result came from, for OnCheck.
+func checkSource(result *LicenseCheckResult) LicenseSource {
+	switch {
+	case result.Source != "":
+		return result.Source
+	case result.OfflineVerified:
+		return LicenseSourceOffline
+	default:
+		return LicenseSourceOnline
+	}
+}
+
+// checkLicense performs a license check with the given options.
+func (s *SDK) checkLicense(ctx context.Context, o checkOptions) (result *LicenseCheckResult, err error) {
 	machineFingerprint := s.GetMachineFingerprint()
+	if s.config.AlwaysOnline && o.online != onlineSkip {
+		o.online = onlineForce
+	}
+	if s.config.DisableOnline {
+		o.online = onlineSkip
+	}
+
+	// Record whether the check made a validation request, whatever its outcome
+	networkChecked := false
+	validate := func(licenseKey string) (*LicenseCheckResult, error) {
+		networkChecked = true
+		return s.validateOnline(ctx, licenseKey, machineFingerprint)
+	}
+	defer func() {
+		if result != nil {
+			result.NetworkChecked = networkChecked
+		}
+	}()
 
 	// Try to load cached license
-	cached, err := s.storage.Load(s.config.ProductID)
+	store := s.checkStore(machineFingerprint)
+	cached, err := store.Load(s.config.ProductID)
 	if err != nil {
 		return nil, fmt.Errorf("load cached license: %w", err)
 	}
@@ -97,66 +264,331 @@ func (s *SDK) CheckLicense(ctx context.Context) (*LicenseCheckResult, error) {
 	if cached != nil {
 		// Verify offline first
 		offlineResult := s.verifyOffline(cached.LicenseKey, machineFingerprint)
+		s.logf("offline verification: valid=%t reason=%s", offlineResult.Valid, offlineResult.Reason)
 
 		if offlineResult.Valid {
-			// If cache is fresh, return offline result
-			if !cached.NeedsRefresh() {
+			tooOld := s.offlineAgeExceeded(cached, offlineResult)
+
+			// If cache is fresh (or the network is off limits), return
+			// offline result
+			if !tooOld && (o.online == onlineSkip || (o.online != onlineForce && !cached.NeedsRefresh())) {
 				return offlineResult, nil
 			}
 
+			// fallback is the result when the server can't confirm the license
+			fallback := func() *LicenseCheckResult {
+				if tooOld {
+					s.logf("no online validation for over %s", s.config.MaxOfflineAge)
+					offlineResult.Valid = false
+					offlineResult.Reason = ReasonOfflineExpired
+					return offlineResult
+				}
+				offlineResult.StaleOffline = true
+				return offlineResult
+			}
+			if o.online == onlineSkip {
+				return fallback(), nil
+			}
+
 			// Try online refresh
-			onlineResult, err := s.validateOnline(ctx, cached.LicenseKey, machineFingerprint)
+			onlineResult, err := validate(cached.LicenseKey)
 			if err != nil {
 				// Network error, trust offline result
-				return offlineResult, nil
+				return fallback(), nil
 			}
 
 			if onlineResult.Valid {
 				// Update cache with fresh timestamp
-				s.storage.Save(s.config.ProductID, cached.LicenseKey, machineFingerprint)
+				store.Save(s.config.ProductID, cached.LicenseKey, machineFingerprint)
 				return onlineResult, nil
 			}
 
 			// License was revoked or otherwise invalidated server-side
 			if onlineResult.Reason != ReasonNetworkError {
-				s.storage.Remove(s.config.ProductID)
+				store.Remove(s.config.ProductID)
 				return onlineResult, nil
 			}
 
 			// Network error, trust offline result
-			return offlineResult, nil
+			return fallback(), nil
 		}
 
 		// Offline verification failed
+		if offlineResult.Reason == ReasonExpired && o.online == onlineSkip {
+			return offlineResult, nil
+		}
 		if offlineResult.Reason == ReasonExpired {
 			// Check online in case there's a renewed license
-			onlineResult, err := s.validateOnline(ctx, cached.LicenseKey, machineFingerprint)
+			onlineResult, err := validate(cached.LicenseKey)
 			if err != nil {
-				s.storage.Remove(s.config.ProductID)
+				// A cancelled check learned nothing about a renewal
+				if ctx.Err() == nil {
+					store.Remove(s.config.ProductID)
+				}
 				return offlineResult, nil
 			}
 			if !onlineResult.Valid {
-				s.storage.Remove(s.config.ProductID)
+				store.Remove(s.config.ProductID)
 			}
 			return onlineResult, nil
 		}
 
-		// Other offline failures (signature, format, machine mismatch)
-		s.storage.Remove(s.config.ProductID)
+		// A license bound to another machine is kept for RebindMachine.
+		// This is a Go exception to spec/flow.md, which removes it.
+		if offlineResult.Reason == ReasonMachineMismatch {
+			return offlineResult, nil
+		}
+
+		// Other offline failures (signature, format or product mismatch)
+		store.Remove(s.config.ProductID)
 		return offlineResult, nil
 	}
 
-	// No cached license
+	// No cached license, so fall back to a local trial if one was started
+	if trial := s.checkTrial(machineFingerprint); trial != nil {
+		s.logf("trial check: valid=%t reason=%s", trial.Valid, trial.Reason)
+		return trial, nil
+	}
+
 	return &LicenseCheckResult{
 		Valid:           false,
 		Reason:          ReasonNotFound,
 		OfflineVerified: false,
+		Source:          LicenseSourceNotFound,
 	}, nil
 }
 
+// checkStore returns the store a license check works from. A license key in
+// the Config.LicenseKeyEnv environment variable is used when nothing is
+// cached, or over the cache with Config.EnvLicensePrecedence. Unless
+// Config.CacheEnvLicense is set and it verifies offline, it is kept in
+// memory, so the check never writes to disk.
+func (s *SDK) checkStore(machineFingerprint string) LicenseStore {
+	if s.noEnvLicense {
+		return s.storage
+	}
+
+	name, envKey := s.envLicense()
+	if envKey == "" {
+		return s.storage
+	}
+
+	cached, err := s.storage.Load(s.config.ProductID)
+	if err == nil && cached != nil && (!s.config.EnvLicensePrecedence || cached.LicenseKey == envKey) {
+		return s.storage
+	}
+
+	s.logf("using license from %s", name)
+	// Only a license that verifies replaces the cache; an invalid one is
+	// checked from memory so the failure can't remove the cached license
+	if s.config.CacheEnvLicense && s.verifyOffline(envKey, machineFingerprint).Valid {
+		err := s.storage.Save(s.config.ProductID, envKey, machineFingerprint)
+		if err == nil {
+			return s.storage
+		}
+		s.logf("cache license from %s: %v", name, err)
+	}
+
+	store := NewMemoryStore()
+	store.Save(s.config.ProductID, envKey, machineFingerprint)
+	return store
+}
+
+// envLicense returns the name of the Config.LicenseKeyEnv variable and the
+// license key it holds, if any.
+func (s *SDK) envLicense() (name, licenseKey string) {
+	name = s.config.LicenseKeyEnv
+	if name == "" {
+		name = DefaultLicenseKeyEnv
+	}
+	return name, strings.TrimSpace(os.Getenv(name))
+}
+
+// offlineAgeExceeded reports whether the cached license was last confirmed
+// (stored or validated online) longer ago than Config.MaxOfflineAge.
+// Perpetual licenses are exempt when Config.OfflineOnly is set.
+func (s *SDK) offlineAgeExceeded(cached *CachedLicenseData, result *LicenseCheckResult) bool {
+	if s.config.MaxOfflineAge <= 0 {
+		return false
+	}
+	if s.config.OfflineOnly && result.License != nil && result.License.ExpiresAt == nil {
+		return false
+	}
+	return nowFunc().Sub(time.UnixMilli(cached.CachedAt)) > s.config.MaxOfflineAge
+}
+
+// FeatureSet checks the license and returns its features as a set, so
+// callers can test membership with set["export"]. Feature names are trimmed
+// and deduplicated. The set is empty (never nil) if the license is invalid.
+func (s *SDK) FeatureSet(ctx context.Context) (map[string]bool, error) {
+	result, err := s.CheckLicense(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool)
+	if !result.Valid || result.License == nil {
+		return set, nil
+	}
+
+	for _, feature := range result.License.Features {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			set[feature] = true
+		}
+	}
+	return set, nil
+}
+
+// HasFeatureCtx reports whether the license grants feature. It checks the
+// license as CheckLicense does, so a fresh cache costs no round trip and
+// Config.AlwaysOnline is honored. If the feature is missing and the check
+// didn't reach the server, the license is revalidated online in case the
+// feature was added or the license renewed since it was cached. OnCheck
+// sees only the final result.
+func (s *SDK) HasFeatureCtx(ctx context.Context, feature string) (bool, error) {
+	result, err := s.checkLicense(ctx, checkOptions{})
+	if err == nil && !grantsFeature(result, feature) && !result.NetworkChecked {
+		result, err = s.checkLicense(ctx, checkOptions{online: onlineForce})
+	}
+	if err != nil {
+		return false, err
+	}
+	s.reportCheck(result)
+	return grantsFeature(result, feature), nil
+}
+
+// grantsFeature reports whether result is valid and lists feature.
+func grantsFeature(result *LicenseCheckResult, feature string) bool {
+	if !result.Valid || result.License == nil {
+		return false
+	}
+	for _, f := range result.License.Features {
+		if strings.TrimSpace(f) == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffFeatures compares the features a license has with the ones it should
+// have, e.g. after a plan upgrade. missing lists features in want but not in
+// have, and extra lists features in have but not in want, each in input
+// order without duplicates. Feature names are trimmed before comparing.
+func DiffFeatures(have, want []string) (missing, extra []string) {
+	return featuresNotIn(want, have), featuresNotIn(have, want)
+}
+
+// featuresNotIn returns the features of from that aren't in exclude.
+func featuresNotIn(from, exclude []string) []string {
+	skip := make(map[string]bool, len(exclude)+len(from))
+	for _, f := range exclude {
+		skip[strings.TrimSpace(f)] = true
+	}
+
+	var out []string
+	for _, f := range from {
+		if f = strings.TrimSpace(f); f != "" && !skip[f] {
+			skip[f] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// ErrOnlineDisabled is returned by ValidateOnline when Config.DisableOnline
+// is set.
+var ErrOnlineDisabled = errors.New("online validation disabled")
+
+// ValidateOnline asks the server whether the cached license is still valid
+// without touching the cache. Unlike CheckLicense it never saves or removes
+// the cached license, so a transient failure can't clobber a working offline
+// cache. On a network failure the result has ReasonNetworkError and the
+// error is returned alongside it.
+func (s *SDK) ValidateOnline(ctx context.Context) (*LicenseCheckResult, error) {
+	if s.config.DisableOnline {
+		return nil, ErrOnlineDisabled
+	}
+
+	cached, err := s.storage.Load(s.config.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("load cached license: %w", err)
+	}
+
+	if cached == nil {
+		return &LicenseCheckResult{
+			Valid:           false,
+			Reason:          ReasonNotFound,
+			OfflineVerified: false,
+			Source:          LicenseSourceNotFound,
+		}, nil
+	}
+
+	result, err := s.validateOnline(ctx, cached.LicenseKey, s.GetMachineFingerprint())
+	result.NetworkChecked = true
+	if err != nil {
+		return result, fmt.Errorf("validate license online: %w", err)
+	}
+	return result, nil
+}
+
 // verifyOffline verifies a license offline using the public key.
+// With Config.IgnoreMachineBinding the license's machine ID isn't checked.
 func (s *SDK) verifyOffline(licenseKey, machineFingerprint string) *LicenseCheckResult {
-	result := VerifyLicense(licenseKey, s.publicKey, machineFingerprint)
+	if s.config.IgnoreMachineBinding {
+		machineFingerprint = ""
+	}
+	result := s.verifyOfflineResult(licenseKey, machineFingerprint)
+	s.nameProduct(result.License)
+	return result
+}
+
+// nameProduct fills in Config.ProductName on a license for this product
+// that has no name from the API.
+func (s *SDK) nameProduct(license *LicenseDetails) {
+	if license != nil && license.ProductName == "" && license.ProductID == s.config.ProductID {
+		license.ProductName = s.config.ProductName
+	}
+}
+
+func (s *SDK) verifyOfflineResult(licenseKey, machineFingerprint string) *LicenseCheckResult {
+	result := s.verified.verify(licenseKey, s.publicKey, machineFingerprint)
+
+	// A correctly signed license for another product is not valid here
+	if result.Valid && result.Payload != nil && result.Payload.ProductID != s.config.ProductID {
+		s.logf("cached license is for %s, not %s", result.Payload.ProductID, s.config.ProductID)
+		return &LicenseCheckResult{
+			Valid:  false,
+			Reason: ReasonProductMismatch,
+			License: &LicenseDetails{
+				ID:        result.Payload.LicenseID,
+				ProductID: result.Payload.ProductID,
+				Features:  result.Payload.Features,
+				Status:    LicenseStatusInvalid,
+				IssuedAt:  result.Payload.IssuedAt,
+				ExpiresAt: result.Payload.ExpiresAt,
+			},
+			OfflineVerified: true,
+			Source:          LicenseSourceOffline,
+		}
+	}
+
+	if result.Valid && result.Payload != nil && s.revoked[result.Payload.LicenseID] {
+		s.logf("license %s is on the revocation list", result.Payload.LicenseID)
+		return &LicenseCheckResult{
+			Valid:  false,
+			Reason: ReasonRevoked,
+			License: &LicenseDetails{
+				ID:        result.Payload.LicenseID,
+				ProductID: result.Payload.ProductID,
+				Features:  result.Payload.Features,
+				Status:    LicenseStatusRevoked,
+				IssuedAt:  result.Payload.IssuedAt,
+				ExpiresAt: result.Payload.ExpiresAt,
+			},
+			OfflineVerified: true,
+			Source:          LicenseSourceOffline,
+		}
+	}
 
 	if result.Valid && result.Payload != nil {
 		return &LicenseCheckResult{
@@ -170,20 +602,17 @@ func (s *SDK) verifyOffline(licenseKey, machineFingerprint string) *LicenseCheck
 				ExpiresAt: result.Payload.ExpiresAt,
 			},
 			OfflineVerified: true,
+			Source:          LicenseSourceOffline,
 		}
 	}
 
 	var license *LicenseDetails
 	if result.Payload != nil {
-		status := LicenseStatusRevoked
-		if result.Reason == ReasonExpired {
-			status = LicenseStatusExpired
-		}
 		license = &LicenseDetails{
 			ID:        result.Payload.LicenseID,
 			ProductID: result.Payload.ProductID,
 			Features:  result.Payload.Features,
-			Status:    status,
+			Status:    statusForReason(result.Reason),
 			IssuedAt:  result.Payload.IssuedAt,
 			ExpiresAt: result.Payload.ExpiresAt,
 		}
@@ -194,33 +623,42 @@ func (s *SDK) verifyOffline(licenseKey, machineFingerprint string) *LicenseCheck
 		Reason:          result.Reason,
 		License:         license,
 		OfflineVerified: true,
+		Source:          LicenseSourceOffline,
 	}
 }
 
 // validateOnline validates a license online with the API.
 func (s *SDK) validateOnline(ctx context.Context, licenseKey, machineFingerprint string) (*LicenseCheckResult, error) {
+	s.logf("online validation for %s", RedactLicenseKey(licenseKey))
 	result, err := s.client.ValidateLicense(ctx, licenseKey, machineFingerprint)
 	if err != nil {
+		s.logf("online validation failed: %v", err)
 		return &LicenseCheckResult{
 			Valid:           false,
 			Reason:          ReasonNetworkError,
 			OfflineVerified: false,
+			Source:          LicenseSourceOnline,
 		}, err
 	}
 
+	s.nameProduct(result.License)
 	if result.Valid && result.License != nil {
+		s.logf("online validation: valid=true")
 		return &LicenseCheckResult{
 			Valid:           true,
 			License:         result.License,
 			OfflineVerified: false,
+			Source:          LicenseSourceOnline,
 		}, nil
 	}
 
+	s.logf("online validation: valid=%t reason=%s", result.Valid, result.Reason)
 	return &LicenseCheckResult{
 		Valid:           false,
 		Reason:          LicenseInvalidReason(result.Reason),
 		License:         result.License,
 		OfflineVerified: false,
+		Source:          LicenseSourceOnline,
 	}, nil
 }
 
@@ -232,66 +670,185 @@ func (s *SDK) PurchaseInBrowser(ctx context.Context, email string) (*CheckoutSes
 	}
 
 	// Try to open browser
-	if err := openURL(session.CheckoutURL); err != nil {
+	if err := openBrowser(session.CheckoutURL); err != nil {
 		// Don't fail if browser can't be opened, just return the URL
 	}
 
 	return session, nil
 }
 
-// WaitForCheckoutComplete polls for checkout completion.
-func (s *SDK) WaitForCheckoutComplete(ctx context.Context, sessionID string, pollInterval, timeout time.Duration) (*LicenseCheckResult, error) {
-	if pollInterval == 0 {
-		pollInterval = 2 * time.Second
+// maxPollBackoff caps the delay between checkout polls after failed requests.
+const maxPollBackoff = 30 * time.Second
+
+// checkoutWaitOptions holds the settings for WaitForCheckout.
+type checkoutWaitOptions struct {
+	pollInterval    time.Duration
+	timeout         time.Duration
+	callbackTimeout time.Duration
+	onStatus        func(status string)
+
+	// immediate polls once straight away instead of after pollInterval
+	immediate bool
+}
+
+// newCheckoutWaitOptions applies opts over the defaults.
+func newCheckoutWaitOptions(opts []CheckoutWaitOption) checkoutWaitOptions {
+	o := checkoutWaitOptions{
+		pollInterval:    2 * time.Second,
+		timeout:         10 * time.Minute,
+		callbackTimeout: 5 * time.Minute,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// CheckoutWaitOption configures WaitForCheckout.
+type CheckoutWaitOption func(*checkoutWaitOptions)
+
+// WithPollInterval sets the delay between checkout polls (default: 2s).
+func WithPollInterval(interval time.Duration) CheckoutWaitOption {
+	return func(o *checkoutWaitOptions) {
+		if interval > 0 {
+			o.pollInterval = interval
+		}
 	}
-	if timeout == 0 {
-		timeout = 10 * time.Minute
+}
+
+// WithTimeout sets how long to wait for the checkout (default: 10m).
+func WithTimeout(timeout time.Duration) CheckoutWaitOption {
+	return func(o *checkoutWaitOptions) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
 	}
+}
 
-	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
+// WithOnStatus registers a callback that receives the checkout status
+// ("pending", "complete", "expired") after each successful poll.
+func WithOnStatus(fn func(status string)) CheckoutWaitOption {
+	return func(o *checkoutWaitOptions) {
+		o.onStatus = fn
+	}
+}
+
+// WaitForCheckoutComplete polls for checkout completion. A zero pollInterval
+// or timeout uses the default. It is equivalent to WaitForCheckout with
+// WithPollInterval and WithTimeout.
+func (s *SDK) WaitForCheckoutComplete(ctx context.Context, sessionID string, pollInterval, timeout time.Duration) (*LicenseCheckResult, error) {
+	return s.WaitForCheckout(ctx, sessionID, WithPollInterval(pollInterval), WithTimeout(timeout))
+}
+
+// WaitForCheckout polls until the checkout completes, expires or times out.
+// Failed polls back off exponentially (capped at maxPollBackoff) and the wait
+// returns ctx.Err() as soon as ctx is cancelled.
+func (s *SDK) WaitForCheckout(ctx context.Context, sessionID string, opts ...CheckoutWaitOption) (*LicenseCheckResult, error) {
+	return s.waitForCheckout(ctx, sessionID, newCheckoutWaitOptions(opts), nil)
+}
+
+// waitForCheckout is WaitForCheckout with a wake channel that triggers a
+// poll straight away, e.g. when the checkout's return URL is visited.
+func (s *SDK) waitForCheckout(ctx context.Context, sessionID string, o checkoutWaitOptions, wake <-chan struct{}) (*LicenseCheckResult, error) {
+	pollInterval := o.pollInterval
+
+	deadline := time.Now().Add(o.timeout)
+	delay := pollInterval
+	first := delay
+	if o.immediate {
+		first = 0
+	}
+	timer := time.NewTimer(first)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
-		case <-ticker.C:
-			if time.Now().After(deadline) {
-				return &LicenseCheckResult{
-					Valid:           false,
-					Reason:          ReasonNetworkError,
-					OfflineVerified: false,
-				}, nil
+		case <-timer.C:
+		case <-wake:
+			s.logf("checkout callback received, checking status")
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
 			}
+		}
 
-			status, err := s.client.GetCheckoutStatus(ctx, sessionID)
-			if err != nil {
-				continue // Retry on error
+		if time.Now().After(deadline) {
+			return &LicenseCheckResult{
+				Valid:           false,
+				Reason:          ReasonNetworkError,
+				OfflineVerified: false,
+			}, nil
+		}
+
+		status, err := s.client.GetCheckoutStatus(ctx, sessionID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
 			}
+			delay *= 2
+			if delay > maxPollBackoff {
+				delay = maxPollBackoff
+			}
+			s.logf("checkout poll failed, retrying in %s: %v", delay, err)
+			timer.Reset(delay)
+			continue
+		}
+		delay = pollInterval
 
-			switch status.Status {
-			case "complete":
-				if status.LicenseKey != "" {
-					s.StoreLicense(status.LicenseKey)
-					return s.CheckLicense(ctx)
-				}
-			case "expired":
-				return &LicenseCheckResult{
-					Valid:           false,
-					Reason:          ReasonExpired,
-					OfflineVerified: false,
-				}, nil
+		if o.onStatus != nil {
+			o.onStatus(status.Status)
+		}
+
+		switch status.Status {
+		case "complete":
+			if status.LicenseKey != "" {
+				return s.completeCheckout(status)
 			}
+		case "expired":
+			return &LicenseCheckResult{
+				Valid:           false,
+				Reason:          ReasonExpired,
+				OfflineVerified: false,
+			}, nil
 		}
+
+		timer.Reset(delay)
 	}
 }
 
+// completeCheckout stores the license from a completed checkout and returns
+// its verification result.
+func (s *SDK) completeCheckout(status *CheckoutStatus) (*LicenseCheckResult, error) {
+	if err := s.StoreLicense(status.LicenseKey); err != nil {
+		return nil, fmt.Errorf("store license: %w", err)
+	}
+
+	result := s.verifyOffline(status.LicenseKey, s.GetMachineFingerprint())
+	if result.License != nil && status.License != nil && status.License.ProductName != "" {
+		result.License.ProductName = status.License.ProductName
+	}
+	return result, nil
+}
+
+// GetCheckoutStatus returns the current status of a checkout session.
+func (s *SDK) GetCheckoutStatus(ctx context.Context, sessionID string) (*CheckoutStatus, error) {
+	return s.client.GetCheckoutStatus(ctx, sessionID)
+}
+
 // RequestLoginOtp requests an OTP for login.
 func (s *SDK) RequestLoginOtp(ctx context.Context, email string) (*OtpRequestResult, error) {
 	return s.client.RequestLoginOtp(ctx, email)
 }
 
+// ResendLoginOtp requests a fresh login OTP, replacing the previous one.
+func (s *SDK) ResendLoginOtp(ctx context.Context, email string) (*OtpRequestResult, error) {
+	return s.client.ResendLoginOtp(ctx, email)
+}
+
 // VerifyLogin verifies an OTP and logs in.
 func (s *SDK) VerifyLogin(ctx context.Context, email, otpID, otp string) (*LoginResult, error) {
 	deviceFingerprint := s.GetMachineFingerprint()
@@ -312,7 +869,9 @@ func (s *SDK) RequestPurchaseOtp(ctx context.Context) (string, int, error) {
 	return result.OtpID, result.ExpiresIn, nil
 }
 
-// ConfirmTerminalPurchase confirms a purchase with saved card and OTP.
+// ConfirmTerminalPurchase confirms a purchase with saved card and OTP. If
+// the result has RequiresAction set, send the customer to ActionURL and then
+// call ContinueAfterAction with the result's SessionID.
 func (s *SDK) ConfirmTerminalPurchase(ctx context.Context, cardID, otpID, otp string) (*PurchaseConfirmResult, error) {
 	result, err := s.client.ConfirmPurchase(ctx, s.config.ProductID, cardID, otpID, otp)
 	if err != nil {
@@ -326,13 +885,77 @@ func (s *SDK) ConfirmTerminalPurchase(ctx context.Context, cardID, otpID, otp st
 	return result, nil
 }
 
+// ContinueAfterAction finishes a terminal purchase that needed customer
+// action, such as 3D Secure verification. The sequence is:
+//
+//  1. ConfirmTerminalPurchase (or Renew) reports RequiresAction, with an
+//     ActionURL and a SessionID.
+//  2. The customer opens ActionURL in a browser and completes the action.
+//  3. ContinueAfterAction(ctx, sessionID) checks the purchase straight away
+//     and then polls like WaitForCheckout until it completes, expires or
+//     times out. On completion the license is stored and verified.
+//
+// It can be called as soon as ActionURL is shown; it waits for the customer
+// either way.
+func (s *SDK) ContinueAfterAction(ctx context.Context, sessionID string, opts ...CheckoutWaitOption) (*LicenseCheckResult, error) {
+	o := newCheckoutWaitOptions(opts)
+	o.immediate = true
+	return s.waitForCheckout(ctx, sessionID, o, nil)
+}
+
+// ActionRequiredError is returned when a purchase needs further customer
+// action, such as 3D Secure verification, before it can complete. Once the
+// customer has finished at ActionURL, pass SessionID to ContinueAfterAction.
+type ActionRequiredError struct {
+	ActionURL string
+	SessionID string
+}
+
+func (e *ActionRequiredError) Error() string {
+	return "purchase requires additional action at " + e.ActionURL
+}
+
+// Renew confirms a renewal purchase with a saved card, stores the new license
+// and returns a fresh license check. As with ConfirmTerminalPurchase, first
+// call RequestPurchaseOtp to send the customer an OTP, then pass its ID and
+// the code they received. Requires an identity token on the client. If the
+// card needs 3D Secure verification an *ActionRequiredError carrying the
+// ActionURL is returned.
+func (s *SDK) Renew(ctx context.Context, cardID, otpID, otp string) (*LicenseCheckResult, error) {
+	result, err := s.client.ConfirmPurchase(ctx, s.config.ProductID, cardID, otpID, otp)
+	if err != nil {
+		return nil, fmt.Errorf("confirm purchase: %w", err)
+	}
+
+	if result.RequiresAction {
+		return nil, &ActionRequiredError{ActionURL: result.ActionURL, SessionID: result.SessionID}
+	}
+	if !result.Success || result.License == "" {
+		if result.Error != "" {
+			return nil, fmt.Errorf("renewal failed: %s", result.Error)
+		}
+		return nil, errors.New("renewal failed")
+	}
+
+	if err := s.StoreLicense(result.License); err != nil {
+		return nil, fmt.Errorf("store license: %w", err)
+	}
+
+	return s.CheckLicense(ctx)
+}
+
 // StoreLicense stores a license key manually.
 func (s *SDK) StoreLicense(licenseKey string) error {
+	if payload, err := ExtractLicensePayload(licenseKey); err == nil && payload.ProductID != s.config.ProductID {
+		return fmt.Errorf("%w: license is for %q, not %q", ErrProductMismatch, payload.ProductID, s.config.ProductID)
+	}
+	s.verified.clear()
 	machineFingerprint := s.GetMachineFingerprint()
 	return s.storage.Save(s.config.ProductID, licenseKey, machineFingerprint)
 }
 
 // GetCachedLicenseKey returns the cached license key without verification.
+// The key is returned in full; use RedactLicenseKey before logging it.
 func (s *SDK) GetCachedLicenseKey() string {
 	cached, err := s.storage.Load(s.config.ProductID)
 	if err != nil || cached == nil {
@@ -341,11 +964,169 @@ func (s *SDK) GetCachedLicenseKey() string {
 	return cached.LicenseKey
 }
 
+// GetCachedLicense returns the cache entry for the product's license, or nil
+// if none is stored. The license itself is not verified.
+func (s *SDK) GetCachedLicense() (*CachedLicenseData, error) {
+	return s.storage.Load(s.config.ProductID)
+}
+
+// CacheInfo returns the refresh schedule of the product's cached license,
+// or nil if none is stored. The license itself is not verified.
+func (s *SDK) CacheInfo() (*CacheInfo, error) {
+	cached, err := s.storage.Load(s.config.ProductID)
+	if err != nil || cached == nil {
+		return nil, err
+	}
+	cachedAt := time.UnixMilli(cached.CachedAt)
+	return &CacheInfo{
+		CachedAt:     cachedAt,
+		RefreshAt:    cached.NextRefresh(),
+		NeedsRefresh: cached.NeedsRefresh(),
+		Age:          nowFunc().Sub(cachedAt),
+	}, nil
+}
+
 // ClearLicense clears the cached license.
 func (s *SDK) ClearLicense() error {
+	s.verified.clear()
+	return s.storage.Remove(s.config.ProductID)
+}
+
+// ErrNoLicense is returned by Deactivate and ExportLicense when no license
+// is stored.
+var ErrNoLicense = errors.New("no license stored")
+
+// ExportLicense returns the cached license key for backing up or moving to a
+// new install, or ErrNoLicense if none is stored. A node-locked license
+// (one whose payload has a MachineID) only verifies on this machine.
+func (s *SDK) ExportLicense() (string, error) {
+	cached, err := s.storage.Load(s.config.ProductID)
+	if err != nil {
+		return "", fmt.Errorf("load license: %w", err)
+	}
+	if cached == nil || cached.LicenseKey == "" {
+		return "", ErrNoLicense
+	}
+	return cached.LicenseKey, nil
+}
+
+// ImportLicense stores a license key produced by ExportLicense, after
+// checking that it is well formed, correctly signed, for this product and
+// not in Config.RevokedLicenseIDs.
+// Machine binding is not checked here, so a node-locked license from another
+// machine is stored but fails the next CheckLicense; compare the returned
+// payload's MachineID with GetMachineFingerprint to warn about that.
+func (s *SDK) ImportLicense(licenseKey string) (*LicensePayload, error) {
+	switch s.verifyOffline(licenseKey, "").Reason {
+	case ReasonInvalidFormat:
+		return nil, ErrInvalidFormat
+	case ReasonInvalidSignature:
+		return nil, ErrInvalidSignature
+	case ReasonRevoked:
+		return nil, ErrRevoked
+	}
+	if err := s.StoreLicense(licenseKey); err != nil {
+		return nil, err
+	}
+	return ExtractLicensePayload(licenseKey)
+}
+
+// ServerUnreachableError is returned by Deactivate when the license was
+// cleared locally but the server couldn't be reached to release the machine
+// binding. Deactivating again from this machine won't help; the binding
+// must be released from the dashboard or by support.
+type ServerUnreachableError struct {
+	Err error
+}
+
+func (e *ServerUnreachableError) Error() string {
+	return "license cleared locally, but the server was not reached: " + e.Err.Error()
+}
+
+func (e *ServerUnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// Deactivate releases this machine's binding on the server, so the license
+// can be activated on another machine, and then clears the cached license.
+// If the server rejects the request, or ctx is cancelled first, the cache is
+// kept so it can be retried.
+// If the server can't be reached the cache is cleared anyway and a
+// *ServerUnreachableError is returned.
+func (s *SDK) Deactivate(ctx context.Context) error {
+	cached, err := s.storage.Load(s.config.ProductID)
+	if err != nil {
+		return fmt.Errorf("load cached license: %w", err)
+	}
+	if cached == nil {
+		return ErrNoLicense
+	}
+
+	s.logf("deactivating %s", RedactLicenseKey(cached.LicenseKey))
+	err = s.client.DeactivateLicense(ctx, cached.LicenseKey, s.GetMachineFingerprint())
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) || ctx.Err() != nil {
+			return fmt.Errorf("deactivate license: %w", err)
+		}
+
+		s.logf("deactivation failed, clearing locally: %v", err)
+		if removeErr := s.storage.Remove(s.config.ProductID); removeErr != nil {
+			return fmt.Errorf("clear license: %w", removeErr)
+		}
+		return &ServerUnreachableError{Err: err}
+	}
+
 	return s.storage.Remove(s.config.ProductID)
 }
 
+// RebindNotAllowedError is returned by RebindMachine when the server refuses
+// to move the license to this machine, for example because the vendor
+// doesn't allow self-service rebinding or the rebind limit was reached.
+type RebindNotAllowedError struct {
+	Err *APIError
+}
+
+func (e *RebindNotAllowedError) Error() string {
+	return "rebinding this license is not allowed: " + e.Err.Message
+}
+
+func (e *RebindNotAllowedError) Unwrap() error {
+	return e.Err
+}
+
+// RebindMachine asks the server to re-issue the cached license for this
+// machine, for a node-locked license that was activated on another machine
+// (or before this machine's fingerprint changed), and stores the new key.
+// It returns ErrNoLicense if no license is stored and a
+// *RebindNotAllowedError if the server refuses. The cached license is kept
+// unless the rebind succeeds.
+func (s *SDK) RebindMachine(ctx context.Context) error {
+	cached, err := s.storage.Load(s.config.ProductID)
+	if err != nil {
+		return fmt.Errorf("load cached license: %w", err)
+	}
+	if cached == nil {
+		return ErrNoLicense
+	}
+
+	fingerprint := s.GetMachineFingerprint()
+	s.logf("rebinding %s", RedactLicenseKey(cached.LicenseKey))
+	licenseKey, err := s.client.RebindLicense(ctx, cached.LicenseKey, fingerprint)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.Code == ErrCodeRebindDenied || apiErr.Code == ErrCodeForbidden) {
+			return &RebindNotAllowedError{Err: apiErr}
+		}
+		return fmt.Errorf("rebind license: %w", err)
+	}
+
+	if result := VerifyLicense(licenseKey, s.publicKey, fingerprint); !result.Valid {
+		return fmt.Errorf("rebound license is not valid on this machine: %s", result.Reason)
+	}
+	return s.StoreLicense(licenseKey)
+}
+
 // ExtractLicenseInfo extracts license info without verification (for display only).
 func (s *SDK) ExtractLicenseInfo(licenseKey string) (*LicenseDetails, error) {
 	payload, err := ExtractLicensePayload(licenseKey)
@@ -369,28 +1150,73 @@ func (s *SDK) ExtractLicenseInfo(licenseKey string) (*LicenseDetails, error) {
 	}, nil
 }
 
+// PreviewLicense verifies a license key against this machine, as
+// CheckLicense would offline, and returns the details that StoreLicense
+// would store, without writing anything to disk.
+// An error is returned only when the key cannot be parsed; verification
+// failures are reported through the VerifyResult.
+func (s *SDK) PreviewLicense(licenseKey string) (*LicenseDetails, *VerifyResult, error) {
+	details, err := s.ExtractLicenseInfo(licenseKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	check := s.verifyOffline(licenseKey, s.GetMachineFingerprint())
+	result := &VerifyResult{Valid: check.Valid, Reason: check.Reason}
+	if check.License != nil {
+		// Only correctly signed licenses carry a payload, as from VerifyLicense
+		result.Payload, _ = ExtractLicensePayload(licenseKey)
+	}
+
+	return details, result, nil
+}
+
 // GetClient returns the underlying API client for advanced usage.
 func (s *SDK) GetClient() *Client {
 	return s.client
 }
 
-// GetStorage returns the underlying storage for advanced usage.
+// GetStorage returns the underlying file storage for advanced usage.
+// Returns nil when a custom Config.Store is in use.
 func (s *SDK) GetStorage() *Storage {
-	return s.storage
+	storage, _ := s.storage.(*Storage)
+	return storage
 }
 
+// StorageDir returns the directory cached licenses are stored in, or "" when
+// a custom Config.Store is in use.
+func (s *SDK) StorageDir() string {
+	if storage := s.GetStorage(); storage != nil {
+		return storage.GetStorageDir()
+	}
+	return ""
+}
+
+// HasIdentityToken reports whether the client has an identity token, as
+// needed by InitTerminalPurchase.
+func (s *SDK) HasIdentityToken() bool {
+	return s.client.HasIdentityToken()
+}
+
+// openBrowser opens checkout pages; tests replace it to stand in for the
+// user's browser.
+var openBrowser = openURL
+
 // openURL opens a URL in the default browser.
 func openURL(url string) error {
-	var cmd *exec.Cmd
+	return browserCommand(runtime.GOOS, url).Start()
+}
 
-	switch runtime.GOOS {
+// browserCommand returns the command that opens url on goos. The URL is
+// always passed as a single argument; on Windows it bypasses cmd.exe, whose
+// start builtin would treat the & in query strings as a command separator.
+func browserCommand(goos, url string) *exec.Cmd {
+	switch goos {
 	case "darwin":
-		cmd = exec.Command("open", url)
+		return exec.Command("open", url)
 	case "windows":
-		cmd = exec.Command("cmd", "/c", "start", url)
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
 	default: // linux, freebsd, etc.
-		cmd = exec.Command("xdg-open", url)
+		return exec.Command("xdg-open", url)
 	}
-
-	return cmd.Start()
 }
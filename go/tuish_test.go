@@ -1,12 +1,22 @@
 package tuish
 
 import (
+	"bytes"
 	"context"
 	"crypto/ed25519"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -75,6 +85,33 @@ func TestSDKGetMachineFingerprint(t *testing.T) {
 	}
 }
 
+func TestSDKWarmFingerprint(t *testing.T) {
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+	})
+
+	sdk.WarmFingerprint()
+
+	cached := func() string {
+		sdk.fingerprintMu.Lock()
+		defer sdk.fingerprintMu.Unlock()
+		return sdk.machineFingerprint
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for cached() == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("expected fingerprint to be cached after warming")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if fp := cached(); fp != GetMachineFingerprint() {
+		t.Errorf("expected warmed fingerprint %q, got %q", GetMachineFingerprint(), fp)
+	}
+}
+
 func TestSDKCheckLicenseNotFound(t *testing.T) {
 	tempDir := t.TempDir()
 	sdk, _ := New(Config{
@@ -220,6 +257,41 @@ func TestSDKGetCachedLicenseKey(t *testing.T) {
 	}
 }
 
+func TestSDKCacheInfo(t *testing.T) {
+	sdk := newOfflineSDK(t, Config{})
+
+	info, err := sdk.CacheInfo()
+	if err != nil || info != nil {
+		t.Fatalf("expected no info without a cached license, got %+v, %v", info, err)
+	}
+
+	cachedAt := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	setNow(t, cachedAt)
+	if err := sdk.StoreLicense("license"); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	setNow(t, cachedAt.Add(2*time.Hour))
+	info, err = sdk.CacheInfo()
+	if err != nil {
+		t.Fatalf("CacheInfo failed: %v", err)
+	}
+	if !info.CachedAt.Equal(cachedAt) {
+		t.Errorf("expected CachedAt %v, got %v", cachedAt, info.CachedAt)
+	}
+	if want := cachedAt.Add(cacheRefreshHours * time.Hour); !info.RefreshAt.Equal(want) {
+		t.Errorf("expected RefreshAt %v, got %v", want, info.RefreshAt)
+	}
+	if info.Age != 2*time.Hour || info.NeedsRefresh {
+		t.Errorf("expected a 2h old cache not due for refresh, got age %v, needsRefresh %v", info.Age, info.NeedsRefresh)
+	}
+
+	setNow(t, cachedAt.Add(25*time.Hour))
+	if info, _ = sdk.CacheInfo(); !info.NeedsRefresh {
+		t.Error("expected the cache to need a refresh after 25h")
+	}
+}
+
 func TestSDKExtractLicenseInfo(t *testing.T) {
 	sdk, _ := New(Config{
 		ProductID: "prod_test",
@@ -358,6 +430,2225 @@ func TestSDKOnlineValidation(t *testing.T) {
 	}
 }
 
+func TestSDKValidateOnlineDoesNotMutateCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		valid   bool
+		reason  LicenseInvalidReason
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(map[string]any{
+					"valid":   true,
+					"license": map[string]any{"id": "lic_test", "productId": "prod_test", "status": "active"},
+				})
+			},
+			valid: true,
+		},
+		{
+			name: "revoked",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(map[string]any{"valid": false, "reason": "revoked"})
+			},
+			reason: ReasonRevoked,
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			reason:  ReasonNetworkError,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			tempDir := t.TempDir()
+			sdk, _ := New(Config{
+				ProductID:  "prod_test",
+				PublicKey:  testPublicKeyHex,
+				StorageDir: tempDir,
+				APIBaseURL: server.URL,
+			})
+
+			now := time.Now().UnixMilli()
+			future := now + 86400000
+			license := generateTestLicenseForSDK(t, LicensePayload{
+				LicenseID: "lic_test",
+				ProductID: "prod_test",
+				IssuedAt:  now,
+				ExpiresAt: &future,
+			})
+			if err := sdk.StoreLicense(license); err != nil {
+				t.Fatalf("StoreLicense failed: %v", err)
+			}
+
+			cachePath := sdk.GetStorage().getLicenseFilePath("prod_test")
+			before, err := os.ReadFile(cachePath)
+			if err != nil {
+				t.Fatalf("read cache: %v", err)
+			}
+
+			result, err := sdk.ValidateOnline(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateOnline error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result.Valid != tt.valid {
+				t.Errorf("expected valid=%v, got %v", tt.valid, result.Valid)
+			}
+			if result.Reason != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, result.Reason)
+			}
+
+			after, err := os.ReadFile(cachePath)
+			if err != nil {
+				t.Fatalf("cache removed: %v", err)
+			}
+			if !bytes.Equal(before, after) {
+				t.Error("expected cache file to be unchanged")
+			}
+		})
+	}
+}
+
+func TestSDKValidateOnlineNotFound(t *testing.T) {
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+	})
+
+	result, err := sdk.ValidateOnline(context.Background())
+	if err != nil {
+		t.Fatalf("ValidateOnline failed: %v", err)
+	}
+	if result.Reason != ReasonNotFound {
+		t.Errorf("expected reason %s, got %s", ReasonNotFound, result.Reason)
+	}
+}
+
+func TestSDKCheckLicenseStaleOffline(t *testing.T) {
+	// Server that is immediately closed so every request fails
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	now := time.Now().UnixMilli()
+	future := now + 86400000
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  now,
+		ExpiresAt: &future,
+	})
+
+	// Fresh cache: verified offline without attempting a refresh
+	sdk.StoreLicense(license)
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid || !result.OfflineVerified {
+		t.Fatalf("expected valid offline result, got %+v", result)
+	}
+	if result.StaleOffline {
+		t.Error("expected StaleOffline=false for a fresh cache")
+	}
+
+	// Stale cache: refresh fails, offline result is trusted
+	saveStaleCache(t, sdk, license)
+	result, err = sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expected valid result, got %s", result.Reason)
+	}
+	if !result.StaleOffline {
+		t.Error("expected StaleOffline=true when the refresh failed")
+	}
+}
+
+// newCountingSDK returns an SDK whose validation requests are counted by a
+// mock server that reports every license as valid.
+func newCountingSDK(t *testing.T) (*SDK, *int) {
+	t.Helper()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]any{
+			"valid": true,
+			"license": map[string]any{
+				"id":        "lic_test",
+				"productId": "prod_test",
+				"features":  []string{},
+				"status":    "active",
+				"issuedAt":  time.Now().UnixMilli(),
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	sdk, err := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return sdk, &calls
+}
+
+func TestSDKCheckLicenseWithForceOnline(t *testing.T) {
+	sdk, calls := newCountingSDK(t)
+	sdk.StoreLicense(generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	}))
+
+	// Fresh cache: the default check stays offline
+	if _, err := sdk.CheckLicense(context.Background()); err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if *calls != 0 {
+		t.Fatalf("expected no requests for a fresh cache, got %d", *calls)
+	}
+
+	result, err := sdk.CheckLicenseWith(context.Background(), ForceOnline())
+	if err != nil {
+		t.Fatalf("CheckLicenseWith failed: %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("expected ForceOnline to revalidate, got %d requests", *calls)
+	}
+	if !result.Valid || result.Source != LicenseSourceOnline {
+		t.Errorf("expected valid online result, got %+v", result)
+	}
+}
+
+func TestSDKAlwaysOnline(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	sdk, calls := newCountingSDK(t)
+	sdk.config.AlwaysOnline = true
+	sdk.StoreLicense(license)
+
+	// The cache is fresh, but the server is asked anyway
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("expected an online validation with a fresh cache, got %d requests", *calls)
+	}
+	if !result.Valid || result.Source != LicenseSourceOnline {
+		t.Errorf("expected valid online result, got %+v", result)
+	}
+
+	// A per-call SkipOnline still stays offline
+	if _, err := sdk.CheckLicenseWith(context.Background(), SkipOnline()); err != nil {
+		t.Fatalf("CheckLicenseWith failed: %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("expected SkipOnline to win over AlwaysOnline, got %d requests", *calls)
+	}
+
+	// With the server down the cached license is the fallback
+	offline := newOfflineSDK(t, Config{AlwaysOnline: true})
+	offline.StoreLicense(license)
+	result, err = offline.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid || result.Source != LicenseSourceOffline || !result.StaleOffline || !result.NetworkChecked {
+		t.Errorf("expected a valid offline fallback after a network error, got %+v", result)
+	}
+}
+
+func TestSDKIgnoreMachineBinding(t *testing.T) {
+	otherMachine := "machine_elsewhere"
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_bound",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+		MachineID: &otherMachine,
+	})
+
+	for _, tt := range []struct {
+		ignore bool
+		valid  bool
+	}{
+		{ignore: false, valid: false},
+		{ignore: true, valid: true},
+	} {
+		sdk := newOfflineSDK(t, Config{IgnoreMachineBinding: tt.ignore})
+		if err := sdk.StoreLicense(license); err != nil {
+			t.Fatalf("StoreLicense failed: %v", err)
+		}
+		result, err := sdk.CheckLicense(context.Background())
+		if err != nil {
+			t.Fatalf("CheckLicense failed: %v", err)
+		}
+		if result.Valid != tt.valid {
+			t.Errorf("IgnoreMachineBinding=%v: expected valid=%v, got valid=%v reason=%s", tt.ignore, tt.valid, result.Valid, result.Reason)
+		}
+		if !tt.valid && result.Reason != ReasonMachineMismatch {
+			t.Errorf("expected reason %s, got %s", ReasonMachineMismatch, result.Reason)
+		}
+	}
+}
+
+func TestSDKConfigProductName(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	offline := newOfflineSDK(t, Config{ProductName: "Acme Pro"})
+	if err := offline.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+	result, err := offline.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Source != LicenseSourceOffline || result.License.ProductName != "Acme Pro" {
+		t.Errorf("expected offline result named Acme Pro, got source %s name %q", result.Source, result.License.ProductName)
+	}
+
+	// The API's name wins when checking online
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"success": true, "data": map[string]any{
+			"valid": true,
+			"license": map[string]any{
+				"id":          "lic_test",
+				"productId":   "prod_test",
+				"productName": "Acme Pro 2",
+				"status":      "active",
+			},
+		}})
+	}))
+	defer server.Close()
+
+	online, _ := New(Config{
+		ProductID:   "prod_test",
+		ProductName: "Acme Pro",
+		PublicKey:   testPublicKeyHex,
+		StorageDir:  t.TempDir(),
+		APIBaseURL:  server.URL,
+	})
+	online.StoreLicense(license)
+	result, err = online.CheckLicenseWith(context.Background(), ForceOnline())
+	if err != nil {
+		t.Fatalf("CheckLicenseWith failed: %v", err)
+	}
+	if result.Source != LicenseSourceOnline || result.License.ProductName != "Acme Pro 2" {
+		t.Errorf("expected online result named by the API, got source %s name %q", result.Source, result.License.ProductName)
+	}
+}
+
+func TestSDKHasFeatureCtx(t *testing.T) {
+	sdk, calls := newCountingSDK(t)
+	checks := 0
+	sdk.config.OnCheck = func(*LicenseCheckResult, string) { checks++ }
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		Features:  []string{"pro"},
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	// A fresh cache that grants the feature stays offline
+	ok, err := sdk.HasFeatureCtx(context.Background(), "pro")
+	if err != nil {
+		t.Fatalf("HasFeatureCtx failed: %v", err)
+	}
+	if !ok {
+		t.Error("expected cached license to grant pro")
+	}
+	if *calls != 0 {
+		t.Errorf("expected no network call for a granted feature, got %d", *calls)
+	}
+
+	// A missing feature is confirmed with the server, reported once
+	ok, err = sdk.HasFeatureCtx(context.Background(), "export")
+	if err != nil {
+		t.Fatalf("HasFeatureCtx failed: %v", err)
+	}
+	if ok {
+		t.Error("expected export not to be granted")
+	}
+	if *calls != 1 {
+		t.Errorf("expected one network call for a missing feature, got %d", *calls)
+	}
+	if checks != 2 {
+		t.Errorf("expected OnCheck once per HasFeatureCtx, got %d calls", checks)
+	}
+}
+
+func TestSDKHasFeatureCtxRefreshesStaleCache(t *testing.T) {
+	sdk, calls := newCountingSDK(t)
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		Features:  []string{"pro"},
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	// A cache due for a refresh is refreshed even when it grants the feature
+	saveStaleCache(t, sdk, license)
+	if _, err := sdk.HasFeatureCtx(context.Background(), "pro"); err != nil {
+		t.Fatalf("HasFeatureCtx failed: %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("expected a refresh of the stale cache, got %d network calls", *calls)
+	}
+
+	// AlwaysOnline revalidates a fresh cache too
+	sdk.config.AlwaysOnline = true
+	if _, err := sdk.HasFeatureCtx(context.Background(), "pro"); err != nil {
+		t.Fatalf("HasFeatureCtx failed: %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("expected AlwaysOnline to revalidate, got %d network calls", *calls)
+	}
+}
+
+func TestDiffFeatures(t *testing.T) {
+	tests := []struct {
+		name        string
+		have, want  []string
+		wantMissing []string
+		wantExtra   []string
+	}{
+		{"disjoint", []string{"basic"}, []string{"pro", "export"}, []string{"pro", "export"}, []string{"basic"}},
+		{"overlapping", []string{"basic", "pro"}, []string{"pro", "export"}, []string{"export"}, []string{"basic"}},
+		{"identical", []string{"pro", "export"}, []string{"export", "pro"}, nil, nil},
+		{"trimmed and deduplicated", []string{" pro "}, []string{"pro", "team", "team"}, []string{"team"}, nil},
+		{"empty", nil, nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing, extra := DiffFeatures(tt.have, tt.want)
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+			if !reflect.DeepEqual(extra, tt.wantExtra) {
+				t.Errorf("extra = %v, want %v", extra, tt.wantExtra)
+			}
+		})
+	}
+}
+
+func TestSDKCheckLicenseWithSkipOnline(t *testing.T) {
+	sdk, calls := newCountingSDK(t)
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	saveStaleCache(t, sdk, license)
+
+	result, err := sdk.CheckLicenseWith(context.Background(), SkipOnline())
+	if err != nil {
+		t.Fatalf("CheckLicenseWith failed: %v", err)
+	}
+	if *calls != 0 {
+		t.Errorf("expected SkipOnline not to hit the network, got %d requests", *calls)
+	}
+	if !result.Valid || !result.OfflineVerified {
+		t.Errorf("expected valid offline result, got %+v", result)
+	}
+
+	// The default check refreshes the stale cache
+	if _, err := sdk.CheckLicense(context.Background()); err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("expected the default check to refresh, got %d requests", *calls)
+	}
+}
+
+func TestSDKCheckLicenseWithSkipOnlineExpired(t *testing.T) {
+	sdk, calls := newCountingSDK(t)
+	past := time.Now().Add(-time.Hour).UnixMilli()
+	sdk.StoreLicense(generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  past,
+		ExpiresAt: &past,
+	}))
+
+	result, err := sdk.CheckLicenseWith(context.Background(), SkipOnline())
+	if err != nil {
+		t.Fatalf("CheckLicenseWith failed: %v", err)
+	}
+	if *calls != 0 {
+		t.Errorf("expected SkipOnline not to hit the network, got %d requests", *calls)
+	}
+	if result.Valid || result.Reason != ReasonExpired {
+		t.Errorf("expected expired result, got %+v", result)
+	}
+}
+
+func TestSDKCheckLicenseExpiredCancelledKeepsLicense(t *testing.T) {
+	sdk, _ := newCountingSDK(t)
+	past := time.Now().Add(-time.Hour).UnixMilli()
+	sdk.StoreLicense(generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  past,
+		ExpiresAt: &past,
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := sdk.CheckLicense(ctx)
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Reason != ReasonExpired {
+		t.Errorf("expected expired result, got %+v", result)
+	}
+	if sdk.GetCachedLicenseKey() == "" {
+		t.Error("expected a cancelled check to keep the expired license for a later renewal check")
+	}
+}
+
+func TestSDKOnCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"valid": false, "reason": "revoked"})
+	}))
+	defer server.Close()
+
+	type outcome struct {
+		reason LicenseInvalidReason
+		source string
+	}
+	var outcomes []outcome
+	sdk, err := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+		OnCheck: func(result *LicenseCheckResult, source string) {
+			outcomes = append(outcomes, outcome{result.Reason, source})
+		},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	now := time.Now().UnixMilli()
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  now,
+	})
+	otherMachine := "other_machine"
+	mismatched := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  now,
+		MachineID: &otherMachine,
+	})
+
+	check := func() {
+		t.Helper()
+		if _, err := sdk.CheckLicense(context.Background()); err != nil {
+			t.Fatalf("CheckLicense failed: %v", err)
+		}
+	}
+
+	check() // not found
+	sdk.StoreLicense(license)
+	check() // valid, fresh cache
+	sdk.StoreLicense(mismatched)
+	check() // machine mismatch
+	saveStaleCache(t, sdk, license)
+	check() // revoked by the server
+
+	want := []outcome{
+		{ReasonNotFound, "not_found"},
+		{"", "offline"},
+		{ReasonMachineMismatch, "offline"},
+		{ReasonRevoked, "online"},
+	}
+	if len(outcomes) != len(want) {
+		t.Fatalf("expected %d callbacks, got %d: %+v", len(want), len(outcomes), outcomes)
+	}
+	for i := range want {
+		if outcomes[i] != want[i] {
+			t.Errorf("check %d: expected %+v, got %+v", i, want[i], outcomes[i])
+		}
+	}
+}
+
+// saveAgedCache stores a license that was cached age ago.
+func saveAgedCache(t *testing.T, sdk *SDK, license string, age time.Duration) {
+	t.Helper()
+
+	saveStaleCache(t, sdk, license)
+	storage := sdk.GetStorage()
+	cached, err := storage.Load(sdk.config.ProductID)
+	if err != nil || cached == nil {
+		t.Fatalf("load cache: %v", err)
+	}
+	cached.CachedAt = time.Now().Add(-age).UnixMilli()
+
+	data, _ := json.Marshal(cached)
+	if err := os.WriteFile(storage.getLicenseFilePath(sdk.config.ProductID), data, 0600); err != nil {
+		t.Fatalf("write cache: %v", err)
+	}
+}
+
+func TestSDKCheckLicenseNetworkChecked(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) *SDK
+		want  bool
+	}{
+		{
+			name: "not found",
+			setup: func(t *testing.T) *SDK {
+				sdk, _ := newCountingSDK(t)
+				return sdk
+			},
+			want: false,
+		},
+		{
+			name: "fresh cache",
+			setup: func(t *testing.T) *SDK {
+				sdk, _ := newCountingSDK(t)
+				if err := sdk.StoreLicense(license); err != nil {
+					t.Fatalf("StoreLicense failed: %v", err)
+				}
+				return sdk
+			},
+			want: false,
+		},
+		{
+			name: "stale cache validated online",
+			setup: func(t *testing.T) *SDK {
+				sdk, _ := newCountingSDK(t)
+				saveStaleCache(t, sdk, license)
+				return sdk
+			},
+			want: true,
+		},
+		{
+			name: "stale cache with network error",
+			setup: func(t *testing.T) *SDK {
+				sdk := newOfflineSDK(t, Config{})
+				saveStaleCache(t, sdk, license)
+				return sdk
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := tt.setup(t)
+			result, err := sdk.CheckLicense(context.Background())
+			if err != nil {
+				t.Fatalf("CheckLicense failed: %v", err)
+			}
+			if result.NetworkChecked != tt.want {
+				t.Errorf("expected NetworkChecked=%t, got %t (source %s)", tt.want, result.NetworkChecked, result.Source)
+			}
+		})
+	}
+}
+
+func newOfflineSDK(t *testing.T, config Config) *SDK {
+	t.Helper()
+
+	// Server that is immediately closed so every request fails
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	config.ProductID = "prod_test"
+	config.PublicKey = testPublicKeyHex
+	config.StorageDir = t.TempDir()
+	config.APIBaseURL = server.URL
+	sdk, err := New(config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return sdk
+}
+
+func TestSDKMaxOfflineAge(t *testing.T) {
+	sdk := newOfflineSDK(t, Config{MaxOfflineAge: 30 * 24 * time.Hour})
+	future := time.Now().Add(365 * 24 * time.Hour).UnixMilli()
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+		ExpiresAt: &future,
+	})
+
+	saveAgedCache(t, sdk, license, 29*24*time.Hour)
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid || !result.StaleOffline {
+		t.Fatalf("expected stale but valid result within the cap, got %+v", result)
+	}
+
+	saveAgedCache(t, sdk, license, 31*24*time.Hour)
+	for _, opts := range [][]CheckOption{nil, {SkipOnline()}} {
+		result, err = sdk.CheckLicenseWith(context.Background(), opts...)
+		if err != nil {
+			t.Fatalf("CheckLicense failed: %v", err)
+		}
+		if result.Valid || result.Reason != ReasonOfflineExpired {
+			t.Errorf("expected %s past the cap, got valid=%t reason=%s", ReasonOfflineExpired, result.Valid, result.Reason)
+		}
+	}
+
+	// The cache is kept so a later online validation can recover
+	if sdk.GetCachedLicenseKey() != license {
+		t.Error("expected cached license to be kept")
+	}
+}
+
+func TestSDKMaxOfflineAgeRecoversOnline(t *testing.T) {
+	sdk, calls := newCountingSDK(t)
+	sdk.config.MaxOfflineAge = 30 * 24 * time.Hour
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	saveAgedCache(t, sdk, license, 31*24*time.Hour)
+
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid || *calls != 1 {
+		t.Fatalf("expected online validation to succeed, got %+v after %d requests", result, *calls)
+	}
+
+	// The successful validation resets the offline age
+	cached, _ := sdk.GetCachedLicense()
+	if time.Since(time.UnixMilli(cached.CachedAt)) > time.Minute {
+		t.Error("expected CachedAt to be reset")
+	}
+}
+
+func TestSDKDisableOnlineSkipsNetwork(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "unexpected request", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sdk, err := New(Config{
+		ProductID:     "prod_test",
+		PublicKey:     testPublicKeyHex,
+		StorageDir:    t.TempDir(),
+		APIBaseURL:    server.URL,
+		DisableOnline: true,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	saveStaleCache(t, sdk, generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	}))
+
+	for _, opts := range [][]CheckOption{nil, {ForceOnline()}} {
+		result, err := sdk.CheckLicenseWith(context.Background(), opts...)
+		if err != nil {
+			t.Fatalf("CheckLicense failed: %v", err)
+		}
+		if !result.Valid || result.Source != LicenseSourceOffline {
+			t.Errorf("expected valid offline result, got valid=%t source=%s", result.Valid, result.Source)
+		}
+		if result.NetworkChecked || result.StaleOffline || result.Reason == ReasonNetworkError {
+			t.Errorf("expected no sign of a network attempt, got %+v", result)
+		}
+	}
+	if _, err := sdk.ValidateOnline(context.Background()); !errors.Is(err, ErrOnlineDisabled) {
+		t.Errorf("expected ErrOnlineDisabled from ValidateOnline, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no requests with DisableOnline, got %d", calls)
+	}
+}
+
+func TestSDKMaxOfflineAgeOfflineOnlyPerpetual(t *testing.T) {
+	sdk := newOfflineSDK(t, Config{MaxOfflineAge: 30 * 24 * time.Hour, OfflineOnly: true})
+	perpetual := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	saveAgedCache(t, sdk, perpetual, 365*24*time.Hour)
+
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("expected perpetual license to be exempt, got %s", result.Reason)
+	}
+
+	future := time.Now().Add(365 * 24 * time.Hour).UnixMilli()
+	expiring := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+		ExpiresAt: &future,
+	})
+	saveAgedCache(t, sdk, expiring, 365*24*time.Hour)
+
+	result, err = sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Reason != ReasonOfflineExpired {
+		t.Errorf("expected expiring license to be capped, got valid=%t reason=%s", result.Valid, result.Reason)
+	}
+}
+
+func TestSDKCheckLicenseSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"valid":   true,
+			"license": map[string]any{"id": "lic_test", "productId": "prod_test", "status": "active"},
+		})
+	}))
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	result, _ := sdk.CheckLicense(context.Background())
+	if result.Source != LicenseSourceNotFound {
+		t.Errorf("expected source %s, got %s", LicenseSourceNotFound, result.Source)
+	}
+
+	now := time.Now().UnixMilli()
+	future := now + 86400000
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  now,
+		ExpiresAt: &future,
+	})
+
+	sdk.StoreLicense(license)
+	result, _ = sdk.CheckLicense(context.Background())
+	if result.Source != LicenseSourceOffline {
+		t.Errorf("expected source %s, got %s", LicenseSourceOffline, result.Source)
+	}
+
+	saveStaleCache(t, sdk, license)
+	result, _ = sdk.CheckLicense(context.Background())
+	if result.Source != LicenseSourceOnline {
+		t.Errorf("expected source %s, got %s", LicenseSourceOnline, result.Source)
+	}
+}
+
+func TestSDKStoreLicenseProductMismatch(t *testing.T) {
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+	})
+
+	future := time.Now().UnixMilli() + 86400000
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_other",
+		ProductID: "prod_other",
+		IssuedAt:  time.Now().UnixMilli(),
+		ExpiresAt: &future,
+	})
+
+	err := sdk.StoreLicense(license)
+	if !errors.Is(err, ErrProductMismatch) {
+		t.Fatalf("expected ErrProductMismatch, got %v", err)
+	}
+	if key := sdk.GetCachedLicenseKey(); key != "" {
+		t.Error("expected mismatched license not to be cached")
+	}
+}
+
+func TestSDKVerifyOfflineStatusByReason(t *testing.T) {
+	sdk, _ := New(Config{
+		ProductID:         "prod_test",
+		PublicKey:         testPublicKeyHex,
+		StorageDir:        t.TempDir(),
+		RevokedLicenseIDs: []string{"lic_revoked"},
+	})
+
+	now := time.Now().UnixMilli()
+	past := now - 86400000
+	otherMachine := "other-machine"
+	valid := generateTestLicenseForSDK(t, LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", IssuedAt: now})
+	tampered := valid[:strings.LastIndex(valid, ".")+1] + base64URLEncode(make([]byte, 64))
+
+	tests := []struct {
+		name    string
+		license string
+		reason  LicenseInvalidReason
+		status  LicenseStatus
+	}{
+		{
+			name:    "expired",
+			license: generateTestLicenseForSDK(t, LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", IssuedAt: past, ExpiresAt: &past}),
+			reason:  ReasonExpired,
+			status:  LicenseStatusExpired,
+		},
+		{
+			name:    "machine mismatch",
+			license: generateTestLicenseForSDK(t, LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", IssuedAt: now, MachineID: &otherMachine}),
+			reason:  ReasonMachineMismatch,
+			status:  LicenseStatusInvalid,
+		},
+		{
+			name:    "product mismatch",
+			license: generateTestLicenseForSDK(t, LicensePayload{LicenseID: "lic_test", ProductID: "prod_other", IssuedAt: now}),
+			reason:  ReasonProductMismatch,
+			status:  LicenseStatusInvalid,
+		},
+		{
+			name:    "revoked",
+			license: generateTestLicenseForSDK(t, LicensePayload{LicenseID: "lic_revoked", ProductID: "prod_test", IssuedAt: now}),
+			reason:  ReasonRevoked,
+			status:  LicenseStatusRevoked,
+		},
+		{
+			// Without a trusted payload there are no details to report
+			name:    "invalid signature",
+			license: tampered,
+			reason:  ReasonInvalidSignature,
+		},
+		{
+			name:    "invalid format",
+			license: "not-a-license",
+			reason:  ReasonInvalidFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := sdk.verifyOffline(tt.license, sdk.GetMachineFingerprint())
+			if result.Valid || result.Reason != tt.reason {
+				t.Fatalf("expected invalid result with reason %s, got valid=%t reason=%s", tt.reason, result.Valid, result.Reason)
+			}
+			if tt.status == "" {
+				if result.License != nil {
+					t.Errorf("expected no license details, got status %s", result.License.Status)
+				}
+				return
+			}
+			if result.License == nil || result.License.Status != tt.status {
+				t.Errorf("expected status %s, got %+v", tt.status, result.License)
+			}
+		})
+	}
+}
+
+func TestSDKCheckLicenseProductMismatch(t *testing.T) {
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+	})
+
+	future := time.Now().UnixMilli() + 86400000
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_other",
+		ProductID: "prod_other",
+		IssuedAt:  time.Now().UnixMilli(),
+		ExpiresAt: &future,
+	})
+
+	// Bypass StoreLicense to simulate a cache written by an older version
+	if err := sdk.storage.Save("prod_test", license, sdk.GetMachineFingerprint()); err != nil {
+		t.Fatalf("save cache: %v", err)
+	}
+
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Valid {
+		t.Error("expected license for another product to be invalid")
+	}
+	if result.Reason != ReasonProductMismatch {
+		t.Errorf("expected reason %s, got %s", ReasonProductMismatch, result.Reason)
+	}
+	if result.License == nil || result.License.ProductID != "prod_other" {
+		t.Error("expected license details to report the embedded product ID")
+	}
+	if key := sdk.GetCachedLicenseKey(); key != "" {
+		t.Error("expected mismatched cache to be removed")
+	}
+}
+
+func TestSDKPreviewLicense(t *testing.T) {
+	tempDir := t.TempDir()
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: tempDir,
+	})
+
+	now := time.Now().UnixMilli()
+	future := now + 86400000
+	past := now - 86400000
+	otherMachine := "other-machine"
+
+	tests := []struct {
+		name    string
+		payload LicensePayload
+		valid   bool
+		reason  LicenseInvalidReason
+		status  LicenseStatus
+	}{
+		{
+			name:    "valid",
+			payload: LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", Features: []string{"pro"}, IssuedAt: now, ExpiresAt: &future},
+			valid:   true,
+			status:  LicenseStatusActive,
+		},
+		{
+			name:    "expired",
+			payload: LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", IssuedAt: past, ExpiresAt: &past},
+			reason:  ReasonExpired,
+			status:  LicenseStatusExpired,
+		},
+		{
+			name:    "wrong machine",
+			payload: LicensePayload{LicenseID: "lic_test", ProductID: "prod_test", IssuedAt: now, ExpiresAt: &future, MachineID: &otherMachine},
+			reason:  ReasonMachineMismatch,
+			status:  LicenseStatusActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			license := generateTestLicenseForSDK(t, tt.payload)
+
+			details, result, err := sdk.PreviewLicense(license)
+			if err != nil {
+				t.Fatalf("PreviewLicense failed: %v", err)
+			}
+			if result.Valid != tt.valid {
+				t.Errorf("expected valid=%v, got %v", tt.valid, result.Valid)
+			}
+			if result.Reason != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, result.Reason)
+			}
+			if details.ID != "lic_test" || details.Status != tt.status {
+				t.Errorf("unexpected details: %+v", details)
+			}
+			if key := sdk.GetCachedLicenseKey(); key != "" {
+				t.Error("expected preview not to write to disk")
+			}
+		})
+	}
+
+	if _, _, err := sdk.PreviewLicense("not-a-license"); err == nil {
+		t.Error("expected error for malformed key")
+	}
+}
+
+func TestSDKPreviewLicenseMatchesCheckLicense(t *testing.T) {
+	otherMachine := "machine_elsewhere"
+	bound := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_bound",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+		MachineID: &otherMachine,
+	})
+	revoked := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_revoked",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	sdk := newOfflineSDK(t, Config{IgnoreMachineBinding: true, RevokedLicenseIDs: []string{"lic_revoked"}})
+
+	_, result, err := sdk.PreviewLicense(bound)
+	if err != nil {
+		t.Fatalf("PreviewLicense failed: %v", err)
+	}
+	if !result.Valid || result.Payload == nil {
+		t.Errorf("expected a license for another machine to preview as valid, got %+v", result)
+	}
+
+	_, result, err = sdk.PreviewLicense(revoked)
+	if err != nil {
+		t.Fatalf("PreviewLicense failed: %v", err)
+	}
+	if result.Valid || result.Reason != ReasonRevoked {
+		t.Errorf("expected a revoked license to preview as revoked, got %+v", result)
+	}
+}
+
+func TestSDKDebugLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"valid": false, "reason": "revoked"})
+	}))
+	defer server.Close()
+
+	var lines []string
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+		Debug:      true,
+		Logger: func(format string, args ...any) {
+			lines = append(lines, fmt.Sprintf(format, args...))
+		},
+	})
+
+	// Cache miss
+	sdk.CheckLicense(context.Background())
+
+	now := time.Now().UnixMilli()
+	future := now + 86400000
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  now,
+		ExpiresAt: &future,
+	})
+	saveStaleCache(t, sdk, license)
+
+	// Cache hit followed by an online refresh
+	sdk.CheckLicense(context.Background())
+
+	output := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"cache miss for prod_test",
+		"cache hit for prod_test",
+		"offline verification: valid=true",
+		"online validation for",
+		"POST /v1/licenses/validate -> 200",
+		"reason=revoked",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected log output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, license) {
+		t.Error("expected license key to be redacted in logs")
+	}
+}
+
+func TestSDKLoggerSilentWithoutDebug(t *testing.T) {
+	called := false
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		Logger: func(format string, args ...any) {
+			called = true
+		},
+	})
+
+	sdk.CheckLicense(context.Background())
+	if called {
+		t.Error("expected no logging when Debug is false")
+	}
+}
+
+func newRenewServer(t *testing.T, confirm map[string]any) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer id_token" {
+			t.Errorf("expected identity token, got %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/v1/purchase/otp":
+			json.NewEncoder(w).Encode(map[string]any{
+				"success": true,
+				"data":    map[string]any{"otpId": "otp_1", "expiresIn": 300},
+			})
+		case "/v1/purchase/confirm":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["otpId"] != "otp_1" || body["otp"] != "123456" || body["cardId"] != "card_1" {
+				t.Errorf("unexpected confirm body: %v", body)
+			}
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "data": confirm})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestSDKRenew(t *testing.T) {
+	now := time.Now().UnixMilli()
+	future := now + 86400000
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_renewed",
+		ProductID: "prod_test",
+		IssuedAt:  now,
+		ExpiresAt: &future,
+	})
+
+	server := newRenewServer(t, map[string]any{"success": true, "license": license})
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+	sdk.GetClient().SetIdentityToken("id_token")
+
+	otpID, _, err := sdk.RequestPurchaseOtp(context.Background())
+	if err != nil {
+		t.Fatalf("RequestPurchaseOtp failed: %v", err)
+	}
+	result, err := sdk.Renew(context.Background(), "card_1", otpID, "123456")
+	if err != nil {
+		t.Fatalf("Renew failed: %v", err)
+	}
+	if !result.Valid || result.License.ID != "lic_renewed" {
+		t.Errorf("expected renewed license, got %+v", result)
+	}
+	if sdk.GetCachedLicenseKey() != license {
+		t.Error("expected renewed license to be stored")
+	}
+}
+
+func TestSDKRenewRequiresAction(t *testing.T) {
+	server := newRenewServer(t, map[string]any{
+		"success":        false,
+		"requiresAction": true,
+		"actionUrl":      "https://example.com/3ds",
+	})
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+	sdk.GetClient().SetIdentityToken("id_token")
+
+	_, err := sdk.Renew(context.Background(), "card_1", "otp_1", "123456")
+	var actionErr *ActionRequiredError
+	if !errors.As(err, &actionErr) {
+		t.Fatalf("expected ActionRequiredError, got %v", err)
+	}
+	if actionErr.ActionURL != "https://example.com/3ds" {
+		t.Errorf("unexpected action URL %q", actionErr.ActionURL)
+	}
+	if sdk.GetCachedLicenseKey() != "" {
+		t.Error("expected nothing stored when action is required")
+	}
+}
+
+// newActionServer serves a purchase confirmation that requires 3D Secure,
+// then reports the checkout pending for pending polls before completing it
+// with license.
+func newActionServer(t *testing.T, license string, pending int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/purchase/confirm":
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "data": map[string]any{
+				"success":        false,
+				"requiresAction": true,
+				"actionUrl":      "https://example.com/3ds",
+				"sessionId":      "sess_3ds",
+			}})
+		case "/v1/checkout/status/sess_3ds":
+			status := map[string]any{"status": "pending"}
+			if int(polls.Add(1)) > pending {
+				status = map[string]any{"status": "complete", "licenseKey": license}
+			}
+			json.NewEncoder(w).Encode(map[string]any{"success": true, "data": status})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, &polls
+}
+
+func TestSDKContinueAfterAction(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_3ds",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	server, polls := newActionServer(t, license, 1)
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+	sdk.GetClient().SetIdentityToken("id_token")
+
+	confirm, err := sdk.ConfirmTerminalPurchase(context.Background(), "card_1", "otp_1", "123456")
+	if err != nil {
+		t.Fatalf("ConfirmTerminalPurchase failed: %v", err)
+	}
+	if !confirm.RequiresAction || confirm.SessionID != "sess_3ds" {
+		t.Fatalf("expected a 3DS action with a session, got %+v", confirm)
+	}
+	if sdk.GetCachedLicenseKey() != "" {
+		t.Fatal("expected nothing stored while action is required")
+	}
+
+	result, err := sdk.ContinueAfterAction(context.Background(), confirm.SessionID, WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("ContinueAfterAction failed: %v", err)
+	}
+	if !result.Valid || result.License.ID != "lic_3ds" {
+		t.Errorf("expected the purchased license, got %+v", result)
+	}
+	if sdk.GetCachedLicenseKey() != license {
+		t.Error("expected the license to be stored after the action completed")
+	}
+	if got := polls.Load(); got != 2 {
+		t.Errorf("expected 2 status polls, got %d", got)
+	}
+}
+
+func TestSDKContinueAfterActionChecksImmediately(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_3ds",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	server, _ := newActionServer(t, license, 0)
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	// With an hour between polls, only an immediate first check can finish
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	opts := make([]CheckoutWaitOption, 1, 2)
+	opts[0] = WithPollInterval(time.Hour)
+	result, err := sdk.ContinueAfterAction(ctx, "sess_3ds", opts...)
+	if err != nil {
+		t.Fatalf("ContinueAfterAction failed: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("expected a valid license, got %s", result.Reason)
+	}
+
+	// The caller's slice has room to spare, which must not be written to
+	if opts[:2][1] != nil {
+		t.Error("expected ContinueAfterAction to leave the caller's options alone")
+	}
+}
+
+func TestSDKRenewFailure(t *testing.T) {
+	server := newRenewServer(t, map[string]any{"success": false, "error": "card declined"})
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+	sdk.GetClient().SetIdentityToken("id_token")
+
+	_, err := sdk.Renew(context.Background(), "card_1", "otp_1", "123456")
+	if err == nil || !strings.Contains(err.Error(), "card declined") {
+		t.Errorf("expected card declined error, got %v", err)
+	}
+}
+
+func TestSDKWithMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	sdk, err := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		Store:      store,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if sdk.GetStorage() != nil {
+		t.Error("expected no file storage with a custom store")
+	}
+
+	now := time.Now().UnixMilli()
+	future := now + 86400000
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  now,
+		ExpiresAt: &future,
+	})
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	cached, _ := store.Load("prod_test")
+	if cached == nil || cached.LicenseKey != license {
+		t.Fatal("expected license in memory store")
+	}
+
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("expected valid license, got reason %s", result.Reason)
+	}
+
+	sdk.ClearLicense()
+	if cached, _ := store.Load("prod_test"); cached != nil {
+		t.Error("expected license cleared from memory store")
+	}
+}
+
+func TestSDKWaitForCheckoutCompleteReturnsResult(t *testing.T) {
+	now := time.Now().UnixMilli()
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_checkout",
+		ProductID: "prod_test",
+		IssuedAt:  now,
+	})
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls == 1 {
+			json.NewEncoder(w).Encode(map[string]any{"status": "pending"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"status":     "complete",
+			"licenseKey": license,
+			"license":    map[string]any{"id": "lic_checkout", "productName": "Test App"},
+		})
+	}))
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	result, err := sdk.WaitForCheckoutComplete(context.Background(), "sess_1", 5*time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("WaitForCheckoutComplete failed: %v", err)
+	}
+	if !result.Valid || result.License.ID != "lic_checkout" {
+		t.Fatalf("expected completed license, got %+v", result)
+	}
+	if result.License.ProductName != "Test App" {
+		t.Errorf("expected product name from checkout, got %q", result.License.ProductName)
+	}
+	if sdk.GetCachedLicenseKey() != license {
+		t.Error("expected license to be stored")
+	}
+}
+
+func TestSDKWaitForCheckoutReportsStatus(t *testing.T) {
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_checkout",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			json.NewEncoder(w).Encode(map[string]any{"status": "pending"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"status": "complete", "licenseKey": license})
+	}))
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	var statuses []string
+	result, err := sdk.WaitForCheckout(context.Background(), "sess_1",
+		WithPollInterval(5*time.Millisecond),
+		WithTimeout(time.Second),
+		WithOnStatus(func(status string) { statuses = append(statuses, status) }),
+	)
+	if err != nil {
+		t.Fatalf("WaitForCheckout failed: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expected completed license, got %+v", result)
+	}
+	if got := strings.Join(statuses, ", "); got != "pending, pending, complete" {
+		t.Errorf("expected statuses \"pending, pending, complete\", got %q", got)
+	}
+}
+
+func TestSDKWaitForCheckoutCompleteCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first poll, then cancel while the SDK is backing off
+		time.AfterFunc(20*time.Millisecond, cancel)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	start := time.Now()
+	_, err := sdk.WaitForCheckoutComplete(ctx, "sess_1", 200*time.Millisecond, time.Minute)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	// First poll at 200ms, cancel 20ms later, well before the 400ms backoff
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("expected prompt return on cancellation, took %s", elapsed)
+	}
+}
+
+func TestSDKWaitForCheckoutCompleteBacksOff(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+		APIBaseURL: server.URL,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	sdk.WaitForCheckoutComplete(ctx, "sess_1", 10*time.Millisecond, time.Minute)
+
+	// Polls at 10, 30, 70 and 150ms; a fixed interval would poll ~20 times
+	if polls > 6 {
+		t.Errorf("expected failed polls to back off, got %d polls", polls)
+	}
+}
+
+func TestSDKCheckLicenseWrongProductInSharedStorage(t *testing.T) {
+	storageDir := t.TempDir()
+	sdkA, _ := New(Config{ProductID: "prod_A", PublicKey: testPublicKeyHex, StorageDir: storageDir})
+	sdkB, _ := New(Config{ProductID: "prod_B", PublicKey: testPublicKeyHex, StorageDir: storageDir})
+
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_A",
+		ProductID: "prod_A",
+		Features:  []string{"pro"},
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	if err := sdkA.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	// Simulate a collision or manual edit: copy A's cache file over B's
+	data, err := os.ReadFile(sdkA.GetStorage().getLicenseFilePath("prod_A"))
+	if err != nil {
+		t.Fatalf("read cache: %v", err)
+	}
+	if err := os.WriteFile(sdkB.GetStorage().getLicenseFilePath("prod_B"), data, 0600); err != nil {
+		t.Fatalf("write cache: %v", err)
+	}
+
+	result, err := sdkB.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Valid || result.Reason != ReasonProductMismatch {
+		t.Errorf("expected product mismatch, got valid=%v reason=%s", result.Valid, result.Reason)
+	}
+	if sdkB.GetCachedLicenseKey() != "" {
+		t.Error("expected the wrong-product cache entry to be removed")
+	}
+
+	// A's own cache is untouched and still valid
+	result, _ = sdkA.CheckLicense(context.Background())
+	if !result.Valid {
+		t.Errorf("expected prod_A license to stay valid, got reason %s", result.Reason)
+	}
+}
+
+func TestSDKFeatureSet(t *testing.T) {
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+	})
+
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		Features:  []string{"pro", "export", "pro", " export ", ""},
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	set, err := sdk.FeatureSet(context.Background())
+	if err != nil {
+		t.Fatalf("FeatureSet failed: %v", err)
+	}
+	if len(set) != 2 || !set["pro"] || !set["export"] {
+		t.Errorf("FeatureSet = %v, want {pro, export}", set)
+	}
+	if set["analytics"] {
+		t.Error("expected missing feature to be false")
+	}
+}
+
+func TestSDKFeatureSetInvalidLicense(t *testing.T) {
+	sdk, _ := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+	})
+
+	set, err := sdk.FeatureSet(context.Background())
+	if err != nil {
+		t.Fatalf("FeatureSet failed: %v", err)
+	}
+	if set == nil || len(set) != 0 {
+		t.Errorf("FeatureSet = %v, want empty set", set)
+	}
+
+	otherMachine := "other_machine"
+	bound := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_bound",
+		ProductID: "prod_test",
+		Features:  []string{"pro"},
+		IssuedAt:  time.Now().UnixMilli(),
+		MachineID: &otherMachine,
+	})
+	if err := sdk.StoreLicense(bound); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	set, err = sdk.FeatureSet(context.Background())
+	if err != nil {
+		t.Fatalf("FeatureSet failed: %v", err)
+	}
+	if len(set) != 0 {
+		t.Errorf("FeatureSet = %v, want empty set for invalid license", set)
+	}
+}
+
+func newDeactivateSDK(t *testing.T, serverURL string) *SDK {
+	t.Helper()
+
+	sdk, err := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		APIBaseURL: serverURL,
+		StorageDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+	return sdk
+}
+
+func TestSDKDeactivate(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/licenses/deactivate" {
+			http.NotFound(w, r)
+			return
+		}
+		calls++
+		json.NewEncoder(w).Encode(map[string]any{"success": true})
+	}))
+	defer server.Close()
+
+	sdk := newDeactivateSDK(t, server.URL)
+	if err := sdk.Deactivate(context.Background()); err != nil {
+		t.Fatalf("Deactivate failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 deactivate call, got %d", calls)
+	}
+	if sdk.GetCachedLicenseKey() != "" {
+		t.Error("expected cached license to be cleared")
+	}
+
+	if err := sdk.Deactivate(context.Background()); !errors.Is(err, ErrNoLicense) {
+		t.Errorf("expected ErrNoLicense without a license, got %v", err)
+	}
+}
+
+func TestSDKDeactivateRejectedKeepsLicense(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]any{
+			"success": false,
+			"error":   map[string]any{"code": ErrCodeForbidden, "message": "deactivation limit reached"},
+		})
+	}))
+	defer server.Close()
+
+	sdk := newDeactivateSDK(t, server.URL)
+	err := sdk.Deactivate(context.Background())
+	if !IsCode(err, ErrCodeForbidden) {
+		t.Fatalf("expected FORBIDDEN error, got %v", err)
+	}
+	if sdk.GetCachedLicenseKey() == "" {
+		t.Error("expected cached license to be kept when the server rejects deactivation")
+	}
+}
+
+func TestSDKDeactivateOffline(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	sdk := newDeactivateSDK(t, server.URL)
+	err := sdk.Deactivate(context.Background())
+
+	var unreachable *ServerUnreachableError
+	if !errors.As(err, &unreachable) {
+		t.Fatalf("expected *ServerUnreachableError, got %v", err)
+	}
+	if sdk.GetCachedLicenseKey() != "" {
+		t.Error("expected cached license to be cleared locally")
+	}
+}
+
+func TestSDKDeactivateCancelledKeepsLicense(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	sdk := newDeactivateSDK(t, server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := sdk.Deactivate(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	var unreachable *ServerUnreachableError
+	if errors.As(err, &unreachable) {
+		t.Error("expected a cancelled deactivation not to be reported as unreachable")
+	}
+	if sdk.GetCachedLicenseKey() == "" {
+		t.Error("expected cached license to be kept when deactivation is cancelled")
+	}
+}
+
+// newRebindServer serves rebind requests, re-issuing lic_test for the
+// requesting machine.
+func newRebindServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/licenses/rebind" {
+			http.NotFound(w, r)
+			return
+		}
+		var req RebindRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		license := generateTestLicenseForSDK(t, LicensePayload{
+			LicenseID: "lic_test",
+			ProductID: "prod_test",
+			IssuedAt:  time.Now().UnixMilli(),
+			MachineID: &req.MachineFingerprint,
+		})
+		json.NewEncoder(w).Encode(map[string]any{
+			"success": true,
+			"data":    map[string]any{"licenseKey": license},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSDKRebindMachine(t *testing.T) {
+	sdk := newDeactivateSDK(t, newRebindServer(t).URL)
+	if err := sdk.RebindMachine(context.Background()); err != nil {
+		t.Fatalf("RebindMachine failed: %v", err)
+	}
+
+	payload, err := ExtractLicensePayload(sdk.GetCachedLicenseKey())
+	if err != nil {
+		t.Fatalf("extract rebound license: %v", err)
+	}
+	if payload.MachineID == nil || *payload.MachineID != sdk.GetMachineFingerprint() {
+		t.Errorf("expected the stored license to be bound to this machine, got %v", payload.MachineID)
+	}
+}
+
+func TestSDKCheckLicenseThenRebindMachine(t *testing.T) {
+	sdk := newDeactivateSDK(t, newRebindServer(t).URL)
+	otherMachine := "other_machine"
+	if err := sdk.StoreLicense(generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_test",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+		MachineID: &otherMachine,
+	})); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	result, err := sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if result.Reason != ReasonMachineMismatch {
+		t.Fatalf("expected machine_mismatch, got %+v", result)
+	}
+
+	// The mismatched license must still be there to rebind
+	if err := sdk.RebindMachine(context.Background()); err != nil {
+		t.Fatalf("RebindMachine failed: %v", err)
+	}
+	result, err = sdk.CheckLicense(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLicense failed: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("expected the rebound license to be valid, got %+v", result)
+	}
+}
+
+func TestSDKRebindMachineNotAllowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]any{
+			"success": false,
+			"error":   map[string]any{"code": ErrCodeRebindDenied, "message": "rebinding is disabled for this product"},
+		})
+	}))
+	defer server.Close()
+
+	sdk := newDeactivateSDK(t, server.URL)
+	license := sdk.GetCachedLicenseKey()
+
+	err := sdk.RebindMachine(context.Background())
+	var denied *RebindNotAllowedError
+	if !errors.As(err, &denied) {
+		t.Fatalf("expected *RebindNotAllowedError, got %v", err)
+	}
+	if !IsCode(err, ErrCodeRebindDenied) {
+		t.Errorf("expected the API error to be wrapped, got %v", err)
+	}
+	if sdk.GetCachedLicenseKey() != license {
+		t.Error("expected cached license to be kept when rebinding is refused")
+	}
+
+	if err := sdk.ClearLicense(); err != nil {
+		t.Fatalf("ClearLicense failed: %v", err)
+	}
+	if err := sdk.RebindMachine(context.Background()); !errors.Is(err, ErrNoLicense) {
+		t.Errorf("expected ErrNoLicense without a license, got %v", err)
+	}
+}
+
+func TestSDKExportImportRoundTrip(t *testing.T) {
+	machineID := "machine_old"
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_export",
+		ProductID: "prod_test",
+		Features:  []string{"pro"},
+		IssuedAt:  time.Now().UnixMilli(),
+		MachineID: &machineID,
+	})
+
+	old := newOfflineSDK(t, Config{})
+	if _, err := old.ExportLicense(); !errors.Is(err, ErrNoLicense) {
+		t.Fatalf("expected ErrNoLicense before storing, got %v", err)
+	}
+	if err := old.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+	exported, err := old.ExportLicense()
+	if err != nil {
+		t.Fatalf("ExportLicense failed: %v", err)
+	}
+
+	fresh := newOfflineSDK(t, Config{})
+	payload, err := fresh.ImportLicense(exported)
+	if err != nil {
+		t.Fatalf("ImportLicense failed: %v", err)
+	}
+	if fresh.GetCachedLicenseKey() != license {
+		t.Error("expected imported license to be stored")
+	}
+	if payload.LicenseID != "lic_export" || payload.MachineID == nil || *payload.MachineID != machineID {
+		t.Errorf("expected payload with machine binding, got %+v", payload)
+	}
+}
+
+func TestSDKImportLicenseRejectsBadKeys(t *testing.T) {
+	otherProduct := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_other",
+		ProductID: "prod_other",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	valid := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_tampered",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	tampered := valid[:len(valid)-4] + "AAAA"
+	revoked := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_revoked",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	tests := []struct {
+		name    string
+		license string
+		want    error
+	}{
+		{"wrong product", otherProduct, ErrProductMismatch},
+		{"bad format", "not-a-license", ErrInvalidFormat},
+		{"bad signature", tampered, ErrInvalidSignature},
+		{"revoked", revoked, ErrRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newOfflineSDK(t, Config{RevokedLicenseIDs: []string{"lic_revoked"}})
+			if _, err := sdk.ImportLicense(tt.license); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			if sdk.GetCachedLicenseKey() != "" {
+				t.Error("expected nothing to be stored")
+			}
+		})
+	}
+}
+
+func TestBrowserCommandKeepsURLIntact(t *testing.T) {
+	url := "https://checkout.example.com/pay?session=cs_123&email=a%40b.com&plan=pro"
+	tests := []struct {
+		goos string
+		args []string
+	}{
+		{"darwin", []string{"open", url}},
+		{"linux", []string{"xdg-open", url}},
+		{"windows", []string{"rundll32", "url.dll,FileProtocolHandler", url}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			cmd := browserCommand(tt.goos, url)
+			if !reflect.DeepEqual(cmd.Args, tt.args) {
+				t.Errorf("expected args %q, got %q", tt.args, cmd.Args)
+			}
+		})
+	}
+}
+
+func TestBrowserCommandWindows(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("requires Windows")
+	}
+
+	url := "https://checkout.example.com/pay?session=cs_123&plan=pro"
+	cmd := browserCommand(runtime.GOOS, url)
+	if cmd.Err != nil {
+		t.Fatalf("expected rundll32 to be found: %v", cmd.Err)
+	}
+	if !strings.Contains(cmd.String(), url) {
+		t.Errorf("expected URL to be passed intact, got %s", cmd.String())
+	}
+}
+
+func TestSDKStorageDirEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(StorageDirEnv, dir)
+
+	sdk, err := New(Config{
+		ProductID:  "prod_test",
+		PublicKey:  testPublicKeyHex,
+		StorageDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if got := sdk.GetStorage().GetStorageDir(); got != dir {
+		t.Errorf("expected %s to override StorageDir, got %s", StorageDirEnv, got)
+	}
+}
+
+func TestSDKCacheNamespace(t *testing.T) {
+	dir := t.TempDir()
+	newTenantSDK := func(namespace string) *SDK {
+		sdk, err := New(Config{
+			ProductID:      "prod_test",
+			PublicKey:      testPublicKeyHex,
+			StorageDir:     dir,
+			CacheNamespace: namespace,
+		})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		return sdk
+	}
+	tenantA, tenantB, plain := newTenantSDK("tenant-a"), newTenantSDK("tenant-b"), newTenantSDK("")
+
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_tenant_a",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	if err := tenantA.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+
+	pathA := tenantA.GetStorage().getLicenseFilePath("prod_test")
+	pathB := tenantB.GetStorage().getLicenseFilePath("prod_test")
+	if pathA == pathB {
+		t.Fatalf("expected tenants to use different files, both use %s", pathA)
+	}
+	if _, err := os.Stat(pathA); err != nil {
+		t.Errorf("expected tenant A's cache file: %v", err)
+	}
+	if tenantB.GetCachedLicenseKey() != "" || plain.GetCachedLicenseKey() != "" {
+		t.Error("expected other namespaces not to see tenant A's license")
+	}
+
+	// No namespace keeps the plain per-product filename
+	hash := sha256.Sum256([]byte("prod_test"))
+	if got := plain.GetStorage().getLicenseFilePath("prod_test"); filepath.Base(got) != hex.EncodeToString(hash[:])+".json" {
+		t.Errorf("expected the unnamespaced filename to be unchanged, got %s", got)
+	}
+}
+
+func TestSDKLicenseFromEnv(t *testing.T) {
+	valid := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_env",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Setenv(DefaultLicenseKeyEnv, valid)
+		sdk := newOfflineSDK(t, Config{})
+
+		result, err := sdk.CheckLicense(context.Background())
+		if err != nil {
+			t.Fatalf("CheckLicense failed: %v", err)
+		}
+		if !result.Valid || result.License.ID != "lic_env" {
+			t.Fatalf("expected the license from the environment, got %+v", result)
+		}
+		if sdk.GetCachedLicenseKey() != "" {
+			t.Error("expected the environment license not to be cached")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv(DefaultLicenseKeyEnv, valid[:len(valid)-4]+"AAAA")
+		sdk := newOfflineSDK(t, Config{})
+
+		result, err := sdk.CheckLicense(context.Background())
+		if err != nil {
+			t.Fatalf("CheckLicense failed: %v", err)
+		}
+		if result.Valid || result.Reason != ReasonInvalidSignature {
+			t.Errorf("expected invalid signature, got %+v", result)
+		}
+	})
+
+	t.Run("custom name and caching", func(t *testing.T) {
+		t.Setenv("MYAPP_LICENSE", valid)
+		sdk := newOfflineSDK(t, Config{LicenseKeyEnv: "MYAPP_LICENSE", CacheEnvLicense: true})
+
+		result, err := sdk.CheckLicense(context.Background())
+		if err != nil || !result.Valid {
+			t.Fatalf("expected a valid license, got %+v, %v", result, err)
+		}
+		if sdk.GetCachedLicenseKey() != valid {
+			t.Error("expected CacheEnvLicense to cache the license")
+		}
+	})
+}
+
+func TestSDKLicenseFromEnvPrecedence(t *testing.T) {
+	cachedLicense := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_cached",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	envLicense := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_env",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	t.Setenv(DefaultLicenseKeyEnv, envLicense)
+
+	tests := []struct {
+		precedence bool
+		want       string
+	}{
+		{false, "lic_cached"},
+		{true, "lic_env"},
+	}
+	for _, tt := range tests {
+		sdk := newOfflineSDK(t, Config{EnvLicensePrecedence: tt.precedence})
+		if err := sdk.StoreLicense(cachedLicense); err != nil {
+			t.Fatalf("StoreLicense failed: %v", err)
+		}
+
+		result, err := sdk.CheckLicense(context.Background())
+		if err != nil || !result.Valid {
+			t.Fatalf("expected a valid license, got %+v, %v", result, err)
+		}
+		if result.License.ID != tt.want {
+			t.Errorf("precedence=%v: expected %s, got %s", tt.precedence, tt.want, result.License.ID)
+		}
+		if sdk.GetCachedLicenseKey() != cachedLicense {
+			t.Errorf("precedence=%v: expected the cached license to be kept", tt.precedence)
+		}
+	}
+}
+
+func TestSDKInvalidEnvLicenseKeepsCache(t *testing.T) {
+	cachedLicense := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_cached",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	otherProduct := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_other",
+		ProductID: "prod_other",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	for name, envLicense := range map[string]string{
+		"malformed":     "not-a-license",
+		"wrong product": otherProduct,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(DefaultLicenseKeyEnv, envLicense)
+			sdk := newOfflineSDK(t, Config{EnvLicensePrecedence: true, CacheEnvLicense: true})
+			if err := sdk.StoreLicense(cachedLicense); err != nil {
+				t.Fatalf("StoreLicense failed: %v", err)
+			}
+
+			result, err := sdk.CheckLicense(context.Background())
+			if err != nil {
+				t.Fatalf("CheckLicense failed: %v", err)
+			}
+			if result.Valid {
+				t.Errorf("expected the env license to be rejected, got %+v", result)
+			}
+			if sdk.GetCachedLicenseKey() != cachedLicense {
+				t.Error("expected an invalid env license to leave the cached license alone")
+			}
+		})
+	}
+}
+
+// saveStaleCache stores a license whose cache entry is already due for refresh.
+func saveStaleCache(t *testing.T, sdk *SDK, license string) {
+	t.Helper()
+
+	storage := sdk.GetStorage()
+	if err := storage.Save(sdk.config.ProductID, license, sdk.GetMachineFingerprint()); err != nil {
+		t.Fatalf("save cache: %v", err)
+	}
+
+	cached, err := storage.Load(sdk.config.ProductID)
+	if err != nil || cached == nil {
+		t.Fatalf("load cache: %v", err)
+	}
+	cached.RefreshAt = time.Now().UnixMilli() - 1000
+
+	data, _ := json.Marshal(cached)
+	if err := os.WriteFile(storage.getLicenseFilePath(sdk.config.ProductID), data, 0600); err != nil {
+		t.Fatalf("write cache: %v", err)
+	}
+}
+
 // generateTestLicenseForSDK generates a test license (duplicate of generateTestLicense for test file separation)
 func generateTestLicenseForSDK(t *testing.T, payload LicensePayload) string {
 	t.Helper()
@@ -1,27 +1,130 @@
 package tuish
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // Config contains the SDK configuration options.
 type Config struct {
 	// ProductID for this application (required)
 	ProductID string
 
+	// ProductName is shown as the license's product name when it was
+	// verified offline, since license keys don't carry it. A name from the
+	// API takes precedence when the license is checked online.
+	ProductName string
+
 	// PublicKey is the Ed25519 public key for offline license verification.
-	// Accepts SPKI base64 (MCow...) or 64-character hex format.
+	// Accepts PEM, SPKI base64 (MCow...) or 64-character hex format.
+	// See PublicKeyFromFile and PublicKeyFromEnv to load it at startup.
 	PublicKey string
 
 	// APIBaseURL is the API base URL (defaults to production)
 	APIBaseURL string
 
+	// APIVersion is the API version used in request paths and the
+	// X-API-Version header (defaults to "v1")
+	APIVersion string
+
 	// APIKey for authenticated requests (optional, used for license validation)
 	APIKey string
 
-	// StorageDir is the custom storage directory (defaults to ~/.tuish/licenses/)
+	// StorageDir is the custom storage directory (defaults to
+	// $XDG_DATA_HOME/tuish/licenses/ on Linux when set, else ~/.tuish/licenses/).
+	// The TUISH_STORAGE_DIR environment variable takes precedence.
 	StorageDir string
 
+	// Store overrides where cached licenses are kept (defaults to files in
+	// StorageDir). See NewMemoryStore for an in-memory alternative.
+	Store LicenseStore
+
+	// LegacyCacheFilenames names cache files with the truncated hash from the
+	// cache spec, for sharing a cache directory with other tuish SDKs.
+	// By default the full SHA256 hex is used, a Go-only extension of the
+	// spec. A legacy file is copied to the full name and left in place, and
+	// removing the cached license deletes both.
+	LegacyCacheFilenames bool
+
+	// CacheNamespace keeps separate license caches for the same product in
+	// one storage directory, e.g. one per tenant when embedding a product
+	// for several customers. It is folded into the cache filename; empty
+	// keeps the plain per-product filenames. It has no effect with Store.
+	CacheNamespace string
+
+	// RevokedLicenseIDs are license IDs to treat as revoked during offline
+	// verification, for installs that can't reach the validation endpoint.
+	RevokedLicenseIDs []string
+
+	// RevocationListFile is the path to a revocation list signed with the
+	// product key (see ParseRevocationList). Its IDs are added to
+	// RevokedLicenseIDs; New fails if it can't be read or verified.
+	RevocationListFile string
+
+	// MaxOfflineAge caps how long a cached license is trusted without a
+	// successful online validation, measured from when it was cached. Once
+	// exceeded, checks fail with ReasonOfflineExpired until the server can
+	// be reached. Zero means no limit.
+	MaxOfflineAge time.Duration
+
+	// OfflineOnly marks an install that isn't expected to validate online.
+	// Perpetual licenses are then exempt from MaxOfflineAge.
+	OfflineOnly bool
+
+	// DisableOnline turns off online validation, for integrations without a
+	// reachable API. License checks verify offline only, as with SkipOnline,
+	// and report NetworkChecked false rather than a network error, and
+	// ValidateOnline returns ErrOnlineDisabled without making a request.
+	DisableOnline bool
+
+	// AlwaysOnline revalidates the license with the server on every check,
+	// as ForceOnline does, instead of trusting a fresh cache. The cached
+	// license is only a fallback for when the server can't be reached; the
+	// result then has Source "offline" and StaleOffline set. DisableOnline
+	// and a per-call SkipOnline take precedence.
+	AlwaysOnline bool
+
+	// IgnoreMachineBinding skips the machine check in offline verification,
+	// treating every license as floating, e.g. for team licenses issued
+	// node-locked before they became floating. A license key copied to any
+	// number of machines then verifies on all of them, so only set it when
+	// the server enforces seat limits or none are wanted. Online validation
+	// still sends the machine fingerprint.
+	IgnoreMachineBinding bool
+
+	// LicenseKeyEnv names the environment variable a license key can be
+	// passed in, e.g. by CI or a container (defaults to TUISH_LICENSE_KEY).
+	// It is verified like a cached license and used when no license is
+	// cached. Unless CacheEnvLicense is set it is never written to disk.
+	LicenseKeyEnv string
+
+	// EnvLicensePrecedence uses the license from LicenseKeyEnv even when a
+	// different license is cached, instead of only as a fallback.
+	EnvLicensePrecedence bool
+
+	// CacheEnvLicense stores the license from LicenseKeyEnv in the cache,
+	// so later runs find it without the environment variable. Only a
+	// license that verifies offline is stored.
+	CacheEnvLicense bool
+
+	// TrialSecret is mixed into the key that signs StartTrial's marker, e.g.
+	// a value baked in at build time with -ldflags -X, so a marker can't be
+	// forged from the files in the storage directory.
+	TrialSecret string
+
+	// OnCheck is called with the final result of every CheckLicense and
+	// CheckLicenseWith call, and where it came from ("offline", "online" or
+	// "not_found"), e.g. to record anonymized outcomes in analytics. The
+	// result never includes the license key. It runs on the checking
+	// goroutine after the result is final, so it should return quickly.
+	OnCheck func(result *LicenseCheckResult, source string)
+
 	// Debug enables debug logging
 	Debug bool
+
+	// Logger receives debug output when Debug is true (defaults to stderr).
+	// License keys are redacted before they reach the logger.
+	Logger func(format string, args ...any)
 }
 
 // LicenseCheckResult contains the result of a license check.
@@ -37,8 +140,42 @@ type LicenseCheckResult struct {
 
 	// OfflineVerified indicates whether the license was verified offline
 	OfflineVerified bool `json:"offlineVerified"`
+
+	// StaleOffline indicates the cache was due for a refresh but the server
+	// couldn't be reached, so the offline result was trusted instead
+	StaleOffline bool `json:"staleOffline,omitempty"`
+
+	// Source indicates where the final result came from
+	Source LicenseSource `json:"source,omitempty"`
+
+	// NetworkChecked indicates the check made an online validation request,
+	// whether or not it succeeded
+	NetworkChecked bool `json:"networkChecked,omitempty"`
+
+	// IsTrial indicates the result comes from a local trial started with
+	// StartTrial rather than an issued license
+	IsTrial bool `json:"isTrial,omitempty"`
 }
 
+// ExpiringSoon reports whether the license is valid but expires within the
+// given duration. Perpetual and already-expired licenses are never "soon".
+func (r *LicenseCheckResult) ExpiringSoon(within time.Duration) bool {
+	if r == nil || !r.Valid || r.License == nil || r.License.ExpiresAt == nil {
+		return false
+	}
+	remaining := time.UnixMilli(*r.License.ExpiresAt).Sub(nowFunc())
+	return remaining > 0 && remaining <= within
+}
+
+// LicenseSource identifies where a license check result came from.
+type LicenseSource string
+
+const (
+	LicenseSourceOffline  LicenseSource = "offline"
+	LicenseSourceOnline   LicenseSource = "online"
+	LicenseSourceNotFound LicenseSource = "not_found"
+)
+
 // LicenseDetails contains license information.
 type LicenseDetails struct {
 	// ID is the license ID
@@ -63,6 +200,18 @@ type LicenseDetails struct {
 	ExpiresAt *int64 `json:"expiresAt"`
 }
 
+// DaysUntilExpiry returns the number of days until the license expires,
+// rounded up, or nil for a perpetual license. An expired license returns
+// zero or a negative number.
+func (d *LicenseDetails) DaysUntilExpiry() *int {
+	if d.ExpiresAt == nil {
+		return nil
+	}
+	remaining := time.UnixMilli(*d.ExpiresAt).Sub(nowFunc())
+	days := int(math.Ceil(remaining.Hours() / 24))
+	return &days
+}
+
 // LicenseStatus represents the status of a license.
 type LicenseStatus string
 
@@ -70,8 +219,25 @@ const (
 	LicenseStatusActive  LicenseStatus = "active"
 	LicenseStatusExpired LicenseStatus = "expired"
 	LicenseStatusRevoked LicenseStatus = "revoked"
+
+	// LicenseStatusInvalid marks a license that can't be used here, such as
+	// one with a bad signature or bound to another machine or product
+	LicenseStatusInvalid LicenseStatus = "invalid"
 )
 
+// statusForReason returns the license status that matches why a license
+// failed verification.
+func statusForReason(reason LicenseInvalidReason) LicenseStatus {
+	switch reason {
+	case ReasonExpired:
+		return LicenseStatusExpired
+	case ReasonRevoked:
+		return LicenseStatusRevoked
+	default:
+		return LicenseStatusInvalid
+	}
+}
+
 // LicenseInvalidReason represents why a license is invalid.
 type LicenseInvalidReason string
 
@@ -82,7 +248,9 @@ const (
 	ReasonInvalidFormat    LicenseInvalidReason = "invalid_format"
 	ReasonInvalidSignature LicenseInvalidReason = "invalid_signature"
 	ReasonMachineMismatch  LicenseInvalidReason = "machine_mismatch"
+	ReasonProductMismatch  LicenseInvalidReason = "product_mismatch"
 	ReasonNetworkError     LicenseInvalidReason = "network_error"
+	ReasonOfflineExpired   LicenseInvalidReason = "offline_expired"
 )
 
 // LicenseHeader is the header portion of a signed license.
@@ -122,6 +290,16 @@ type CheckoutStatus struct {
 
 	// License details when complete
 	License *LicenseDetails `json:"license,omitempty"`
+
+	// AmountPaid is the amount charged in the currency's smallest unit
+	// (e.g. cents), present when complete
+	AmountPaid int `json:"amountPaid,omitempty"`
+
+	// Currency is the ISO 4217 code of AmountPaid
+	Currency string `json:"currency,omitempty"`
+
+	// ReceiptURL links to the payment receipt, present when complete
+	ReceiptURL string `json:"receiptUrl,omitempty"`
 }
 
 // OtpRequestResult is returned when requesting an OTP.
@@ -134,6 +312,19 @@ type OtpRequestResult struct {
 
 	// ExpiresIn is seconds until OTP expires
 	ExpiresIn int `json:"expiresIn"`
+
+	// RequestedAt is when the OTP was requested (set by the client)
+	RequestedAt time.Time `json:"-"`
+}
+
+// ExpiresAt returns when the OTP expires, for counting down to a resend.
+func (r *OtpRequestResult) ExpiresAt() time.Time {
+	return r.RequestedAt.Add(time.Duration(r.ExpiresIn) * time.Second)
+}
+
+// Expired reports whether the OTP has expired and a new one can be requested.
+func (r *OtpRequestResult) Expired() bool {
+	return !nowFunc().Before(r.ExpiresAt())
 }
 
 // LoginResult is returned after successful login.
@@ -198,6 +389,10 @@ type PurchaseConfirmResult struct {
 	// ActionURL for 3DS
 	ActionURL string `json:"actionUrl,omitempty"`
 
+	// SessionID identifies the pending purchase when RequiresAction is set;
+	// pass it to SDK.ContinueAfterAction once the customer has finished
+	SessionID string `json:"sessionId,omitempty"`
+
 	// Error message if failed
 	Error string `json:"error,omitempty"`
 }
@@ -222,7 +417,29 @@ type CachedLicenseData struct {
 
 // NeedsRefresh returns true if the cache should be refreshed.
 func (c *CachedLicenseData) NeedsRefresh() bool {
-	return time.Now().UnixMilli() >= c.RefreshAt
+	return nowFunc().UnixMilli() >= c.RefreshAt
+}
+
+// NextRefresh returns when the cached license is next revalidated online.
+// It is in the past if a refresh is already due.
+func (c *CachedLicenseData) NextRefresh() time.Time {
+	return time.UnixMilli(c.RefreshAt)
+}
+
+// CacheInfo describes the refresh schedule of a cached license, for status
+// screens such as "Last verified 2h ago, next check in 22h".
+type CacheInfo struct {
+	// CachedAt is when the license was cached or last revalidated
+	CachedAt time.Time
+
+	// RefreshAt is when the license is next revalidated online
+	RefreshAt time.Time
+
+	// NeedsRefresh is true once RefreshAt has passed
+	NeedsRefresh bool
+
+	// Age is how long ago the license was cached
+	Age time.Duration
 }
 
 // ValidateRequest is sent to the API for license validation.
@@ -231,6 +448,23 @@ type ValidateRequest struct {
 	MachineFingerprint string `json:"machineFingerprint"`
 }
 
+// DeactivateRequest is sent to the API to release a machine binding.
+type DeactivateRequest struct {
+	LicenseKey         string `json:"licenseKey"`
+	MachineFingerprint string `json:"machineFingerprint"`
+}
+
+// RebindRequest is sent to the API to move a license's machine binding.
+type RebindRequest struct {
+	LicenseKey         string `json:"licenseKey"`
+	MachineFingerprint string `json:"machineFingerprint"`
+}
+
+// RebindResponse is returned from the API with the re-issued license.
+type RebindResponse struct {
+	LicenseKey string `json:"licenseKey"`
+}
+
 // ValidateResponse is returned from the API for license validation.
 type ValidateResponse struct {
 	Valid   bool            `json:"valid"`
@@ -0,0 +1,71 @@
+package tuish
+
+import (
+	"testing"
+	"time"
+)
+
+func expiringResult(valid bool, expiresIn time.Duration) *LicenseCheckResult {
+	expiresAt := time.Now().Add(expiresIn).UnixMilli()
+	return &LicenseCheckResult{
+		Valid:   valid,
+		License: &LicenseDetails{ID: "lic_test", ExpiresAt: &expiresAt},
+	}
+}
+
+func TestLicenseDetailsDaysUntilExpiry(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		want      int
+	}{
+		{"in ten days", 10*24*time.Hour - time.Minute, 10},
+		{"in a few hours", 3 * time.Hour, 1},
+		{"just over a day", 24*time.Hour + time.Hour, 2},
+		{"expired an hour ago", -time.Hour, 0},
+		{"expired two days ago", -(2*24*time.Hour + time.Hour), -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days := expiringResult(true, tt.expiresIn).License.DaysUntilExpiry()
+			if days == nil {
+				t.Fatal("expected days for an expiring license")
+			}
+			if *days != tt.want {
+				t.Errorf("expected %d days, got %d", tt.want, *days)
+			}
+		})
+	}
+
+	perpetual := &LicenseDetails{ID: "lic_test"}
+	if days := perpetual.DaysUntilExpiry(); days != nil {
+		t.Errorf("expected nil for perpetual license, got %d", *days)
+	}
+}
+
+func TestLicenseCheckResultExpiringSoon(t *testing.T) {
+	week := 7 * 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		result *LicenseCheckResult
+		want   bool
+	}{
+		{"inside window", expiringResult(true, week-time.Minute), true},
+		{"outside window", expiringResult(true, week+time.Minute), false},
+		{"already expired", expiringResult(true, -time.Minute), false},
+		{"invalid license", expiringResult(false, time.Hour), false},
+		{"perpetual", &LicenseCheckResult{Valid: true, License: &LicenseDetails{ID: "lic_test"}}, false},
+		{"no license", &LicenseCheckResult{Valid: false}, false},
+		{"nil result", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.ExpiringSoon(week); got != tt.want {
+				t.Errorf("expected ExpiringSoon=%t, got %t", tt.want, got)
+			}
+		})
+	}
+}
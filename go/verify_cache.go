@@ -0,0 +1,102 @@
+package tuish
+
+import (
+	"container/list"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+const (
+	// verifyCacheSize is how many verified licenses are remembered.
+	verifyCacheSize = 16
+
+	// verifyCacheTTL is how long a signature check is reused.
+	verifyCacheTTL = time.Minute
+)
+
+// verifyCache is a small LRU of VerifyLicense results, so gating on a
+// feature every frame doesn't re-run ed25519.Verify each time. Entries are
+// keyed by a hash of the license and machine ID, so a changed license
+// always misses.
+type verifyCache struct {
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	order   *list.List
+}
+
+type verifyCacheEntry struct {
+	key      [sha256.Size]byte
+	result   *VerifyResult
+	cachedAt time.Time
+}
+
+func newVerifyCache() *verifyCache {
+	return &verifyCache{
+		entries: make(map[[sha256.Size]byte]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// verify returns VerifyLicense's result for the license, reusing a recent
+// one if it has it. Expiry is checked against the current time on every
+// call, so a cached license still expires on time.
+func (c *verifyCache) verify(licenseString string, publicKey ed25519.PublicKey, machineID string) *VerifyResult {
+	key := sha256.Sum256([]byte(licenseString + "\x00" + machineID))
+	now := nowFunc()
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*verifyCacheEntry)
+		if now.Sub(entry.cachedAt) < verifyCacheTTL {
+			c.order.MoveToFront(el)
+			result := entry.result
+			c.mu.Unlock()
+			return recheckExpiry(result, now)
+		}
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+
+	result := VerifyLicense(licenseString, publicKey, machineID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+	}
+	c.entries[key] = c.order.PushFront(&verifyCacheEntry{key: key, result: result, cachedAt: now})
+	for c.order.Len() > verifyCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*verifyCacheEntry).key)
+	}
+	return recheckExpiry(result, now)
+}
+
+// clear forgets every cached result.
+func (c *verifyCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[[sha256.Size]byte]*list.Element)
+	c.order.Init()
+}
+
+// recheckExpiry returns a copy of a cached result, as expired if the
+// license was valid when cached but has expired since. The copy keeps
+// callers from changing the cached payload.
+func recheckExpiry(result *VerifyResult, now time.Time) *VerifyResult {
+	fresh := *result
+	if result.Payload != nil {
+		payload := *result.Payload
+		payload.Features = append([]string(nil), payload.Features...)
+		fresh.Payload = &payload
+	}
+	if fresh.Valid && fresh.Payload != nil && fresh.Payload.ExpiresAt != nil && *fresh.Payload.ExpiresAt < now.UnixMilli() {
+		fresh.Valid = false
+		fresh.Reason = ReasonExpired
+	}
+	return &fresh
+}
@@ -0,0 +1,137 @@
+package tuish
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyCacheHonorsExpiry(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Second).UnixMilli()
+	license := generateTestLicense(t, LicensePayload{
+		LicenseID: "lic_soon",
+		ProductID: "prod_test",
+		IssuedAt:  now.UnixMilli(),
+		ExpiresAt: &expiresAt,
+	})
+	publicKey, err := ParsePublicKey(testPublicKeyHex)
+	if err != nil {
+		t.Fatalf("parse public key: %v", err)
+	}
+
+	setNow(t, now)
+	cache := newVerifyCache()
+	if result := cache.verify(license, publicKey, ""); !result.Valid {
+		t.Fatalf("expected valid license before expiry, got %s", result.Reason)
+	}
+
+	setNow(t, time.UnixMilli(expiresAt).Add(time.Millisecond))
+
+	// The entry is still well within its TTL, but the license has expired
+	result := cache.verify(license, publicKey, "")
+	if result.Valid || result.Reason != ReasonExpired {
+		t.Errorf("expected cached license to expire, got valid=%v reason=%s", result.Valid, result.Reason)
+	}
+}
+
+func TestVerifyCacheReturnsCopies(t *testing.T) {
+	license := generateTestLicense(t, LicensePayload{
+		LicenseID: "lic_copy",
+		ProductID: "prod_test",
+		Features:  []string{"pro"},
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+	publicKey, _ := ParsePublicKey(testPublicKeyHex)
+
+	cache := newVerifyCache()
+	cache.verify(license, publicKey, "").Payload.Features[0] = "changed on miss"
+	cache.verify(license, publicKey, "").Payload.Features[0] = "changed on hit"
+
+	if got := cache.verify(license, publicKey, "").Payload.Features[0]; got != "pro" {
+		t.Errorf("expected cached payload to be unchanged, got feature %q", got)
+	}
+}
+
+func TestVerifyCacheEvictsOldest(t *testing.T) {
+	publicKey, _ := ParsePublicKey(testPublicKeyHex)
+	cache := newVerifyCache()
+	for i := 0; i < verifyCacheSize+4; i++ {
+		cache.verify("not-a-license", publicKey, string(rune('a'+i)))
+	}
+	if got := cache.order.Len(); got != verifyCacheSize {
+		t.Errorf("expected %d entries, got %d", verifyCacheSize, got)
+	}
+}
+
+func TestSDKStoreAndClearLicenseResetVerifyCache(t *testing.T) {
+	sdk := newOfflineSDK(t, Config{})
+	license := generateTestLicenseForSDK(t, LicensePayload{
+		LicenseID: "lic_cache",
+		ProductID: "prod_test",
+		IssuedAt:  time.Now().UnixMilli(),
+	})
+
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+	if result := sdk.verifyOffline(license, sdk.GetMachineFingerprint()); !result.Valid {
+		t.Fatalf("expected valid license, got %s", result.Reason)
+	}
+	if sdk.verified.order.Len() != 1 {
+		t.Fatalf("expected the verification to be cached")
+	}
+
+	if err := sdk.ClearLicense(); err != nil {
+		t.Fatalf("ClearLicense failed: %v", err)
+	}
+	if sdk.verified.order.Len() != 0 {
+		t.Error("expected ClearLicense to empty the verification cache")
+	}
+
+	sdk.verifyOffline(license, sdk.GetMachineFingerprint())
+	if err := sdk.StoreLicense(license); err != nil {
+		t.Fatalf("StoreLicense failed: %v", err)
+	}
+	if sdk.verified.order.Len() != 0 {
+		t.Error("expected StoreLicense to empty the verification cache")
+	}
+}
+
+func BenchmarkVerifyCache(b *testing.B) {
+	future := time.Now().Add(24 * time.Hour).UnixMilli()
+	machineID := "machine_bench"
+	license := generateTestLicense(b, LicensePayload{
+		LicenseID:  "lic_bench",
+		ProductID:  "prod_test",
+		CustomerID: "cus_bench",
+		Features:   []string{"pro", "export", "analytics"},
+		IssuedAt:   time.Now().UnixMilli(),
+		ExpiresAt:  &future,
+		MachineID:  &machineID,
+	})
+
+	publicKey, err := ParsePublicKey(testPublicKeyHex)
+	if err != nil {
+		b.Fatalf("parse public key: %v", err)
+	}
+
+	b.Run("cached", func(b *testing.B) {
+		cache := newVerifyCache()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if result := cache.verify(license, publicKey, machineID); !result.Valid {
+				b.Fatalf("expected valid license, got %s", result.Reason)
+			}
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if result := VerifyLicense(license, publicKey, machineID); !result.Valid {
+				b.Fatalf("expected valid license, got %s", result.Reason)
+			}
+		}
+	})
+}